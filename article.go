@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+// writeArticle prints the sections of an article with their headings,
+// indenting deeper sections by two spaces per level. The text is wrapped
+// at width, 0 disables wrapping.
+func writeArticle(w io.Writer, title string, sections []wiki.ArticleSection, width int) {
+	activeTheme.Title.Fprintln(w, title)
+	for _, section := range sections {
+		indent := ""
+		if section.Level > 0 {
+			headingIndent := strings.Repeat("  ", section.Level-2)
+			fmt.Fprintln(w)
+			activeTheme.Summary.Fprintln(w, headingIndent+section.Title)
+			indent = headingIndent + "  "
+		}
+		if section.Text == "" {
+			continue
+		}
+		fmt.Fprintln(w)
+		for _, paragraph := range strings.Split(section.Text, "\n") {
+			lines := []string{paragraph}
+			if width > 0 {
+				lines = wrapText(paragraph, max(width-len(indent), 20))
+			}
+			for _, line := range lines {
+				if line == "" {
+					fmt.Fprintln(w)
+					continue
+				}
+				fmt.Fprintln(w, indent+line)
+			}
+		}
+	}
+}
+
+// runArticle prints the whole article, for -full.
+func runArticle(ctx context.Context, lang, title string, out io.Writer, width int) int {
+	stopLoading := startLoadingAnimation()
+	sections, err := newClient(lang).Article(ctx, title)
+	stopLoading()
+	truncated := errors.Is(err, wiki.ErrTooLarge) && len(sections) > 0
+	if err != nil && !truncated {
+		activeTheme.Error.Fprintf(os.Stderr, "Error fetching article: %v\n", err)
+		return exitCodeFor(err)
+	}
+	addHistoryEntry(lang, title)
+	writeArticle(out, title, sections, width)
+	if truncated {
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "[Article cut off after %d bytes, raise -max-bytes to read all of it]\n", maxBytes)
+	}
+	fmt.Fprintln(out)
+	activeTheme.URL.Fprintln(out, "URL:")
+	fmt.Fprintln(out, newClient(lang).ArticleURL(title))
+	return exitOK
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestWriteArticle(t *testing.T) {
+	sections := []wiki.ArticleSection{
+		{Level: 0, Text: "Berlin ist die Hauptstadt Deutschlands."},
+		{Level: 2, Title: "Geschichte"},
+		{Level: 3, Title: "Mittelalter", Text: "Berlin wurde 1237 erstmals erwähnt.\n\nDie Stadt wuchs."},
+	}
+
+	var output bytes.Buffer
+	writeArticle(&output, "Berlin", sections, 0)
+
+	want := "Berlin\n\nBerlin ist die Hauptstadt Deutschlands.\n\nGeschichte\n\n  Mittelalter\n\n    Berlin wurde 1237 erstmals erwähnt.\n\n    Die Stadt wuchs.\n"
+	if output.String() != want {
+		t.Errorf("Erwartete\n%s\nerhielt\n%s", want, output.String())
+	}
+}
+
+func TestWriteArticleWrapsIndented(t *testing.T) {
+	sections := []wiki.ArticleSection{{Level: 2, Title: "Lage", Text: "eins zwei drei vier fünf sechs sieben acht neun zehn"}}
+	var output bytes.Buffer
+	writeArticle(&output, "Berlin", sections, 24)
+
+	want := "Berlin\n\nLage\n\n  eins zwei drei vier\n  fünf sechs sieben acht\n  neun zehn\n"
+	if output.String() != want {
+		t.Errorf("Erwartete\n%q\nerhielt\n%q", want, output.String())
+	}
+}
+
+func TestRunFullMaxBytes(t *testing.T) {
+	setTestHome(t)
+
+	// Ein Artikel von etwa 1 MB hinter der Einleitung
+	article := `{"parse": {"title": "Riesig", "text": "<p>Anfang des Artikels.</p><h2>Teil</h2><p>` +
+		strings.Repeat("Sehr langer Text. ", 60000) + `</p>"}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("list") == "search" {
+			fmt.Fprint(w, `{"query": {"search": [{"title": "Riesig"}]}}`)
+			return
+		}
+		fmt.Fprint(w, article)
+	}))
+	defer server.Close()
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	var code int
+	stdout, _ := captureOutput(t, func() {
+		code = run([]string{"-full", "-max-bytes", "10000", "Riesig"})
+	})
+	if code != exitOK {
+		t.Fatalf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+	if !strings.Contains(stdout, "Anfang des Artikels.") || !strings.Contains(stdout, "Article cut off after 10000 bytes") {
+		t.Errorf("Der Anfang und ein Hinweis sollten erscheinen: %.200q", stdout)
+	}
+	if len(stdout) > 12000 {
+		t.Errorf("Die Ausgabe sollte am Limit enden, %d Bytes", len(stdout))
+	}
+
+	stdout, _ = captureOutput(t, func() {
+		code = run([]string{"-full", "-max-bytes", "0", "Riesig"})
+	})
+	if code != exitOK || strings.Contains(stdout, "cut off") || len(stdout) < 1000000 {
+		t.Errorf("Mit -max-bytes 0 sollte der ganze Artikel kommen: %d, %d Bytes", code, len(stdout))
+	}
+
+	_, stderr := captureOutput(t, func() {
+		code = run([]string{"-max-bytes", "-1", "Riesig"})
+	})
+	if code != exitUsage || !strings.Contains(stderr, "-max-bytes must not be negative") {
+		t.Errorf("Ein negatives Limit sollte ein Bedienfehler sein: %d, %q", code, stderr)
+	}
+}
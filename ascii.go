@@ -0,0 +1,81 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// asciiReplacements spells out the characters of German and other Latin
+// texts in ASCII. Umlauts follow the German convention, other accented
+// letters lose their accent.
+var asciiReplacements = map[rune]string{
+	'ä': "ae", 'ö': "oe", 'ü': "ue", 'Ä': "Ae", 'Ö': "Oe", 'Ü': "Ue", 'ß': "ss", 'ẞ': "SS",
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'å': "a", 'æ': "ae", 'ç': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+	'ñ': "n", 'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ø': "o", 'œ': "oe",
+	'ù': "u", 'ú': "u", 'û': "u", 'ý': "y", 'ÿ': "y",
+	'À': "A", 'Á': "A", 'Â': "A", 'Ã': "A", 'Å': "A", 'Æ': "Ae", 'Ç': "C",
+	'È': "E", 'É': "E", 'Ê': "E", 'Ë': "E", 'Ì': "I", 'Í': "I", 'Î': "I", 'Ï': "I",
+	'Ñ': "N", 'Ò': "O", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ø': "O", 'Œ': "Oe",
+	'Ù': "U", 'Ú': "U", 'Û': "U", 'Ý': "Y",
+	'„': "\"", '“': "\"", '”': "\"", '«': "\"", '»': "\"",
+	'‚': "'", '‘': "'", '’': "'", '‹': "'", '›': "'",
+	'–': "-", '—': "-", '…': "...", '·': "*", '•': "*", ' ': " ",
+	'°': " deg", '€': "EUR", '×': "x",
+}
+
+// transliterate replaces every non-ASCII character of s, with "?" for the
+// ones that have no ASCII spelling.
+func transliterate(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r < utf8.RuneSelf:
+			b.WriteRune(r)
+		case asciiReplacements[r] != "":
+			b.WriteString(asciiReplacements[r])
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// asciiWriter transliterates everything written to out, set up by -ascii
+// for terminals that cannot display UTF-8. The output writers write whole
+// strings, so a character is never split between two writes.
+type asciiWriter struct {
+	out io.Writer
+}
+
+func (w asciiWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.out, transliterate(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// utf8Locale reports whether the locale in the environment uses UTF-8. The
+// first of LC_ALL, LC_CTYPE and LANG that is set decides; known is false
+// when none is set, e.g. on Windows.
+func utf8Locale(getenv func(string) string) (isUTF8, known bool) {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := getenv(name); value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8"), true
+		}
+	}
+	return false, false
+}
+
+// warnNonUTF8Terminal suggests -ascii when stdout is a terminal whose
+// locale is known not to use UTF-8.
+func warnNonUTF8Terminal() {
+	if isUTF8, known := utf8Locale(os.Getenv); known && !isUTF8 && term.IsTerminal(int(os.Stdout.Fd())) {
+		logger.Warn("the terminal locale is not UTF-8, use -ascii if characters look garbled")
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestTransliterate(t *testing.T) {
+	tests := map[string]string{
+		"Berlin":            "Berlin",
+		"Müller aß Äpfel":   "Mueller ass Aepfel",
+		"Öl, Übung, Straße": "Oel, Uebung, Strasse",
+		"Café Crème":        "Cafe Creme",
+		"„Zitat“ – 3 € …":   "\"Zitat\" - 3 EUR ...",
+		"東京":                "??",
+	}
+	for input, want := range tests {
+		if got := transliterate(input); got != want {
+			t.Errorf("transliterate(%q) = %q, erwartet %q", input, got, want)
+		}
+	}
+}
+
+func TestASCIIWriter(t *testing.T) {
+	var output bytes.Buffer
+	n, err := fmt.Fprint(asciiWriter{out: &output}, "Köln")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Gemeldet wird die Länge der Eingabe, nicht der Ausgabe
+	if n != len("Köln") {
+		t.Errorf("Write meldete %d Bytes, erwartet %d", n, len("Köln"))
+	}
+	if output.String() != "Koeln" {
+		t.Errorf("Erwartete 'Koeln', erhielt %q", output.String())
+	}
+}
+
+func TestUTF8Locale(t *testing.T) {
+	tests := []struct {
+		env                 map[string]string
+		wantUTF8, wantKnown bool
+	}{
+		{map[string]string{"LANG": "de_DE.UTF-8"}, true, true},
+		{map[string]string{"LANG": "en_US.utf8"}, true, true},
+		{map[string]string{"LC_ALL": "C", "LANG": "de_DE.UTF-8"}, false, true},
+		{map[string]string{"LC_CTYPE": "de_DE.ISO-8859-1"}, false, true},
+		{map[string]string{}, false, false},
+	}
+	for _, test := range tests {
+		isUTF8, known := utf8Locale(func(name string) string { return test.env[name] })
+		if isUTF8 != test.wantUTF8 || known != test.wantKnown {
+			t.Errorf("%v: erhielt %v, %v, erwartet %v, %v", test.env, isUTF8, known, test.wantUTF8, test.wantKnown)
+		}
+	}
+}
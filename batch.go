@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// defaultConcurrency is the number of lookups -batch runs in parallel
+// unless -concurrency says otherwise.
+const defaultConcurrency = 4
+
+// clampConcurrency makes sure at least one worker runs.
+func clampConcurrency(workers int) int {
+	if workers < 1 {
+		return 1
+	}
+	return workers
+}
+
+type batchItem struct {
+	Term   string
+	Result Result
+	Err    error
+}
+
+// readBatchTerms reads one search term per line. Empty lines and lines
+// starting with "#" are skipped.
+func readBatchTerms(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening batch file: %v", err)
+	}
+	defer file.Close()
+
+	var terms []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		term := strings.TrimSpace(scanner.Text())
+		if term == "" || strings.HasPrefix(term, "#") {
+			continue
+		}
+		terms = append(terms, term)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading batch file: %v", err)
+	}
+	return terms, nil
+}
+
+// runBatchLookups looks up all terms with a bounded number of workers.
+// The items are returned in the order of the terms.
+func runBatchLookups(ctx context.Context, lang string, terms []string, workers int, lookup termLookup) []batchItem {
+	items := make([]batchItem, len(terms))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result, err := lookup(ctx, lang, terms[i])
+				items[i] = batchItem{Term: terms[i], Result: result, Err: err}
+			}
+		}()
+	}
+	for i := range terms {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return items
+}
+
+// printBatch writes the results grouped by term. Headers are only
+// printed for plain output so other formats stay machine-readable.
+func printBatch(items []batchItem, output OutputWriter, headers bool, out, errOut io.Writer) int {
+	code := exitOK
+	for _, item := range items {
+		if headers {
+			activeTheme.Title.Fprintf(out, "\n== %s ==\n", item.Term)
+		}
+		if item.Err != nil {
+			activeTheme.Error.Fprintf(errOut, "Error looking up %q: %v\n", item.Term, item.Err)
+			if code == exitOK {
+				code = exitCodeFor(item.Err)
+			}
+			continue
+		}
+		if err := output.Write(item.Result); err != nil {
+			fmt.Fprintln(errOut, "Error:", err)
+			code = exitNoResults
+		}
+	}
+	return code
+}
+
+func runBatch(ctx context.Context, lang, path string, strict bool, workers int, output OutputWriter, out io.Writer, headers bool) int {
+	terms, err := readBatchTerms(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	lookup := func(ctx context.Context, lang, term string) (Result, error) {
+		return lookupTerm(ctx, lang, term, strict)
+	}
+
+	// One spinner for the whole batch instead of one per worker
+	stopLoading := startLoadingAnimation()
+	previous := spinnerEnabled
+	spinnerEnabled = false
+	items := runBatchLookups(ctx, lang, terms, clampConcurrency(workers), lookup)
+	spinnerEnabled = previous
+	stopLoading()
+
+	return printBatch(items, output, headers, out, os.Stderr)
+}
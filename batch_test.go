@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestReadBatchTerms(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "begriffe.txt")
+	content := "Berlin\n\n# Kommentar\n  Paris  \nTokyo\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Die Datei konnte nicht geschrieben werden: %v", err)
+	}
+
+	terms, err := readBatchTerms(path)
+	if err != nil {
+		t.Fatalf("readBatchTerms sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	want := []string{"Berlin", "Paris", "Tokyo"}
+	if strings.Join(terms, ",") != strings.Join(want, ",") {
+		t.Errorf("Erwartete %q, erhielt %q", want, terms)
+	}
+
+	if _, err := readBatchTerms(filepath.Join(t.TempDir(), "fehlt.txt")); err == nil {
+		t.Error("Eine fehlende Datei sollte einen Fehler liefern")
+	}
+}
+
+func TestRunBatchLookupsKeepsOrder(t *testing.T) {
+	terms := []string{"Berlin", "Paris", "Gibtesnicht", "Tokyo", "Rom"}
+
+	// Frühe Begriffe brauchen länger, damit die Reihenfolge durcheinander käme
+	delays := map[string]time.Duration{
+		"Berlin":      50 * time.Millisecond,
+		"Paris":       40 * time.Millisecond,
+		"Gibtesnicht": 30 * time.Millisecond,
+		"Tokyo":       20 * time.Millisecond,
+		"Rom":         10 * time.Millisecond,
+	}
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	lookup := func(ctx context.Context, lang, term string) (Result, error) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(delays[term])
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		if term == "Gibtesnicht" {
+			return Result{}, wiki.ErrNoResults
+		}
+		return Result{Title: term, Lang: lang, Summary: "Über " + term}, nil
+	}
+
+	items := runBatchLookups(context.Background(), "de", terms, 2, lookup)
+	if maxRunning > 2 {
+		t.Errorf("Es sollten höchstens 2 Abrufe gleichzeitig laufen, waren %d", maxRunning)
+	}
+
+	for i, item := range items {
+		if item.Term != terms[i] {
+			t.Errorf("Eintrag %d: erwartete '%s', erhielt '%s'", i, terms[i], item.Term)
+		}
+	}
+	if !errors.Is(items[2].Err, wiki.ErrNoResults) {
+		t.Errorf("Der dritte Eintrag sollte den Fehler enthalten: %+v", items[2])
+	}
+
+	var out, errOut bytes.Buffer
+	code := printBatch(items, &markdownWriter{out: &out}, false, &out, &errOut)
+	if code != exitNoResults {
+		t.Errorf("Erwarteter Exit-Code %d, erhielt %d", exitNoResults, code)
+	}
+	if strings.Index(out.String(), "## Berlin") > strings.Index(out.String(), "## Rom") {
+		t.Errorf("Die Ausgabe sollte der Reihenfolge der Datei folgen: %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "Gibtesnicht") {
+		t.Errorf("Der Fehler sollte auf stderr gemeldet werden: %q", errOut.String())
+	}
+}
+
+func TestRunBatchLookupsUsesAllWorkers(t *testing.T) {
+	const workers = 3
+	terms := []string{"Berlin", "Paris", "Tokyo", "Rom", "Wien", "Prag"}
+
+	// Jeder Abruf wartet, bis alle Worker gleichzeitig beschäftigt sind
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	full := make(chan struct{})
+	var once sync.Once
+	lookup := func(ctx context.Context, lang, term string) (Result, error) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		if running == workers {
+			once.Do(func() { close(full) })
+		}
+		mu.Unlock()
+
+		select {
+		case <-full:
+		case <-time.After(time.Second):
+		}
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return Result{Title: term}, nil
+	}
+
+	runBatchLookups(context.Background(), "de", terms, workers, lookup)
+	if maxRunning != workers {
+		t.Errorf("Es sollten genau %d Abrufe gleichzeitig laufen, waren %d", workers, maxRunning)
+	}
+}
+
+func TestClampConcurrency(t *testing.T) {
+	for input, want := range map[int]int{-3: 1, 0: 1, 1: 1, 8: 8} {
+		if got := clampConcurrency(input); got != want {
+			t.Errorf("clampConcurrency(%d) = %d, erwartet %d", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// benchmarkTitles are looked up by -benchmark. They have articles in the
+// German and the English Wikipedia.
+var benchmarkTitles = []string{"Berlin", "Hamburg", "Paris", "London", "Tokyo"}
+
+// benchmarkPass is the outcome of looking up all titles once.
+type benchmarkPass struct {
+	Elapsed time.Duration
+	Fetched int
+}
+
+// runBenchmarkPass fetches the titles one after another, so the API sees
+// no more than one request at a time.
+func runBenchmarkPass(ctx context.Context, lang string, titles []string, fetch summaryFetcher, errOut io.Writer) benchmarkPass {
+	start := time.Now()
+	var pass benchmarkPass
+	for _, title := range titles {
+		if _, _, err := fetch(ctx, lang, title); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			fmt.Fprintf(errOut, "Error fetching %q: %v\n", title, err)
+			continue
+		}
+		pass.Fetched++
+	}
+	pass.Elapsed = time.Since(start)
+	return pass
+}
+
+// runBenchmark fetches the titles from the network and then from the
+// cache it just filled, and compares the times of both passes.
+func runBenchmark(ctx context.Context, lang string, titles []string, out io.Writer) int {
+	previous := spinnerEnabled
+	spinnerEnabled = false
+	defer func() { spinnerEnabled = previous }()
+
+	cold := runBenchmarkPass(ctx, lang, titles, fetchWikipediaSummary, os.Stderr)
+	if cold.Fetched == 0 {
+		return exitNetwork
+	}
+	warm := runBenchmarkPass(ctx, lang, titles, getWikipediaSummary, os.Stderr)
+
+	fmt.Fprintf(out, "Cold (network): %d of %d articles in %s\n", cold.Fetched, len(titles), cold.Elapsed.Round(time.Microsecond))
+	fmt.Fprintf(out, "Warm (cache):   %d of %d articles in %s\n", warm.Fetched, len(titles), warm.Elapsed.Round(time.Microsecond))
+	if warm.Elapsed > 0 {
+		fmt.Fprintf(out, "Speedup:        %.1fx\n", float64(cold.Elapsed)/float64(warm.Elapsed))
+	}
+	return exitOK
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestBenchmarkWarmPassIsFaster(t *testing.T) {
+	setTestHome(t)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		// Simuliert die Netzwerklatenz
+		time.Sleep(20 * time.Millisecond)
+		title := strings.TrimPrefix(r.URL.Path, "/de/api/rest_v1/page/summary/")
+		fmt.Fprintf(w, `{"title": %q, "extract": "Text.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/%s"}}}`, title, title)
+	}))
+	defer server.Close()
+
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	titles := []string{"Berlin", "Hamburg", "Paris"}
+	cold := runBenchmarkPass(context.Background(), "de", titles, fetchWikipediaSummary, io.Discard)
+	warm := runBenchmarkPass(context.Background(), "de", titles, getWikipediaSummary, io.Discard)
+
+	if cold.Fetched != 3 || warm.Fetched != 3 {
+		t.Fatalf("Erwartete je 3 Artikel, erhielt %d und %d", cold.Fetched, warm.Fetched)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("Der zweite Durchlauf sollte aus dem Cache kommen, %d Anfragen", requests.Load())
+	}
+	if warm.Elapsed >= cold.Elapsed {
+		t.Errorf("Der warme Durchlauf (%s) sollte schneller sein als der kalte (%s)", warm.Elapsed, cold.Elapsed)
+	}
+
+	var output bytes.Buffer
+	if code := runBenchmark(context.Background(), "de", titles, &output); code != exitOK {
+		t.Errorf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+	for _, want := range []string{"Cold (network): 3 of 3 articles", "Warm (cache):   3 of 3 articles", "Speedup:"} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("Ausgabe enthält %q nicht:\n%s", want, output.String())
+		}
+	}
+}
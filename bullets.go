@@ -0,0 +1,32 @@
+package main
+
+import "strings"
+
+// summaryBullets prints the summary as one bullet point per sentence, set
+// by -bullets. Only the plain and markdown output show bullets, the
+// summary itself is left as it is.
+var summaryBullets = false
+
+const bulletPrefix = "- "
+
+// bulletLines splits the summary into sentences and returns them as
+// "- " bullets. With a positive width every bullet is wrapped and its
+// continuation lines are indented under the text.
+func bulletLines(summary string, width int) []string {
+	indent := strings.Repeat(" ", len(bulletPrefix))
+	var lines []string
+	for _, sentence := range splitSentences(summary) {
+		wrapped := []string{sentence}
+		if width > 0 {
+			wrapped = wrapText(sentence, width-len(bulletPrefix))
+		}
+		for i, line := range wrapped {
+			if i == 0 {
+				lines = append(lines, bulletPrefix+line)
+			} else {
+				lines = append(lines, indent+line)
+			}
+		}
+	}
+	return lines
+}
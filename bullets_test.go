@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBulletLines(t *testing.T) {
+	summary := "Berlin ist die Hauptstadt Deutschlands. Die Mauer fiel am 9. November 1989! Ist sie z. B. größer als Hamburg? Ja."
+	want := []string{
+		"- Berlin ist die Hauptstadt Deutschlands.",
+		"- Die Mauer fiel am 9. November 1989!",
+		"- Ist sie z. B. größer als Hamburg?",
+		"- Ja.",
+	}
+	if got := bulletLines(summary, 0); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Erwartete\n%s\nerhielt\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	wrapped := bulletLines("Berlin ist die Hauptstadt Deutschlands. Ja.", 20)
+	want = []string{"- Berlin ist die", "  Hauptstadt", "  Deutschlands.", "- Ja."}
+	if strings.Join(wrapped, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Umbrochene Punkte sollten eingerückt werden: %q", wrapped)
+	}
+}
+
+func TestWritersWithBullets(t *testing.T) {
+	summaryBullets = true
+	defer func() { summaryBullets = false }()
+
+	result := testResult
+	result.Summary = "Berlin ist die Hauptstadt. Sie liegt an der Spree."
+	bullets := "- Berlin ist die Hauptstadt.\n- Sie liegt an der Spree.\n"
+
+	var markdown bytes.Buffer
+	if err := (&markdownWriter{out: &markdown}).Write(result); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(markdown.String(), "\n\n"+bullets+"\n") {
+		t.Errorf("Markdown sollte eine Liste enthalten: %q", markdown.String())
+	}
+
+	var plain bytes.Buffer
+	if err := (&plainWriter{out: &plain}).Write(result); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(plain.String(), bullets) {
+		t.Errorf("Die Textausgabe sollte Aufzählungspunkte enthalten: %q", plain.String())
+	}
+
+	result.Summary = ""
+	plain.Reset()
+	(&plainWriter{out: &plain}).Write(result)
+	if !strings.Contains(plain.String(), noSummaryMessage) || strings.Contains(plain.String(), "- ") {
+		t.Errorf("Ohne Zusammenfassung sollte kein Punkt erscheinen: %q", plain.String())
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// freshShare is the part of the TTL during which a cached entry is shown
+// as fresh. Older entries are colored as nearing expiry.
+const freshShare = 0.75
+
+// cachedLabel describes how old the cached result is, e.g.
+// "(cached 3h ago)". Results without a timestamp are only marked as cached,
+// expired ones shown because the fetch failed as stale.
+func cachedLabel(result Result, now time.Time) string {
+	if result.Stale {
+		if result.CachedAt.IsZero() {
+			return "(stale, offline)"
+		}
+		return fmt.Sprintf("(stale, offline, cached %s)", formatAge(now.Sub(result.CachedAt)))
+	}
+	if result.CachedAt.IsZero() {
+		return "(cached)"
+	}
+	return fmt.Sprintf("(cached %s)", formatAge(now.Sub(result.CachedAt)))
+}
+
+// formatAge rounds age down to its largest unit.
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age/time.Minute))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age/time.Hour))
+	}
+	return fmt.Sprintf("%dd ago", int(age/(24*time.Hour)))
+}
+
+// cachedColor returns the fresh color while the entry is well within its
+// TTL and the regular cached color once it nears expiry.
+func cachedColor(result Result, now time.Time) *color.Color {
+	if result.Stale {
+		return activeTheme.Error
+	}
+	if result.CachedAt.IsZero() || result.CacheTTL <= 0 {
+		return activeTheme.Cached
+	}
+	if now.Sub(result.CachedAt) < time.Duration(float64(result.CacheTTL)*freshShare) {
+		return activeTheme.Fresh
+	}
+	return activeTheme.Cached
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCachedLabel(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		cachedAt time.Time
+		want     string
+	}{
+		{time.Time{}, "(cached)"},
+		{now.Add(-30 * time.Second), "(cached just now)"},
+		{now.Add(-42 * time.Minute), "(cached 42m ago)"},
+		{now.Add(-3*time.Hour - 59*time.Minute), "(cached 3h ago)"},
+		{now.Add(-50 * time.Hour), "(cached 2d ago)"},
+	}
+	for _, tt := range tests {
+		if got := cachedLabel(Result{Cached: true, CachedAt: tt.cachedAt}, now); got != tt.want {
+			t.Errorf("cachedLabel(%v) = %q, erwartet %q", tt.cachedAt, got, tt.want)
+		}
+	}
+}
+
+func TestCachedColor(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ttl := 24 * time.Hour
+
+	fresh := Result{Cached: true, CachedAt: now.Add(-time.Hour), CacheTTL: ttl}
+	if cachedColor(fresh, now) != activeTheme.Fresh {
+		t.Error("Ein junger Eintrag sollte als frisch markiert werden")
+	}
+	old := Result{Cached: true, CachedAt: now.Add(-20 * time.Hour), CacheTTL: ttl}
+	if cachedColor(old, now) != activeTheme.Cached {
+		t.Error("Ein Eintrag kurz vor Ablauf sollte nicht als frisch markiert werden")
+	}
+}
+
+func TestCachedLabelStale(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	result := Result{Cached: true, Stale: true, CachedAt: now.Add(-50 * time.Hour)}
+	if got := cachedLabel(result, now); got != "(stale, offline, cached 2d ago)" {
+		t.Errorf("cachedLabel = %q", got)
+	}
+	if cachedColor(result, now) != activeTheme.Error {
+		t.Error("Veraltete Ergebnisse sollten in der Fehlerfarbe erscheinen")
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+// splitCacheInfoArg splits "de:Berlin" into language and title. Without a
+// known language prefix the whole argument is the title, so titles like
+// "Star Trek: Voyager" keep their colon.
+func splitCacheInfoArg(arg, lang string) (string, string) {
+	if prefix, title, found := strings.Cut(arg, ":"); found {
+		if _, ok := supportedLanguages[prefix]; ok {
+			return prefix, strings.TrimSpace(title)
+		}
+	}
+	return lang, arg
+}
+
+// printCacheInfo describes the cache entry stored under key.
+func printCacheInfo(out io.Writer, key string, entry wiki.CacheEntry, now time.Time) {
+	fmt.Fprintf(out, "Key:         %s\n", key)
+	switch {
+	case entry.NotFound:
+		fmt.Fprintln(out, "Article:     not found")
+	case entry.RedirectTo != "":
+		fmt.Fprintf(out, "Redirect to: %s\n", entry.RedirectTo)
+	default:
+		fmt.Fprintf(out, "Title:       %s\n", entry.Title)
+		fmt.Fprintf(out, "Summary:     %d characters\n", utf8.RuneCountInString(entry.Summary))
+		fmt.Fprintf(out, "URL:         %s\n", entry.URL)
+	}
+	fmt.Fprintf(out, "Stored:      %s\n", entry.Timestamp.Local().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(out, "Age:         %s\n", formatAge(now.Sub(entry.Timestamp)))
+	fmt.Fprintf(out, "TTL:         %s\n", entry.TTL())
+	if entry.ETag != "" {
+		fmt.Fprintf(out, "ETag:        %s\n", entry.ETag)
+	}
+	expired := "no"
+	if !entry.Pinned && now.Sub(entry.Timestamp) >= entry.TTL() {
+		expired = "yes"
+	}
+	fmt.Fprintf(out, "Expired:     %s\n", expired)
+	if entry.Pinned {
+		fmt.Fprintln(out, "Pinned:      yes")
+	}
+}
+
+// runCacheInfo prints the metadata of one cached article without any
+// network access.
+func runCacheInfo(project, lang, arg string, out io.Writer) int {
+	lang, title := splitCacheInfoArg(arg, lang)
+	key := wiki.ProjectCacheKey(project, lang, title)
+	entry, ok := fileCache().GetStale(key)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Not cached: %s\n", key)
+		return exitNoResults
+	}
+	printCacheInfo(out, key, entry, time.Now())
+	return exitOK
+}
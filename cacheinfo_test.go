@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestSplitCacheInfoArg(t *testing.T) {
+	tests := []struct {
+		arg, lang string
+		wantLang  string
+		wantTitle string
+	}{
+		{"de:Berlin", "en", "de", "Berlin"},
+		{"Berlin", "de", "de", "Berlin"},
+		{"Star Trek: Voyager", "en", "en", "Star Trek: Voyager"},
+	}
+	for _, tt := range tests {
+		lang, title := splitCacheInfoArg(tt.arg, tt.lang)
+		if lang != tt.wantLang || title != tt.wantTitle {
+			t.Errorf("splitCacheInfoArg(%q) = %q, %q, erwartet %q, %q", tt.arg, lang, title, tt.wantLang, tt.wantTitle)
+		}
+	}
+}
+
+func TestCacheInfo(t *testing.T) {
+	setTestHome(t)
+	fileCache().Set("de", "Berlin", wiki.CacheEntry{
+		Title:   "Berlin",
+		Summary: "Berlin ist die Hauptstadt.",
+		URL:     "https://de.wikipedia.org/wiki/Berlin",
+	})
+
+	stdout, _ := captureOutput(t, func() {
+		if code := run([]string{"-cache-info", "de:Berlin"}); code != exitOK {
+			t.Errorf("Exit-Code = %d, erwartet %d", code, exitOK)
+		}
+	})
+	for _, want := range []string{"Key:         de:Berlin", "Summary:     26 characters", "https://de.wikipedia.org/wiki/Berlin", "Age:         just now", "Expired:     no"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Ausgabe enthält %q nicht:\n%s", want, stdout)
+		}
+	}
+
+	_, stderr := captureOutput(t, func() {
+		if code := run([]string{"-cache-info", "de:Paris"}); code != exitNoResults {
+			t.Errorf("Exit-Code = %d, erwartet %d", code, exitNoResults)
+		}
+	})
+	if !strings.Contains(stderr, "Not cached: de:Paris") {
+		t.Errorf("Fehlermeldung fehlt: %q", stderr)
+	}
+}
+
+func TestPrintCacheInfoExpired(t *testing.T) {
+	now := time.Now()
+	entry := wiki.CacheEntry{Title: "Berlin", Timestamp: now.Add(-25 * time.Hour)}
+
+	var out strings.Builder
+	printCacheInfo(&out, "de:Berlin", entry, now)
+	if !strings.Contains(out.String(), "Expired:     yes") || !strings.Contains(out.String(), "Age:         1d ago") {
+		t.Errorf("Abgelaufener Eintrag falsch beschrieben:\n%s", out.String())
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+var errNoClipboard = errors.New("no clipboard tool found")
+
+// clipboardCommand is a tool that reads the text to copy from stdin.
+type clipboardCommand struct {
+	name string
+	args []string
+}
+
+// commandRunner runs a command with the given input on stdin. It is a
+// variable so tests do not touch the real clipboard.
+var commandRunner = func(name string, args []string, input string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(input)
+	return cmd.Run()
+}
+
+var lookPath = exec.LookPath
+
+// clipboardCommands returns the clipboard tools to try on the platform, in
+// order of preference.
+func clipboardCommands(goos string) []clipboardCommand {
+	switch goos {
+	case "darwin":
+		return []clipboardCommand{{name: "pbcopy"}}
+	case "windows":
+		return []clipboardCommand{{name: "clip.exe"}}
+	default:
+		// clip.exe is available under WSL
+		return []clipboardCommand{
+			{name: "wl-copy"},
+			{name: "xclip", args: []string{"-selection", "clipboard"}},
+			{name: "xsel", args: []string{"--clipboard", "--input"}},
+			{name: "clip.exe"},
+		}
+	}
+}
+
+// copyToClipboard copies the text with the first clipboard tool that is
+// installed.
+func copyToClipboard(text string) error {
+	for _, command := range clipboardCommands(runtime.GOOS) {
+		if _, err := lookPath(command.name); err != nil {
+			continue
+		}
+		if err := commandRunner(command.name, command.args, text); err != nil {
+			return fmt.Errorf("%s: %w", command.name, err)
+		}
+		return nil
+	}
+	return errNoClipboard
+}
+
+// copyURL copies the URL for -copy and only warns if that fails, the
+// lookup itself succeeded.
+func copyURL(url string) {
+	if err := copyToClipboard(url); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not copy the URL to the clipboard: %v\n", err)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+// stubClipboard replaces the command runner and lookPath so that only the
+// given tools appear to be installed.
+func stubClipboard(t *testing.T, installed ...string) *[]string {
+	var calls []string
+	originalRunner, originalLookPath := commandRunner, lookPath
+	t.Cleanup(func() { commandRunner, lookPath = originalRunner, originalLookPath })
+
+	lookPath = func(name string) (string, error) {
+		for _, tool := range installed {
+			if tool == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+	commandRunner = func(name string, args []string, input string) error {
+		calls = append(calls, strings.TrimSpace(name+" "+strings.Join(args, " "))+": "+input)
+		return nil
+	}
+	return &calls
+}
+
+func TestCopyToClipboard(t *testing.T) {
+	calls := stubClipboard(t, "xclip", "xsel", "pbcopy", "clip.exe", "wl-copy")
+
+	if err := copyToClipboard("https://de.wikipedia.org/wiki/Berlin"); err != nil {
+		t.Fatalf("copyToClipboard sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	// Es wird genau ein Werkzeug aufgerufen
+	if len(*calls) != 1 || !strings.HasSuffix((*calls)[0], ": https://de.wikipedia.org/wiki/Berlin") {
+		t.Errorf("Erwartete einen Aufruf mit der URL, erhielt %v", *calls)
+	}
+}
+
+func TestCopyToClipboardWithoutTool(t *testing.T) {
+	calls := stubClipboard(t)
+
+	if err := copyToClipboard("https://de.wikipedia.org/wiki/Berlin"); !errors.Is(err, errNoClipboard) {
+		t.Errorf("Erwartete errNoClipboard, erhielt %v", err)
+	}
+	if len(*calls) != 0 {
+		t.Errorf("Ohne Werkzeug sollte nichts aufgerufen werden, erhielt %v", *calls)
+	}
+}
+
+func TestClipboardCommands(t *testing.T) {
+	tests := map[string]string{
+		"darwin":  "pbcopy",
+		"windows": "clip.exe",
+		"linux":   "wl-copy",
+	}
+	for goos, want := range tests {
+		if commands := clipboardCommands(goos); commands[0].name != want {
+			t.Errorf("%s: erwartete '%s', erhielt '%s'", goos, want, commands[0].name)
+		}
+	}
+
+	// Unter Linux wird xclip mit der Zwischenablage statt der Auswahl aufgerufen
+	for _, command := range clipboardCommands("linux") {
+		if command.name == "xclip" && strings.Join(command.args, " ") != "-selection clipboard" {
+			t.Errorf("Unerwartete Argumente für xclip: %v", command.args)
+		}
+	}
+}
+
+func TestSearchStateCopiesURL(t *testing.T) {
+	setTestHome(t)
+	calls := stubClipboard(t, "pbcopy", "wl-copy", "clip.exe")
+
+	fetch := func(ctx context.Context, lang, title string) (wiki.CacheEntry, bool, error) {
+		return wiki.CacheEntry{Summary: "Berlin", URL: "https://de.wikipedia.org/wiki/Berlin"}, false, nil
+	}
+
+	maxResults := 5
+	state := &searchState{Lang: "de", Results: []string{"Berlin"}, Output: &plainWriter{out: io.Discard}, CopyURL: true}
+	if err := state.run(context.Background(), &maxResults, fetch); err != nil {
+		t.Fatalf("run sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if len(*calls) != 1 || !strings.HasSuffix((*calls)[0], "https://de.wikipedia.org/wiki/Berlin") {
+		t.Errorf("Die URL sollte kopiert werden, erhielt %v", *calls)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const compactSeparator = " — "
+
+// compactWriter prints the title, the short description and the URL on a
+// single line, for status bars and scripts.
+type compactWriter struct {
+	out   io.Writer
+	width int
+}
+
+func (w *compactWriter) Write(result Result) error {
+	_, err := fmt.Fprintln(w.out, compactLine(result, w.width))
+	return err
+}
+
+// compactLine joins title, description and URL. The description is
+// shortened so the line fits into width, title and URL are never cut.
+func compactLine(result Result, width int) string {
+	description := describe(result)
+	fixed := utf8.RuneCountInString(result.Title) + utf8.RuneCountInString(result.URL) + 2*utf8.RuneCountInString(compactSeparator)
+	description = truncate(description, width-fixed)
+	if description == "" {
+		return result.Title + compactSeparator + result.URL
+	}
+	return result.Title + compactSeparator + description + compactSeparator + result.URL
+}
+
+// truncate shortens text to at most width characters and marks the cut
+// with an ellipsis.
+func truncate(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	if width <= 1 {
+		return ""
+	}
+	runes := []rune(text)
+	return strings.TrimRight(string(runes[:width-1]), " ") + "…"
+}
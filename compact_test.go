@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompactLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		result Result
+		width  int
+		want   string
+	}{
+		{
+			name:   "Beschreibung passt",
+			result: Result{Title: "Berlin", Description: "Hauptstadt von Deutschland", URL: "https://de.wikipedia.org/wiki/Berlin"},
+			width:  80,
+			want:   "Berlin — Hauptstadt von Deutschland — https://de.wikipedia.org/wiki/Berlin",
+		},
+		{
+			name:   "Beschreibung wird gekürzt",
+			result: Result{Title: "Berlin", Description: "Hauptstadt von Deutschland", URL: "https://de.wikipedia.org/wiki/Berlin"},
+			width:  60,
+			want:   "Berlin — Hauptstadt… — https://de.wikipedia.org/wiki/Berlin",
+		},
+		{
+			name:   "erster Satz ohne Beschreibung",
+			result: Result{Title: "Go", Summary: "Go ist eine Programmiersprache. Sie wurde bei Google entwickelt.", URL: "https://de.wikipedia.org/wiki/Go"},
+			width:  80,
+			want:   "Go — Go ist eine Programmiersprache. — https://de.wikipedia.org/wiki/Go",
+		},
+		{
+			name:   "kein Platz für die Beschreibung",
+			result: Result{Title: "Berlin", Description: "Hauptstadt von Deutschland", URL: "https://de.wikipedia.org/wiki/Berlin"},
+			width:  20,
+			want:   "Berlin — https://de.wikipedia.org/wiki/Berlin",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compactLine(tt.result, tt.width); got != tt.want {
+				t.Errorf("compactLine() = %q, erwartet %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompactWriterSingleLine(t *testing.T) {
+	var buf bytes.Buffer
+	w := &compactWriter{out: &buf, width: 80}
+	if err := w.Write(Result{Title: "Berlin", Description: "Hauptstadt", URL: "https://de.wikipedia.org/wiki/Berlin", Cached: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "Berlin — Hauptstadt — https://de.wikipedia.org/wiki/Berlin\n"; got != want {
+		t.Errorf("Ausgabe = %q, erwartet %q", got, want)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// completionValues returns the values the shells offer after a flag,
+// nil if the flag takes free text or no value at all.
+func completionValues(name string) []string {
+	switch name {
+	case "lang", "lang-swap":
+		codes := make([]string, 0, len(supportedLanguages))
+		for code := range supportedLanguages {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		return codes
+	case "format":
+		return outputFormats
+	case "project":
+		return wiki.Projects
+	case "sort":
+		return sortModes
+	case "theme":
+		return themeNames()
+	case "spinner":
+		return spinnerStyleNames()
+	case "url-format":
+		return urlFormats
+	case "lang-guess":
+		return langGuessModes
+	}
+	return nil
+}
+
+// takesValue reports whether the flag needs an argument, -json does not
+// but -lang does.
+func takesValue(f *flag.Flag) bool {
+	if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+		return false
+	}
+	return true
+}
+
+// writeCompletion writes the completion script for the shell, covering
+// all flags of the flag set.
+func writeCompletion(w io.Writer, shell string, flags *flag.FlagSet) error {
+	var all []*flag.Flag
+	flags.VisitAll(func(f *flag.Flag) {
+		all = append(all, f)
+	})
+	switch shell {
+	case "bash":
+		writeBashCompletion(w, all)
+	case "zsh":
+		writeZshCompletion(w, all)
+	case "fish":
+		writeFishCompletion(w, all)
+	default:
+		return fmt.Errorf("unknown shell %q, available shells: %s", shell, strings.Join(completionShells, ", "))
+	}
+	return nil
+}
+
+func writeBashCompletion(w io.Writer, all []*flag.Flag) {
+	names := make([]string, len(all))
+	for i, f := range all {
+		names[i] = "-" + f.Name
+	}
+	fmt.Fprintln(w, "# bash completion for wikr, load with: source <(wikr -completion bash)")
+	fmt.Fprintln(w, "_wikr() {")
+	fmt.Fprintln(w, "    local cur=\"${COMP_WORDS[COMP_CWORD]}\"")
+	fmt.Fprintln(w, "    local prev=\"${COMP_WORDS[COMP_CWORD-1]}\"")
+	fmt.Fprintln(w, "    case \"$prev\" in")
+	for _, f := range all {
+		if values := completionValues(f.Name); values != nil {
+			fmt.Fprintf(w, "        -%s) COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")); return ;;\n", f.Name, strings.Join(values, " "))
+		}
+	}
+	fmt.Fprintln(w, "    esac")
+	fmt.Fprintln(w, "    if [[ \"$cur\" == -* ]]; then")
+	fmt.Fprintf(w, "        COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(names, " "))
+	fmt.Fprintln(w, "    fi")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "complete -o default -F _wikr wikr")
+}
+
+// zshEscaper quotes a flag description inside the single-quoted
+// _arguments specs.
+var zshEscaper = strings.NewReplacer("'", `'\''`, "[", `\[`, "]", `\]`, ":", `\:`)
+
+func writeZshCompletion(w io.Writer, all []*flag.Flag) {
+	fmt.Fprintln(w, "#compdef wikr")
+	fmt.Fprintln(w, "# zsh completion for wikr, save as _wikr in a directory of $fpath")
+	fmt.Fprintln(w, "_arguments \\")
+	for _, f := range all {
+		spec := fmt.Sprintf("-%s[%s]", f.Name, zshEscaper.Replace(f.Usage))
+		if takesValue(f) {
+			if values := completionValues(f.Name); values != nil {
+				spec += fmt.Sprintf(":%s:(%s)", f.Name, strings.Join(values, " "))
+			} else {
+				spec += ":" + f.Name + ":_default"
+			}
+		}
+		fmt.Fprintf(w, "  '%s' \\\n", spec)
+	}
+	fmt.Fprintln(w, "  '*:search term:'")
+}
+
+func writeFishCompletion(w io.Writer, all []*flag.Flag) {
+	fmt.Fprintln(w, "# fish completion for wikr, load with: wikr -completion fish | source")
+	for _, f := range all {
+		line := fmt.Sprintf("complete -c wikr -o %s -d '%s'", f.Name, strings.ReplaceAll(f.Usage, "'", `\'`))
+		if values := completionValues(f.Name); values != nil {
+			line += fmt.Sprintf(" -x -a '%s'", strings.Join(values, " "))
+		} else if takesValue(f) {
+			line += " -r"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
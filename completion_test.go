@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunCompletion(t *testing.T) {
+	setTestHome(t)
+
+	for _, shell := range completionShells {
+		var code int
+		stdout, _ := captureOutput(t, func() {
+			code = run([]string{"-completion", shell})
+		})
+		if code != exitOK {
+			t.Fatalf("%s: erwarteter Exit-Code %d, erhielt %d", shell, exitOK, code)
+		}
+		for _, want := range []string{"lang", "max", "json", "clear-cache", "completion", "lang-swap"} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("%s: das Skript sollte das Flag %q enthalten:\n%s", shell, want, stdout)
+			}
+		}
+		if !strings.Contains(stdout, "de el en") {
+			t.Errorf("%s: das Skript sollte die Sprachcodes enthalten:\n%s", shell, stdout)
+		}
+		if !strings.Contains(stdout, "plain json markdown") {
+			t.Errorf("%s: das Skript sollte die Ausgabeformate enthalten:\n%s", shell, stdout)
+		}
+	}
+}
+
+func TestRunCompletionUnknownShell(t *testing.T) {
+	setTestHome(t)
+
+	var code int
+	_, stderr := captureOutput(t, func() {
+		code = run([]string{"-completion", "powershell"})
+	})
+	if code != exitUsage {
+		t.Errorf("Erwarteter Exit-Code %d, erhielt %d", exitUsage, code)
+	}
+	if !strings.Contains(stderr, "bash, zsh, fish") {
+		t.Errorf("Die Fehlermeldung sollte die Shells nennen: %q", stderr)
+	}
+}
+
+func TestZshCompletionEscapesDescriptions(t *testing.T) {
+	if got := zshEscaper.Replace("it's [a]:b"); got != `it'\''s \[a\]\:b` {
+		t.Errorf("Unerwartete Maskierung: %q", got)
+	}
+}
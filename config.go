@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+const (
+	// configFileName is the legacy name of the config in the home directory
+	configFileName    = ".wikr_config.json"
+	defaultMaxResults = 5
+)
+
+// Config holds the user settings read from the config file. Settings
+// given on the command line take precedence.
+type Config struct {
+	MaxResults     int            `json:"max_results"`
+	LangMaxResults map[string]int `json:"lang_max_results"`
+	// CacheMode is the octal permission mode of the cache file, e.g. "0640"
+	CacheMode string `json:"cache_mode,omitempty"`
+	// CompressCache gzips the cache file
+	CompressCache bool `json:"compress_cache,omitempty"`
+	// AdaptiveTTL lets the TTL of articles depend on their last edit,
+	// between AdaptiveTTLMin and AdaptiveTTLMax, e.g. "1h" and "168h"
+	AdaptiveTTL    bool   `json:"adaptive_ttl,omitempty"`
+	AdaptiveTTLMin string `json:"adaptive_ttl_min,omitempty"`
+	AdaptiveTTLMax string `json:"adaptive_ttl_max,omitempty"`
+}
+
+func getConfigPath() string {
+	return wiki.ConfigFilePath("config.json", configFileName)
+}
+
+// loadConfig reads the config file and falls back to the defaults for
+// everything that is missing.
+func loadConfig() Config {
+	config := Config{MaxResults: defaultMaxResults}
+	configPath := getConfigPath()
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Info("error reading config file", "path", configPath, "error", err)
+		}
+		return config
+	}
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		logger.Info("error decoding config", "path", configPath, "error", err)
+	}
+	if config.MaxResults <= 0 {
+		config.MaxResults = defaultMaxResults
+	}
+	return config
+}
+
+// maxResultsFor returns the per-language override for lang if there is
+// one and the global default otherwise.
+func (c Config) maxResultsFor(lang string) int {
+	if max, ok := c.LangMaxResults[lang]; ok && max > 0 {
+		return max
+	}
+	if c.MaxResults > 0 {
+		return c.MaxResults
+	}
+	return defaultMaxResults
+}
+
+// cacheFileMode returns the permission mode for the cache file, 0600 when
+// none or an invalid one is configured.
+func (c Config) cacheFileMode() os.FileMode {
+	if c.CacheMode == "" {
+		return wiki.DefaultCacheMode
+	}
+	mode, err := strconv.ParseUint(c.CacheMode, 8, 32)
+	if err != nil || mode > 0777 {
+		logger.Info("invalid cache_mode in config file, using 0600", "cache_mode", c.CacheMode)
+		return wiki.DefaultCacheMode
+	}
+	return os.FileMode(mode)
+}
+
+// adaptiveTTLBounds returns the bounds of the adaptive TTL, or zero when
+// it is not enabled. Missing or invalid bounds fall back to the defaults.
+func (c Config) adaptiveTTLBounds() (time.Duration, time.Duration) {
+	if !c.AdaptiveTTL {
+		return 0, 0
+	}
+	bound := func(name, value string, fallback time.Duration) time.Duration {
+		if value == "" {
+			return fallback
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			logger.Info("invalid "+name+" in config file, using "+fallback.String(), name, value)
+			return fallback
+		}
+		return d
+	}
+	min := bound("adaptive_ttl_min", c.AdaptiveTTLMin, wiki.DefaultMinAdaptiveTTL)
+	max := bound("adaptive_ttl_max", c.AdaptiveTTLMax, wiki.DefaultMaxAdaptiveTTL)
+	if min > max {
+		logger.Info("adaptive_ttl_min is above adaptive_ttl_max in config file, using the defaults", "adaptive_ttl_min", min, "adaptive_ttl_max", max)
+		return wiki.DefaultMinAdaptiveTTL, wiki.DefaultMaxAdaptiveTTL
+	}
+	return min, max
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestMaxResultsForResolution(t *testing.T) {
+	config := Config{
+		MaxResults:     7,
+		LangMaxResults: map[string]int{"en": 10, "de": 3},
+	}
+
+	tests := []struct {
+		lang string
+		want int
+	}{
+		{"en", 10},
+		{"de", 3},
+		{"fr", 7},
+	}
+
+	for _, test := range tests {
+		if got := config.maxResultsFor(test.lang); got != test.want {
+			t.Errorf("maxResultsFor(%q): erwartete %d, erhielt %d", test.lang, test.want, got)
+		}
+	}
+}
+
+func TestMaxResultsForDefaults(t *testing.T) {
+	if got := (Config{}).maxResultsFor("de"); got != defaultMaxResults {
+		t.Errorf("Ohne Konfiguration erwartete %d, erhielt %d", defaultMaxResults, got)
+	}
+
+	config := Config{LangMaxResults: map[string]int{"en": 0}}
+	if got := config.maxResultsFor("en"); got != defaultMaxResults {
+		t.Errorf("Ungültige Sprachwerte sollten ignoriert werden: erwartete %d, erhielt %d", defaultMaxResults, got)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	setTestHome(t)
+
+	config := loadConfig()
+	if config.MaxResults != defaultMaxResults {
+		t.Errorf("Ohne Konfigurationsdatei erwartete %d, erhielt %d", defaultMaxResults, config.MaxResults)
+	}
+
+	data := []byte(`{"max_results": 4, "lang_max_results": {"en": 12}}`)
+	writeTestFile(t, getConfigPath(), data)
+
+	config = loadConfig()
+	if got := config.maxResultsFor("en"); got != 12 {
+		t.Errorf("Erwartete 12 für 'en', erhielt %d", got)
+	}
+	if got := config.maxResultsFor("de"); got != 4 {
+		t.Errorf("Erwartete 4 für 'de', erhielt %d", got)
+	}
+}
+
+func TestCacheFileMode(t *testing.T) {
+	tests := map[string]os.FileMode{
+		"":     wiki.DefaultCacheMode,
+		"0640": 0640,
+		"600":  0600,
+		"0999": wiki.DefaultCacheMode,
+		"abc":  wiki.DefaultCacheMode,
+	}
+
+	for value, want := range tests {
+		if got := (Config{CacheMode: value}).cacheFileMode(); got != want {
+			t.Errorf("cache_mode %q: erwartete %o, erhielt %o", value, want, got)
+		}
+	}
+}
+
+func TestAdaptiveTTLBounds(t *testing.T) {
+	tests := []struct {
+		config   Config
+		min, max time.Duration
+	}{
+		{Config{AdaptiveTTLMin: "2h"}, 0, 0},
+		{Config{AdaptiveTTL: true}, wiki.DefaultMinAdaptiveTTL, wiki.DefaultMaxAdaptiveTTL},
+		{Config{AdaptiveTTL: true, AdaptiveTTLMin: "30m", AdaptiveTTLMax: "48h"}, 30 * time.Minute, 48 * time.Hour},
+		{Config{AdaptiveTTL: true, AdaptiveTTLMin: "bald", AdaptiveTTLMax: "-1h"}, wiki.DefaultMinAdaptiveTTL, wiki.DefaultMaxAdaptiveTTL},
+		{Config{AdaptiveTTL: true, AdaptiveTTLMin: "48h", AdaptiveTTLMax: "1h"}, wiki.DefaultMinAdaptiveTTL, wiki.DefaultMaxAdaptiveTTL},
+	}
+	for _, test := range tests {
+		if min, max := test.config.adaptiveTTLBounds(); min != test.min || max != test.max {
+			t.Errorf("%+v: erwartete %v bis %v, erhielt %v bis %v", test.config, test.min, test.max, min, max)
+		}
+	}
+}
+
+// writeTestFile writes data to path and creates the directory, config and
+// cache files live in subdirectories of the home directory.
+func writeTestFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("%s konnte nicht geschrieben werden: %v", path, err)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// countResult is the JSON output of -count.
+type countResult struct {
+	Term  string `json:"term"`
+	Lang  string `json:"lang"`
+	Count int    `json:"count"`
+}
+
+// runCount prints how many articles match the term, for -count.
+func runCount(ctx context.Context, lang, term string, asJSON bool, out io.Writer) int {
+	stopLoading := startLoadingAnimation()
+	count, err := newClient(lang).Count(ctx, term)
+	stopLoading()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error during search:", err)
+		return exitCodeFor(err)
+	}
+
+	if asJSON {
+		data, err := marshalJSON(countResult{Term: term, Lang: lang, Count: count})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return exitNoResults
+		}
+		fmt.Fprintln(out, string(data))
+	} else {
+		fmt.Fprintln(out, count)
+	}
+	if count == 0 {
+		return exitNoResults
+	}
+	return exitOK
+}
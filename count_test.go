@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestRunCount(t *testing.T) {
+	setTestHome(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("srsearch") == "Berlin" {
+			fmt.Fprint(w, `{"query": {"searchinfo": {"totalhits": 48213}, "search": [{"title": "Berlin"}]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"query": {"searchinfo": {"totalhits": 0}, "search": []}}`)
+	}))
+	defer server.Close()
+
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	var output bytes.Buffer
+	if code := runCount(context.Background(), "de", "Berlin", false, &output); code != exitOK {
+		t.Errorf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+	if output.String() != "48213\n" {
+		t.Errorf("Erwartete \"48213\\n\", erhielt %q", output.String())
+	}
+
+	output.Reset()
+	runCount(context.Background(), "de", "Berlin", true, &output)
+	var decoded countResult
+	if err := json.Unmarshal(output.Bytes(), &decoded); err != nil {
+		t.Fatalf("Die Ausgabe sollte gültiges JSON sein: %v", err)
+	}
+	if decoded != (countResult{Term: "Berlin", Lang: "de", Count: 48213}) {
+		t.Errorf("Unerwartetes Ergebnis: %+v", decoded)
+	}
+
+	output.Reset()
+	if code := runCount(context.Background(), "de", "Xyzzyplugh", false, &output); code != exitNoResults {
+		t.Errorf("Ohne Treffer sollte %d zurückgegeben werden, erhielt %d", exitNoResults, code)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+// sparseResults is the number of results below which a spelling
+// suggestion is offered.
+const sparseResults = 3
+
+// offerSuggestion reports whether the search found so little that the
+// suggested spelling is worth offering.
+func offerSuggestion(result wiki.SearchResult) bool {
+	return result.Suggestion != "" && len(result.Titles) < sparseResults
+}
+
+// askDidYouMean asks whether to search for the suggestion instead, the
+// default is no.
+func askDidYouMean(suggestion string) bool {
+	fmt.Fprintf(promptOut, "Did you mean %s? [y/N] ", suggestion)
+	done := waitForInput()
+	input, _ := stdinReader.ReadString('\n')
+	done()
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func suggestionServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("srsearch") == "Einstien" {
+			fmt.Fprint(w, `{"query": {"searchinfo": {"suggestion": "einstein"}, "search": []}}`)
+			return
+		}
+		fmt.Fprint(w, `{"query": {"search": [{"title": "Albert Einstein"}, {"title": "Einstein (Begriffsklärung)"}]}}`)
+	}))
+	t.Cleanup(server.Close)
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	t.Cleanup(func() { wikiOptions = nil })
+	return server
+}
+
+func TestSearchWithFallbackAcceptsSuggestion(t *testing.T) {
+	setTestHome(t)
+	suggestionServer(t)
+
+	var asked string
+	confirm := func(suggestion string) bool {
+		asked = suggestion
+		return true
+	}
+	results, _, err := searchWithFallback(context.Background(), []string{"de"}, "Einstien", confirm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asked != "einstein" {
+		t.Errorf("Erwartete die Frage nach 'einstein', erhielt %q", asked)
+	}
+	if len(results) != 2 || results[0] != "Albert Einstein" {
+		t.Errorf("Erwartete die Ergebnisse für den Vorschlag, erhielt %v", results)
+	}
+}
+
+func TestSearchWithFallbackDeclinesSuggestion(t *testing.T) {
+	setTestHome(t)
+	suggestionServer(t)
+	stdinReader = bufio.NewReader(strings.NewReader("\n"))
+	defer func() { stdinReader = bufio.NewReader(os.Stdin) }()
+
+	results, _, err := searchWithFallback(context.Background(), []string{"de"}, "Einstien", askDidYouMean)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Ohne Zustimmung sollte nicht erneut gesucht werden, erhielt %v", results)
+	}
+}
+
+func TestOfferSuggestion(t *testing.T) {
+	if offerSuggestion(wiki.SearchResult{Titles: []string{"A", "B", "C"}, Suggestion: "x"}) {
+		t.Error("Bei genügend Ergebnissen sollte kein Vorschlag angeboten werden")
+	}
+	if offerSuggestion(wiki.SearchResult{}) {
+		t.Error("Ohne Vorschlag sollte nichts angeboten werden")
+	}
+	if !offerSuggestion(wiki.SearchResult{Titles: []string{"A"}, Suggestion: "x"}) {
+		t.Error("Bei wenigen Ergebnissen sollte der Vorschlag angeboten werden")
+	}
+}
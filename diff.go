@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Below this width the two summaries are printed one after the other
+const minSideBySideWidth = 60
+
+type diffSide struct {
+	Result Result
+	Err    error
+}
+
+type termLookup func(ctx context.Context, lang, term string) (Result, error)
+
+// parseDiffTerms accepts either a comma-separated pair or two single
+// words. Terms with several words need the comma.
+func parseDiffTerms(searchTerm string) ([]string, error) {
+	var terms []string
+	if strings.Contains(searchTerm, ",") {
+		terms = strings.Split(searchTerm, ",")
+	} else {
+		terms = strings.Fields(searchTerm)
+	}
+	if len(terms) != 2 {
+		return nil, errors.New("-diff needs exactly two search terms, e.g. -diff Berlin,Paris")
+	}
+	for i, term := range terms {
+		terms[i] = strings.TrimSpace(term)
+		if terms[i] == "" {
+			return nil, errors.New("-diff needs two non-empty search terms")
+		}
+	}
+	return terms, nil
+}
+
+// fetchDiff looks up all terms concurrently and returns the results in
+// the order of the terms.
+func fetchDiff(ctx context.Context, lang string, terms []string, lookup termLookup) []diffSide {
+	sides := make([]diffSide, len(terms))
+	var wg sync.WaitGroup
+	for i, term := range terms {
+		wg.Add(1)
+		go func(i int, term string) {
+			defer wg.Done()
+			result, err := lookup(ctx, lang, term)
+			sides[i] = diffSide{Result: result, Err: err}
+		}(i, term)
+	}
+	wg.Wait()
+	return sides
+}
+
+func (s diffSide) text() string {
+	if s.Err != nil {
+		return "Error: " + s.Err.Error()
+	}
+	return s.Result.Title + "\n\n" + s.Result.Summary + "\n\n" + s.Result.URL
+}
+
+// formatSideBySide renders two texts in columns that fit into width, or
+// stacks them if the terminal is too narrow.
+func formatSideBySide(left, right string, width int) string {
+	if width < minSideBySideWidth {
+		separator := strings.Repeat("-", width)
+		return strings.Join(wrapText(left, width), "\n") + "\n" + separator + "\n" + strings.Join(wrapText(right, width), "\n") + "\n"
+	}
+
+	columnWidth := (width - 3) / 2
+	leftLines := wrapText(left, columnWidth)
+	rightLines := wrapText(right, columnWidth)
+
+	var builder strings.Builder
+	for i := 0; i < len(leftLines) || i < len(rightLines); i++ {
+		leftLine, rightLine := "", ""
+		if i < len(leftLines) {
+			leftLine = leftLines[i]
+		}
+		if i < len(rightLines) {
+			rightLine = rightLines[i]
+		}
+		padding := strings.Repeat(" ", columnWidth-utf8.RuneCountInString(leftLine))
+		builder.WriteString(strings.TrimRight(leftLine+padding+" | "+rightLine, " ") + "\n")
+	}
+	return builder.String()
+}
+
+func runDiff(ctx context.Context, lang, searchTerm string) int {
+	terms, err := parseDiffTerms(searchTerm)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitUsage
+	}
+
+	// One spinner for both lookups instead of two competing ones
+	stopLoading := startLoadingAnimation()
+	previous := spinnerEnabled
+	spinnerEnabled = false
+	sides := fetchDiff(ctx, lang, terms, lookupFirstResult)
+	spinnerEnabled = previous
+	stopLoading()
+
+	fmt.Print("\n" + formatSideBySide(sides[0].text(), sides[1].text(), terminalWidth()))
+
+	for _, side := range sides {
+		if side.Err != nil {
+			return exitCodeFor(side.Err)
+		}
+	}
+	return exitOK
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestParseDiffTerms(t *testing.T) {
+	terms, err := parseDiffTerms("New York, Paris")
+	if err != nil {
+		t.Fatalf("parseDiffTerms sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if terms[0] != "New York" || terms[1] != "Paris" {
+		t.Errorf("Erwartete [New York Paris], erhielt %q", terms)
+	}
+
+	terms, err = parseDiffTerms("Berlin Paris")
+	if err != nil || terms[0] != "Berlin" || terms[1] != "Paris" {
+		t.Errorf("Erwartete [Berlin Paris], erhielt %q (%v)", terms, err)
+	}
+
+	for _, invalid := range []string{"Berlin", "Berlin, Paris, Rom", "Berlin,"} {
+		if _, err := parseDiffTerms(invalid); err == nil {
+			t.Errorf("parseDiffTerms(%q) sollte einen Fehler liefern", invalid)
+		}
+	}
+}
+
+func TestFetchDiffConcurrent(t *testing.T) {
+	lookup := func(ctx context.Context, lang, term string) (Result, error) {
+		time.Sleep(200 * time.Millisecond)
+		if term == "Gibtesnicht" {
+			return Result{}, wiki.ErrNoResults
+		}
+		return Result{Title: term, Lang: lang}, nil
+	}
+
+	start := time.Now()
+	sides := fetchDiff(context.Background(), "de", []string{"Berlin", "Gibtesnicht"}, lookup)
+	if elapsed := time.Since(start); elapsed >= 400*time.Millisecond {
+		t.Errorf("Die Abrufe sollten gleichzeitig laufen, dauerten aber %v", elapsed)
+	}
+
+	if sides[0].Result.Title != "Berlin" || sides[0].Err != nil {
+		t.Errorf("Die erste Spalte sollte 'Berlin' enthalten: %+v", sides[0])
+	}
+	if !errors.Is(sides[1].Err, wiki.ErrNoResults) {
+		t.Errorf("Die zweite Spalte sollte den Fehler enthalten: %+v", sides[1])
+	}
+}
+
+func TestFormatSideBySide(t *testing.T) {
+	output := formatSideBySide("Berlin ist die Hauptstadt Deutschlands.", "Paris ist die Hauptstadt Frankreichs.", 60)
+
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	for _, line := range lines {
+		if len([]rune(line)) > 60 {
+			t.Errorf("Die Zeile ist breiter als 60 Zeichen: %q", line)
+		}
+		if !strings.Contains(line, " | ") {
+			t.Errorf("Jede Zeile sollte den Spaltentrenner enthalten: %q", line)
+		}
+	}
+	if !strings.HasPrefix(lines[0], "Berlin") || !strings.Contains(lines[0], "| Paris") {
+		t.Errorf("Unerwartete erste Zeile: %q", lines[0])
+	}
+}
+
+func TestFormatSideBySideNarrow(t *testing.T) {
+	output := formatSideBySide("Berlin", "Paris", 40)
+
+	if strings.Contains(output, " | ") {
+		t.Errorf("Bei schmalen Terminals sollten die Texte untereinander stehen: %q", output)
+	}
+	if !strings.HasPrefix(output, "Berlin\n") || !strings.HasSuffix(output, "Paris\n") {
+		t.Errorf("Unerwartete Ausgabe: %q", output)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// dryRunURLs returns the requests a lookup of the terms would start with,
+// for -dry-run. Which article the search finds is not known without a
+// request, so the summary or section URL assumes the term is the title.
+func dryRunURLs(langs, terms []string, section string) []string {
+	var urls []string
+	for _, term := range terms {
+		for _, lang := range langs {
+			client := newClient(lang)
+			urls = append(urls, client.SearchURL(term))
+			if section != "" {
+				urls = append(urls, client.SectionsURL(term))
+			} else {
+				urls = append(urls, client.SummaryURL(term))
+			}
+		}
+	}
+	return urls
+}
+
+func printDryRun(out io.Writer, urls []string) int {
+	for _, url := range urls {
+		fmt.Fprintln(out, url)
+	}
+	return exitOK
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDryRunURLs(t *testing.T) {
+	setTestHome(t)
+
+	urls := dryRunURLs([]string{"en"}, []string{"Albert Einstein"}, "")
+	want := []string{
+		"https://en.wikipedia.org/w/api.php?action=query&list=search&srsearch=Albert+Einstein&format=json",
+		"https://en.wikipedia.org/api/rest_v1/page/summary/Albert%20Einstein",
+	}
+	if len(urls) != len(want) {
+		t.Fatalf("Erwartete %d URLs, erhielt %q", len(want), urls)
+	}
+	for i := range want {
+		if urls[i] != want[i] {
+			t.Errorf("Erwartete\n%s\nerhielt\n%s", want[i], urls[i])
+		}
+	}
+
+	var out bytes.Buffer
+	printDryRun(&out, urls)
+	if out.String() != want[0]+"\n"+want[1]+"\n" {
+		t.Errorf("Unerwartete Ausgabe: %q", out.String())
+	}
+}
+
+func TestDryRunURLsSectionAndFallback(t *testing.T) {
+	setTestHome(t)
+
+	urls := dryRunURLs([]string{"de", "en"}, []string{"Berlin"}, "Geschichte")
+	if len(urls) != 4 {
+		t.Fatalf("Erwartete 4 URLs, erhielt %q", urls)
+	}
+	if urls[1] != "https://de.wikipedia.org/w/api.php?action=parse&page=Berlin&prop=sections&redirects=1&format=json&formatversion=2" {
+		t.Errorf("Unerwartete Abschnitts-URL: %s", urls[1])
+	}
+	if urls[2] != "https://en.wikipedia.org/w/api.php?action=query&list=search&srsearch=Berlin&format=json" {
+		t.Errorf("Die Fallback-Sprache sollte ebenfalls ausgegeben werden: %s", urls[2])
+	}
+}
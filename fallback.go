@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+// parseLangFallback returns the languages to try in order, starting with
+// lang and skipping duplicates. The fallback languages become part of the
+// host name, so names are resolved and unknown codes rejected.
+func parseLangFallback(lang, fallback string) ([]string, error) {
+	langs := []string{lang}
+	for _, candidate := range strings.Split(fallback, ",") {
+		if strings.TrimSpace(candidate) == "" {
+			continue
+		}
+		code, err := resolveLanguage(candidate)
+		if err != nil {
+			return nil, fmt.Errorf("-lang-fallback: %w", err)
+		}
+		if !containsString(langs, code) {
+			langs = append(langs, code)
+		}
+	}
+	return langs, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// searchWithFallback searches the languages in order and returns the
+// results of the first language that has any, together with it. When a
+// language has few results and the API suggests another spelling, confirm
+// is asked whether to search for it instead; nil never asks.
+func searchWithFallback(ctx context.Context, langs []string, term string, confirm func(suggestion string) bool) ([]string, string, error) {
+	for i, lang := range langs {
+		result, err := searchWithSuggestion(ctx, lang, term)
+		if err != nil {
+			return nil, lang, err
+		}
+		if confirm != nil && offerSuggestion(result) && confirm(result.Suggestion) {
+			term = result.Suggestion
+			result, err = searchWithSuggestion(ctx, lang, term)
+			if err != nil {
+				return nil, lang, err
+			}
+		}
+		if len(result.Titles) > 0 {
+			if i > 0 {
+				fmt.Fprintf(os.Stderr, "No results in %q, using %q instead.\n", langs[0], lang)
+			}
+			return result.Titles, lang, nil
+		}
+	}
+	return nil, langs[0], nil
+}
+
+// fallbackFetcher wraps fetch so that a missing article is retried in the
+// other languages. The language that served the entry is stored in it.
+func fallbackFetcher(langs []string, fetch summaryFetcher) summaryFetcher {
+	return func(ctx context.Context, lang, title string) (wiki.CacheEntry, bool, error) {
+		order := []string{lang}
+		for _, other := range langs {
+			if other != lang {
+				order = append(order, other)
+			}
+		}
+
+		var err error
+		for _, candidate := range order {
+			var entry wiki.CacheEntry
+			var cached bool
+			entry, cached, err = fetch(ctx, candidate, title)
+			if errors.Is(err, wiki.ErrArticleNotFound) {
+				continue
+			}
+			if err == nil && candidate != lang {
+				entry.Lang = candidate
+				fmt.Fprintf(os.Stderr, "No %q article for %q, showing the %q article instead.\n", lang, title, candidate)
+			}
+			return entry, cached, err
+		}
+		return wiki.CacheEntry{}, false, err
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestParseLangFallback(t *testing.T) {
+	langs, err := parseLangFallback("de", " en, de,fr,,english")
+	if err != nil || strings.Join(langs, ",") != "de,en,fr" {
+		t.Errorf("Erwartete [de en fr], erhielt %q, %v", langs, err)
+	}
+
+	if langs, _ := parseLangFallback("de", ""); len(langs) != 1 {
+		t.Errorf("Ohne Fallback sollte nur die Sprache selbst enthalten sein, erhielt %q", langs)
+	}
+
+	// Der Code landet im Hostnamen und darf ihn nicht umlenken
+	for _, fallback := range []string{"evil.com#", "en,xx", "en/../x"} {
+		if _, err := parseLangFallback("de", fallback); err == nil || !strings.Contains(err.Error(), "unknown language") {
+			t.Errorf("%q sollte abgelehnt werden, erhielt %v", fallback, err)
+		}
+	}
+}
+
+func TestRunRejectsUnknownFallback(t *testing.T) {
+	setTestHome(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	var code int
+	_, stderr := captureOutput(t, func() {
+		code = run([]string{"-lang-fallback", "evil.com#", "Berlin"})
+	})
+	if code != exitUsage || !strings.Contains(stderr, "-lang-fallback: unknown language") {
+		t.Errorf("Ein unbekannter Fallback sollte ein Bedienfehler sein: %d, %q", code, stderr)
+	}
+	if requests != 0 {
+		t.Errorf("Es sollte keine Anfrage geben, erhalten %d", requests)
+	}
+}
+
+func TestFallbackFetcherUsesNextLanguage(t *testing.T) {
+	setTestHome(t)
+
+	// Die deutsche Wikipedia kennt den Artikel nicht, die englische schon
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/de/") {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"title": "Golang", "extract": "Go is a programming language.", "content_urls": {"desktop": {"page": "https://en.wikipedia.org/wiki/Go_(programming_language)"}}}`)
+	}))
+	defer server.Close()
+
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	fetch := fallbackFetcher([]string{"de", "en"}, getWikipediaSummary)
+	entry, _, err := fetch(context.Background(), "de", "Golang")
+	if err != nil {
+		t.Fatalf("Der Fallback sollte erfolgreich sein: %v", err)
+	}
+
+	if entry.Lang != "en" {
+		t.Errorf("Erwartete Sprache 'en', erhielt '%s'", entry.Lang)
+	}
+	if result := newResult("de", "Golang", entry, false); result.Lang != "en" {
+		t.Errorf("Das Ergebnis sollte die tatsächliche Sprache melden, erhielt '%s'", result.Lang)
+	}
+
+	// Die Einträge bleiben je Sprache getrennt
+	if cached, found := fileCache().Get("de", "Golang"); !found || !cached.NotFound {
+		t.Error("Für 'de' sollte ein negativer Eintrag im Cache liegen")
+	}
+	if _, found := fileCache().Get("en", "Golang"); !found {
+		t.Error("Für 'en' sollte der Artikel im Cache liegen")
+	}
+}
+
+func TestFallbackFetcherAllMissing(t *testing.T) {
+	fetch := fallbackFetcher([]string{"de", "en"}, func(ctx context.Context, lang, title string) (wiki.CacheEntry, bool, error) {
+		return wiki.CacheEntry{}, false, wiki.ErrArticleNotFound
+	})
+
+	if _, _, err := fetch(context.Background(), "de", "Gibtesnicht"); err != wiki.ErrArticleNotFound {
+		t.Errorf("Erwartete wiki.ErrArticleNotFound, erhielt %v", err)
+	}
+}
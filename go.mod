@@ -3,8 +3,13 @@ module github.com/SvenSchneiderDVAG/wikr
 go 1.23.1
 
 require (
-	github.com/fatih/color v1.17.0 // indirect
+	github.com/fatih/color v1.17.0
+	golang.org/x/term v0.20.0
+	golang.org/x/text v0.24.0
+)
+
+require (
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
-	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/sys v0.20.0 // indirect
 )
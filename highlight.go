@@ -0,0 +1,90 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// colorSummary highlights the article title in the summary, set by
+// -color-summary.
+var colorSummary = false
+
+// maxSubjectWords limits the leading subject of a summary, longer text
+// before the first bracket or comma is a sentence rather than a name.
+const maxSubjectWords = 8
+
+// highlightSummary wraps the leading subject of the summary and every
+// occurrence of the title with paint. Wikipedia shows the subject in bold
+// at the start of an article, e.g. "Albert Einstein (1879–1955) war",
+// but the plain text of the summary has lost it, so it is recognized by
+// sharing a word with the title. Line breaks from wrapping may separate
+// the words of the title.
+func highlightSummary(summary, title string, paint func(a ...interface{}) string) string {
+	var spans [][2]int
+	if end := leadingSubject(summary, title); end > 0 {
+		spans = append(spans, [2]int{0, end})
+	}
+	if pattern := titlePattern(title); pattern != nil {
+		for _, match := range pattern.FindAllStringSubmatchIndex(summary, -1) {
+			spans = append(spans, [2]int{match[2], match[3]})
+		}
+	}
+	if len(spans) == 0 {
+		return summary
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	var s strings.Builder
+	pos := 0
+	for _, span := range spans {
+		if span[1] <= pos {
+			continue
+		}
+		start := max(span[0], pos)
+		s.WriteString(summary[pos:start])
+		s.WriteString(paint(summary[start:span[1]]))
+		pos = span[1]
+	}
+	s.WriteString(summary[pos:])
+	return s.String()
+}
+
+// titlePattern matches the title as whole words, ignoring case and how
+// the words are separated. Go's \b only knows ASCII letters, so the
+// boundaries are spelled out for titles like "Malmö".
+func titlePattern(title string) *regexp.Regexp {
+	words := strings.Fields(strings.ReplaceAll(title, "_", " "))
+	if len(words) == 0 {
+		return nil
+	}
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+	return regexp.MustCompile(`(?i)(?:^|[^\pL\pN])(` + strings.Join(words, `\s+`) + `)(?:$|[^\pL\pN])`)
+}
+
+// leadingSubject returns the end of the subject the summary starts with,
+// the text before the first bracket or comma, or 0 if there is none.
+func leadingSubject(summary, title string) int {
+	end := strings.IndexAny(summary, "([,")
+	if end <= 0 {
+		return 0
+	}
+	subject := strings.TrimRightFunc(summary[:end], unicode.IsSpace)
+	words := strings.Fields(subject)
+	if len(words) == 0 || len(words) > maxSubjectWords {
+		return 0
+	}
+	titleWords := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ReplaceAll(title, "_", " ")) {
+		titleWords[strings.ToLower(word)] = true
+	}
+	for _, word := range words {
+		if titleWords[strings.ToLower(word)] {
+			return len(subject)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestHighlightSummary(t *testing.T) {
+	mark := func(a ...interface{}) string { return "*" + fmt.Sprint(a...) + "*" }
+	tests := []struct {
+		title, summary, want string
+	}{
+		{"Albert Einstein", "Albert Einstein (1879–1955) war ein Physiker. Später zog albert einstein nach Princeton.",
+			"*Albert Einstein* (1879–1955) war ein Physiker. Später zog *albert einstein* nach Princeton."},
+		{"Deutschland", "Die Bundesrepublik Deutschland, kurz Deutschland, ist ein Bundesstaat.",
+			"*Die Bundesrepublik Deutschland*, kurz *Deutschland*, ist ein Bundesstaat."},
+		{"Berlin", "Berlin ist die Hauptstadt. Die Berliner Mauer fiel 1989.",
+			"*Berlin* ist die Hauptstadt. Die Berliner Mauer fiel 1989."},
+		{"Malmö", "Malmö [malˈmøː] ist eine Stadt. In MALMÖ leben viele.",
+			"*Malmö* [malˈmøː] ist eine Stadt. In *MALMÖ* leben viele."},
+		{"New_York_City", "New York City, oft New\nYork genannt, liegt am Atlantik.",
+			"*New York City*, oft New\nYork genannt, liegt am Atlantik."},
+		{"Rom", "Die Hauptstadt Italiens hat viele Einwohner, darunter viele Römer.",
+			"Die Hauptstadt Italiens hat viele Einwohner, darunter viele Römer."},
+	}
+	for _, test := range tests {
+		if got := highlightSummary(test.summary, test.title, mark); got != test.want {
+			t.Errorf("%s: erwartete\n%q\nerhielt\n%q", test.title, test.want, got)
+		}
+	}
+
+	// Zeilenumbrüche trennen den Titel nicht
+	if got := highlightSummary("Sie liegt in New\nYork City.", "New York City", mark); got != "Sie liegt in *New\nYork City*." {
+		t.Errorf("Der umbrochene Titel sollte hervorgehoben werden: %q", got)
+	}
+}
+
+func TestPlainWriterColorSummaryRespectsNoColor(t *testing.T) {
+	colorSummary = true
+	defer func() { colorSummary = false }()
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	var output bytes.Buffer
+	if err := (&plainWriter{out: &output}).Write(testResult); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output.String(), testResult.Summary) || strings.Contains(output.String(), "\x1b[") {
+		t.Errorf("Ohne Farben sollte die Zusammenfassung unverändert bleiben: %q", output.String())
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+const (
+	// historyFileName is the legacy name of the history in the home directory
+	historyFileName = ".wikr_history.json"
+	historySize     = 20
+)
+
+type HistoryEntry struct {
+	Lang      string    `json:"lang"`
+	Title     string    `json:"title"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// getHistoryPath returns the history file next to the config file.
+func getHistoryPath() string {
+	return wiki.ConfigFilePath("history.json", historyFileName)
+}
+
+// loadHistory returns the viewed articles, most recent first.
+func loadHistory() []HistoryEntry {
+	var history []HistoryEntry
+	historyPath := getHistoryPath()
+	data, err := os.ReadFile(historyPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Info("error reading history file", "path", historyPath, "error", err)
+		}
+		return history
+	}
+	err = json.Unmarshal(data, &history)
+	if err != nil {
+		logger.Info("error decoding history", "path", historyPath, "error", err)
+	}
+	return history
+}
+
+func saveHistory(history []HistoryEntry) {
+	data, err := json.Marshal(history)
+	if err != nil {
+		logger.Info("error encoding history", "error", err)
+		return
+	}
+	historyPath := getHistoryPath()
+	if err := os.MkdirAll(filepath.Dir(historyPath), 0700); err != nil {
+		logger.Info("error creating history directory", "path", historyPath, "error", err)
+		return
+	}
+	// The history reveals the looked up articles like the cache, so it gets
+	// the same mode. WriteFile keeps the mode of an existing file, a history
+	// written by older versions is restricted as well
+	err = os.WriteFile(historyPath, data, cacheMode)
+	if err == nil {
+		err = os.Chmod(historyPath, cacheMode)
+	}
+	if err != nil {
+		logger.Info("error writing history file", "path", historyPath, "error", err)
+	}
+}
+
+// addHistoryEntry moves the article to the top of the history and keeps
+// at most historySize entries.
+func addHistoryEntry(lang, title string) {
+	history := []HistoryEntry{{
+		Lang:      lang,
+		Title:     title,
+		Timestamp: time.Now(),
+	}}
+	for _, entry := range loadHistory() {
+		if entry.Lang == lang && entry.Title == title {
+			continue
+		}
+		history = append(history, entry)
+	}
+	if len(history) > historySize {
+		history = history[:historySize]
+	}
+	saveHistory(history)
+}
+
+func clearHistory() error {
+	historyPath := getHistoryPath()
+	err := os.Remove(historyPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error deleting history file: %v", err)
+	}
+	return nil
+}
+
+func printHistory(history []HistoryEntry) {
+	if len(history) == 0 {
+		fmt.Println("History is empty.")
+		return
+	}
+	for i, entry := range history {
+		fmt.Printf("%d. [%s] %s (%s)\n", i+1, entry.Lang, entry.Title, entry.Timestamp.Format("2006-01-02 15:04"))
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestAddHistoryEntry(t *testing.T) {
+	setTestHome(t)
+
+	addHistoryEntry("de", "Berlin")
+	addHistoryEntry("en", "Paris")
+	addHistoryEntry("de", "Berlin")
+
+	history := loadHistory()
+	if len(history) != 2 {
+		t.Fatalf("Erwartete 2 Einträge in der Historie, erhielt %d", len(history))
+	}
+
+	// Der zuletzt angesehene Artikel sollte oben stehen
+	if history[0].Lang != "de" || history[0].Title != "Berlin" {
+		t.Errorf("Erwartete 'de:Berlin' als ersten Eintrag, erhielt '%s:%s'", history[0].Lang, history[0].Title)
+	}
+
+	if history[1].Title != "Paris" {
+		t.Errorf("Erwartete 'Paris' als zweiten Eintrag, erhielt '%s'", history[1].Title)
+	}
+}
+
+func TestHistorySizeLimit(t *testing.T) {
+	setTestHome(t)
+
+	for i := 0; i < historySize+5; i++ {
+		addHistoryEntry("de", fmt.Sprintf("Artikel %d", i))
+	}
+
+	history := loadHistory()
+	if len(history) != historySize {
+		t.Errorf("Die Historie sollte höchstens %d Einträge enthalten, erhielt %d", historySize, len(history))
+	}
+}
+
+func TestClearHistory(t *testing.T) {
+	setTestHome(t)
+
+	addHistoryEntry("de", "Berlin")
+	if err := clearHistory(); err != nil {
+		t.Fatalf("clearHistory sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	if history := loadHistory(); len(history) != 0 {
+		t.Errorf("Die Historie sollte nach dem Löschen leer sein, erhielt %d Einträge", len(history))
+	}
+}
+
+func TestHistoryFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows kennt keine Unix-Rechte")
+	}
+	setTestHome(t)
+
+	// Eine Historie älterer Versionen war für alle lesbar
+	path := getHistoryPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	addHistoryEntry("de", "Berlin")
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode&0o077 != 0 {
+		t.Errorf("Die Historie sollte nur für den Besitzer lesbar sein, Modus %v", mode)
+	}
+}
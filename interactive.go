@@ -0,0 +1,342 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// searchDebounce is how long typing has to pause before -interactive-search
+// starts a search.
+const searchDebounce = 300 * time.Millisecond
+
+// minSearchInterval spaces the searches of -interactive-search, so fast
+// typing does not run into the rate limit of Wikipedia.
+const minSearchInterval = time.Second
+
+const searchBoxPrompt = "Search: "
+
+// nextSearchAt returns when the search for the latest input may start:
+// once the input has been quiet for debounce, and not before interval has
+// passed since the previous search.
+func nextSearchAt(lastInput, lastSearch time.Time, debounce, interval time.Duration) time.Time {
+	at := lastInput.Add(debounce)
+	if !lastSearch.IsZero() && lastSearch.Add(interval).After(at) {
+		at = lastSearch.Add(interval)
+	}
+	return at
+}
+
+type searchBoxKey int
+
+const (
+	boxNone searchBoxKey = iota
+	boxText
+	boxBackspace
+	boxUp
+	boxDown
+	boxEnter
+	boxCancel
+)
+
+// parseSearchBoxInput maps the bytes of one key press in the search box.
+// Unlike in the result menu, letters like q and j are part of the query.
+func parseSearchBoxInput(input []byte) (searchBoxKey, string) {
+	switch {
+	case len(input) == 0:
+		return boxNone, ""
+	case string(input) == "\x1b[A" || string(input) == "\x1bOA":
+		return boxUp, ""
+	case string(input) == "\x1b[B" || string(input) == "\x1bOB":
+		return boxDown, ""
+	case input[0] == '\r' || input[0] == '\n':
+		return boxEnter, ""
+	case string(input) == "\x1b" || input[0] == 3: // 3 is Ctrl-C in raw mode
+		return boxCancel, ""
+	case input[0] == 127 || input[0] == '\b':
+		return boxBackspace, ""
+	case input[0] == '\x1b':
+		// Other escape sequences, e.g. the left and right arrow keys
+		return boxNone, ""
+	}
+	var text strings.Builder
+	for _, r := range string(input) {
+		if r != utf8.RuneError && unicode.IsPrint(r) {
+			text.WriteRune(r)
+		}
+	}
+	if text.Len() == 0 {
+		return boxNone, ""
+	}
+	return boxText, text.String()
+}
+
+// searchBox is the state of -interactive-search: the query typed so far
+// and the results of the latest search.
+type searchBox struct {
+	query   []rune
+	results []string
+	cursor  int
+	// searched is the query the results belong to
+	searched string
+	err      error
+}
+
+// edit applies a key to the query and the cursor and reports whether the
+// query changed.
+func (b *searchBox) edit(key searchBoxKey, text string) bool {
+	switch key {
+	case boxText:
+		b.query = append(b.query, []rune(text)...)
+		return true
+	case boxBackspace:
+		if len(b.query) == 0 {
+			return false
+		}
+		b.query = b.query[:len(b.query)-1]
+		return true
+	case boxUp:
+		if len(b.results) > 0 {
+			b.cursor = moveCursor(b.cursor, len(b.results), keyUp)
+		}
+	case boxDown:
+		if len(b.results) > 0 {
+			b.cursor = moveCursor(b.cursor, len(b.results), keyDown)
+		}
+	}
+	return false
+}
+
+// setResults shows the results of a search unless the query has changed
+// since it started.
+func (b *searchBox) setResults(query string, results []string, err error) bool {
+	if query != string(b.query) {
+		return false
+	}
+	b.results, b.err, b.searched, b.cursor = results, err, query, 0
+	return true
+}
+
+// draw writes the query line and the results below it. The cursor is
+// left at the end of the query, where the next draw starts over.
+func (b *searchBox) draw(out io.Writer) {
+	var s strings.Builder
+	s.WriteString("\r\x1b[J" + searchBoxPrompt + string(b.query))
+	lines := 0
+	line := func(text string) {
+		s.WriteString("\r\n" + text)
+		lines++
+	}
+	switch {
+	case b.err != nil:
+		line(activeTheme.Error.Sprintf("  Error: %v", b.err))
+	case len(b.query) > 0 && b.searched == string(b.query) && len(b.results) == 0:
+		line("  No results found.")
+	}
+	for i, result := range b.results {
+		if i == b.cursor {
+			line(activeTheme.Title.Sprint("> " + result))
+		} else {
+			line("  " + result)
+		}
+	}
+	if lines > 0 {
+		fmt.Fprintf(&s, "\x1b[%dA", lines)
+	}
+	fmt.Fprintf(&s, "\r\x1b[%dC", utf8.RuneCountInString(searchBoxPrompt)+len(b.query))
+	fmt.Fprint(out, s.String())
+}
+
+var (
+	searchKeysOnce sync.Once
+	searchKeys     chan []byte
+)
+
+// searchBoxKeys returns the key presses read from stdin. A blocked read
+// cannot be stopped when the search box closes, so the goroutine reading
+// them is started once and shared by all calls instead of one per call.
+// The channel is closed when stdin ends.
+func searchBoxKeys() <-chan []byte {
+	searchKeysOnce.Do(func() {
+		searchKeys = make(chan []byte)
+		go func() {
+			buf := make([]byte, 16)
+			for {
+				n, err := stdinReader.Read(buf)
+				if err != nil {
+					close(searchKeys)
+					return
+				}
+				searchKeys <- append([]byte(nil), buf[:n]...)
+			}
+		}()
+	})
+	return searchKeys
+}
+
+// interactiveSearch shows live results while the user types and returns
+// the title chosen with Enter. The boolean is false when the user
+// cancelled. Searches start after a pause in typing and are spaced by
+// minSearchInterval; results of an outdated query are dropped.
+func interactiveSearch(ctx context.Context, max int, search func(ctx context.Context, query string) ([]string, error)) (string, bool, error) {
+	fd := int(os.Stdin.Fd())
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", false, err
+	}
+	defer term.Restore(fd, oldState)
+	setTerminalRestore(func() { term.Restore(fd, oldState) })
+	defer setTerminalRestore(nil)
+	doneWaiting := waitForInput()
+	defer doneWaiting()
+
+	keys := searchBoxKeys()
+	type searchDone struct {
+		query   string
+		results []string
+		err     error
+	}
+	found := make(chan searchDone, 1)
+	box := &searchBox{}
+	var lastInput, lastSearch time.Time
+	var timer *time.Timer
+	var timerC <-chan time.Time
+	pending, searching := false, false
+	schedule := func() {
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.NewTimer(time.Until(nextSearchAt(lastInput, lastSearch, searchDebounce, minSearchInterval)))
+		timerC = timer.C
+	}
+	finish := func() {
+		fmt.Fprint(promptOut, "\r\x1b[J")
+	}
+
+	box.draw(promptOut)
+	for {
+		select {
+		case <-ctx.Done():
+			finish()
+			return "", false, ctx.Err()
+		case input, ok := <-keys:
+			if !ok {
+				finish()
+				return "", false, io.ErrUnexpectedEOF
+			}
+			key, text := parseSearchBoxInput(input)
+			switch key {
+			case boxEnter:
+				if len(box.results) > 0 {
+					finish()
+					return box.results[box.cursor], true, nil
+				}
+			case boxCancel:
+				finish()
+				return "", false, nil
+			}
+			if box.edit(key, text) {
+				lastInput = time.Now()
+				pending = true
+				if strings.TrimSpace(string(box.query)) == "" {
+					// Nothing to search for, the results are cleared
+					box.setResults(string(box.query), nil, nil)
+					pending, timerC = false, nil
+				} else if !searching {
+					schedule()
+				}
+			}
+			box.draw(promptOut)
+		case <-timerC:
+			timerC = nil
+			query := string(box.query)
+			pending, searching = false, true
+			lastSearch = time.Now()
+			go func() {
+				results, err := search(ctx, query)
+				found <- searchDone{query: query, results: results, err: err}
+			}()
+		case done := <-found:
+			searching = false
+			results := done.results
+			if len(results) > max {
+				results = results[:max]
+			}
+			if box.setResults(done.query, results, done.err) {
+				box.draw(promptOut)
+			}
+			if pending {
+				schedule()
+			}
+		}
+	}
+}
+
+// readSearchQuery asks for the query on a line of its own, the fallback of
+// -interactive-search without a terminal.
+func readSearchQuery() string {
+	fmt.Fprint(promptOut, searchBoxPrompt)
+	done := waitForInput()
+	input, _ := stdinReader.ReadString('\n')
+	done()
+	return strings.TrimSpace(input)
+}
+
+// runInteractiveSearch looks up the article picked in the live search box,
+// or, without a terminal, in the results of a query read from stdin.
+func runInteractiveSearch(ctx context.Context, lang string, maxResults *int, output OutputWriter, copy bool) int {
+	search := func(ctx context.Context, query string) ([]string, error) {
+		return searchWikipedia(ctx, lang, query)
+	}
+
+	var title string
+	if arrowMenuAvailable() {
+		chosen, ok, err := interactiveSearch(ctx, *maxResults, search)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return exitCodeFor(err)
+		}
+		if !ok {
+			fmt.Fprintln(promptOut, "Program was exited.")
+			return exitOK
+		}
+		title = chosen
+	} else {
+		query := readSearchQuery()
+		if query == "" {
+			fmt.Fprintln(os.Stderr, "Error: search term is required")
+			return exitUsage
+		}
+		stopLoading := startLoadingAnimation()
+		results, err := search(ctx, query)
+		stopLoading()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error during search:", err)
+			return exitCodeFor(err)
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(os.Stderr, "No results found.")
+			return exitNoResults
+		}
+		title = results[0]
+		if len(results) > 1 {
+			title = chooseResult(results, maxResults)
+		}
+	}
+
+	// getWikipediaSummary shows its own spinner when it has to fetch
+	entry, cached, err := getWikipediaSummary(ctx, lang, title)
+	if err != nil {
+		activeTheme.Error.Fprintf(os.Stderr, "Error fetching summary: %v\n", err)
+		return exitCodeFor(err)
+	}
+	return showEntry(lang, entry, cached, output, copy)
+}
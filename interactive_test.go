@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestNextSearchAt(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	debounce, interval := 300*time.Millisecond, time.Second
+
+	tests := []struct {
+		name       string
+		lastInput  time.Time
+		lastSearch time.Time
+		want       time.Time
+	}{
+		{"Erste Suche nach der Pause", start, time.Time{}, start.Add(debounce)},
+		{"Weiteres Tippen verschiebt die Suche", start.Add(200 * time.Millisecond), time.Time{}, start.Add(500 * time.Millisecond)},
+		{"Gedrosselt nach einer Suche", start, start.Add(-100 * time.Millisecond), start.Add(900 * time.Millisecond)},
+		{"Lange nach der letzten Suche", start, start.Add(-time.Minute), start.Add(debounce)},
+	}
+	for _, test := range tests {
+		if got := nextSearchAt(test.lastInput, test.lastSearch, debounce, interval); !got.Equal(test.want) {
+			t.Errorf("%s: erwartete %s, erhielt %s", test.name, test.want.Sub(start), got.Sub(start))
+		}
+	}
+}
+
+func TestParseSearchBoxInput(t *testing.T) {
+	tests := []struct {
+		input string
+		key   searchBoxKey
+		text  string
+	}{
+		{"q", boxText, "q"},
+		{"j", boxText, "j"},
+		{"ä", boxText, "ä"},
+		{"Berlin", boxText, "Berlin"},
+		{"\x1b[A", boxUp, ""},
+		{"\x1b[B", boxDown, ""},
+		{"\x1b[C", boxNone, ""},
+		{"\x7f", boxBackspace, ""},
+		{"\r", boxEnter, ""},
+		{"\x1b", boxCancel, ""},
+		{"\x03", boxCancel, ""},
+		{"\t", boxNone, ""},
+	}
+	for _, test := range tests {
+		key, text := parseSearchBoxInput([]byte(test.input))
+		if key != test.key || text != test.text {
+			t.Errorf("%q: erwartete (%d, %q), erhielt (%d, %q)", test.input, test.key, test.text, key, text)
+		}
+	}
+}
+
+func TestSearchBoxDropsOutdatedResults(t *testing.T) {
+	box := &searchBox{}
+	box.edit(boxText, "Berl")
+	box.edit(boxText, "in")
+	if box.setResults("Berl", []string{"Berlingen"}, nil) {
+		t.Error("Ergebnisse einer veralteten Eingabe sollten verworfen werden")
+	}
+	if !box.setResults("Berlin", []string{"Berlin", "Berlin-Mitte"}, nil) {
+		t.Fatal("Ergebnisse der aktuellen Eingabe sollten übernommen werden")
+	}
+	box.edit(boxDown, "")
+	box.edit(boxDown, "")
+	if box.cursor != 0 {
+		t.Errorf("Der Cursor sollte umlaufen, erhielt %d", box.cursor)
+	}
+	if box.edit(boxUp, "") || !box.edit(boxBackspace, "") || string(box.query) != "Berli" {
+		t.Errorf("Unerwartete Eingabe nach Rücktaste: %q", string(box.query))
+	}
+
+	var out strings.Builder
+	box.setResults("Berli", nil, errors.New("rate limited"))
+	box.draw(&out)
+	if !strings.Contains(out.String(), searchBoxPrompt+"Berli") || !strings.Contains(out.String(), "rate limited") {
+		t.Errorf("Die Eingabe und der Fehler sollten erscheinen: %q", out.String())
+	}
+}
+
+func TestRunInteractiveSearchFallback(t *testing.T) {
+	setTestHome(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if term := r.URL.Query().Get("srsearch"); term != "" {
+			fmt.Fprintf(w, `{"query": {"search": [{"title": %q}]}}`, term)
+			return
+		}
+		fmt.Fprint(w, `{"title": "Berlin", "extract": "Berlin ist die Hauptstadt.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+	}))
+	defer server.Close()
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	// Ohne Terminal wird die Suche zeilenweise gelesen
+	stdinReader = bufio.NewReader(strings.NewReader("Berlin\n"))
+	defer func() { stdinReader = bufio.NewReader(os.Stdin) }()
+
+	var code int
+	stdout, _ := captureOutput(t, func() {
+		code = run([]string{"-interactive-search"})
+	})
+	if code != exitOK {
+		t.Fatalf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+	if !strings.Contains(stdout, "Berlin ist die Hauptstadt.") {
+		t.Errorf("Die Zusammenfassung sollte nach der Eingabe erscheinen: %q", stdout)
+	}
+}
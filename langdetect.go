@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// langSearch holds the search results of one language for
+// -lang-detect-query.
+type langSearch struct {
+	Lang    string
+	Results []string
+	Err     error
+}
+
+// parseDetectLangs splits the comma-separated list of -lang-detect-query,
+// resolves language names like -lang does and rejects languages wikr does
+// not know.
+func parseDetectLangs(list string) ([]string, error) {
+	var langs []string
+	for _, candidate := range strings.Split(list, ",") {
+		if strings.TrimSpace(candidate) == "" {
+			continue
+		}
+		lang, err := resolveLanguage(candidate)
+		if err != nil {
+			return nil, fmt.Errorf("-lang-detect-query: %w", err)
+		}
+		if !containsString(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+	if len(langs) == 0 {
+		return nil, fmt.Errorf("-lang-detect-query needs at least one language, e.g. de,en,fr")
+	}
+	return langs, nil
+}
+
+// searchLanguages searches the term in all languages with a bounded
+// number of workers. The searches are returned in the order of langs.
+func searchLanguages(ctx context.Context, langs []string, term string, workers int) []langSearch {
+	searches := make([]langSearch, len(langs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results, err := searchWikipedia(ctx, langs[i], term)
+				searches[i] = langSearch{Lang: langs[i], Results: results, Err: err}
+			}
+		}()
+	}
+	for i := range langs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return searches
+}
+
+// printLangSearches lists the results grouped by language, languages with
+// results first.
+func printLangSearches(w io.Writer, term string, searches []langSearch) {
+	sorted := append([]langSearch(nil), searches...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(sorted[i].Results) > 0 && len(sorted[j].Results) == 0
+	})
+	fmt.Fprintf(w, "\nResults for %q by language:\n", term)
+	for _, search := range sorted {
+		switch {
+		case search.Err != nil:
+			fmt.Fprintf(w, "  %s: error: %v\n", search.Lang, search.Err)
+		case len(search.Results) == 0:
+			fmt.Fprintf(w, "  %s: no results\n", search.Lang)
+		default:
+			fmt.Fprintf(w, "  %s: %s\n", search.Lang, strings.Join(search.Results, ", "))
+		}
+	}
+}
+
+// languagesWithResults returns the searches that found something.
+func languagesWithResults(searches []langSearch) []langSearch {
+	var found []langSearch
+	for _, search := range searches {
+		if search.Err == nil && len(search.Results) > 0 {
+			found = append(found, search)
+		}
+	}
+	return found
+}
+
+// chooseLanguage lets the user pick one of the languages with results. A
+// single language is used without asking.
+func chooseLanguage(found []langSearch) langSearch {
+	if len(found) == 1 {
+		return found[0]
+	}
+	labels := make([]string, len(found))
+	for i, search := range found {
+		labels[i] = fmt.Sprintf("%s (%d results)", search.Lang, len(search.Results))
+	}
+	all := len(labels)
+	choice := chooseResult(labels, &all)
+	for i, label := range labels {
+		if label == choice {
+			return found[i]
+		}
+	}
+	return found[0]
+}
+
+// detectLanguage searches the term in all languages, shows where it was
+// found and returns the results of the language the user picked. It only
+// fails if every search failed.
+func detectLanguage(ctx context.Context, langs []string, term string, workers int) ([]string, string, error) {
+	searches := searchLanguages(ctx, langs, term, workers)
+	printLangSearches(promptOut, term, searches)
+
+	found := languagesWithResults(searches)
+	if len(found) == 0 {
+		for _, search := range searches {
+			if search.Err == nil {
+				return nil, langs[0], nil
+			}
+		}
+		return nil, langs[0], searches[0].Err
+	}
+	chosen := chooseLanguage(found)
+	return chosen.Results, chosen.Lang, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	setTestHome(t)
+
+	// Nur die englische Wikipedia kennt den Begriff
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/en/") {
+			fmt.Fprint(w, `{"query": {"search": [{"title": "Brexit"}, {"title": "Brexit referendum"}]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"query": {"search": []}}`)
+	}))
+	defer server.Close()
+
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	searches := searchLanguages(context.Background(), []string{"de", "en"}, "Brexit", 2)
+	if searches[0].Lang != "de" || len(searches[0].Results) != 0 {
+		t.Errorf("Für de sollten keine Ergebnisse gefunden werden: %+v", searches[0])
+	}
+	if searches[1].Lang != "en" || len(searches[1].Results) != 2 {
+		t.Errorf("Für en sollten zwei Ergebnisse gefunden werden: %+v", searches[1])
+	}
+
+	var output bytes.Buffer
+	printLangSearches(&output, "Brexit", searches)
+	want := "\nResults for \"Brexit\" by language:\n  en: Brexit, Brexit referendum\n  de: no results\n"
+	if output.String() != want {
+		t.Errorf("Ausgabe = %q, erwartet %q", output.String(), want)
+	}
+
+	// Mit nur einer passenden Sprache wird nicht nachgefragt
+	results, lang, err := detectLanguage(context.Background(), []string{"de", "en"}, "Brexit", 2)
+	if err != nil {
+		t.Fatalf("detectLanguage sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if lang != "en" || len(results) != 2 {
+		t.Errorf("Erwartet en mit zwei Ergebnissen, erhielt %s mit %v", lang, results)
+	}
+}
+
+func TestParseDetectLangs(t *testing.T) {
+	langs, err := parseDetectLangs(" de, en,de ,fr")
+	if err != nil {
+		t.Fatalf("parseDetectLangs sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if strings.Join(langs, ",") != "de,en,fr" {
+		t.Errorf("Erwartete de,en,fr, erhielt %v", langs)
+	}
+
+	// Sprachnamen gelten wie bei -lang
+	langs, err = parseDetectLangs("german,français, de")
+	if err != nil || strings.Join(langs, ",") != "de,fr" {
+		t.Errorf("Erwartete de,fr, erhielt %v, %v", langs, err)
+	}
+
+	for _, invalid := range []string{"", " , ", "de,xx", "klingonisch"} {
+		if _, err := parseDetectLangs(invalid); err == nil {
+			t.Errorf("%q sollte abgelehnt werden", invalid)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// langGuessModes are the values of -lang-guess: off keeps -lang, ask
+// offers the guessed language, auto switches to it.
+var langGuessModes = []string{"off", "ask", "auto"}
+
+// checkLangGuess reports an unknown -lang-guess value.
+func checkLangGuess(mode string) error {
+	if !containsString(langGuessModes, mode) {
+		return fmt.Errorf("unknown -lang-guess mode %q, available modes: %s", mode, strings.Join(langGuessModes, ", "))
+	}
+	return nil
+}
+
+// scriptLanguages maps the scripts that point to one language edition to
+// its code. Latin is missing on purpose, it is used by too many languages.
+var scriptLanguages = []struct {
+	script *unicode.RangeTable
+	lang   string
+}{
+	{unicode.Cyrillic, "ru"},
+	{unicode.Greek, "el"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Thai, "th"},
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Han, "zh"},
+}
+
+// ukrainianLetters are Cyrillic letters that Russian does not use.
+const ukrainianLetters = "іїєґІЇЄҐ"
+
+// guessLanguage returns the language edition suggested by the script of
+// most letters of the term, or false when they are Latin or mixed evenly.
+// Han characters count as Japanese once there is any kana, Cyrillic as
+// Ukrainian once there is a letter only Ukrainian uses.
+func guessLanguage(term string) (string, bool) {
+	counts := map[string]int{}
+	latin := 0
+	for _, r := range term {
+		if unicode.Is(unicode.Latin, r) {
+			latin++
+			continue
+		}
+		for _, s := range scriptLanguages {
+			if unicode.Is(s.script, r) {
+				counts[s.lang]++
+				break
+			}
+		}
+	}
+	if counts["ja"] > 0 {
+		counts["ja"] += counts["zh"]
+		delete(counts, "zh")
+	}
+	if counts["ru"] > 0 && strings.ContainsAny(term, ukrainianLetters) {
+		counts["uk"] = counts["ru"]
+		delete(counts, "ru")
+	}
+
+	best, bestCount, tie := "", latin, false
+	for lang, count := range counts {
+		switch {
+		case count > bestCount:
+			best, bestCount, tie = lang, count, false
+		case count == bestCount:
+			tie = true
+		}
+	}
+	if best == "" || tie {
+		return "", false
+	}
+	return best, true
+}
+
+// askLangGuess asks whether to search the guessed language instead, the
+// default is yes.
+func askLangGuess(lang, guess string) bool {
+	fmt.Fprintf(promptOut, "The search term looks %s, search the %s Wikipedia instead of %s? [Y/n] ", supportedLanguages[guess], guess, lang)
+	done := waitForInput()
+	input, _ := stdinReader.ReadString('\n')
+	done()
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "n", "no":
+		return false
+	}
+	return true
+}
+
+// applyLangGuess returns the language to search the term in under the
+// mode. confirm is asked in ask mode, auto mode only tells on stderr.
+func applyLangGuess(mode, lang, term string, confirm func(lang, guess string) bool) string {
+	if mode == "off" {
+		return lang
+	}
+	guess, ok := guessLanguage(term)
+	if !ok || guess == lang {
+		return lang
+	}
+	if mode == "ask" {
+		if confirm(lang, guess) {
+			return guess
+		}
+		return lang
+	}
+	fmt.Fprintf(os.Stderr, "The search term looks %s, searching the %s Wikipedia.\n", supportedLanguages[guess], guess)
+	return guess
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestGuessLanguage(t *testing.T) {
+	tests := []struct {
+		term string
+		want string
+	}{
+		{"Москва", "ru"},
+		{"Київ", "uk"},
+		{"Αθήνα", "el"},
+		{"القاهرة", "ar"},
+		{"ירושלים", "he"},
+		{"दिल्ली", "hi"},
+		{"กรุงเทพมหานคร", "th"},
+		{"서울", "ko"},
+		{"東京タワー", "ja"},
+		{"とうきょう", "ja"},
+		{"北京", "zh"},
+		{"Москва Kreml", "ru"},
+		{"Berlin", ""},
+		{"Ærø", ""},
+		{"Moscow Москв", ""},
+		{"1990", ""},
+	}
+	for _, tt := range tests {
+		got, ok := guessLanguage(tt.term)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("guessLanguage(%q) = %q, %v, erwartet %q", tt.term, got, ok, tt.want)
+		}
+	}
+}
+
+func TestApplyLangGuess(t *testing.T) {
+	never := func(lang, guess string) bool {
+		t.Errorf("Es sollte nicht nach %s gefragt werden", guess)
+		return false
+	}
+	if got := applyLangGuess("off", "de", "Москва", never); got != "de" {
+		t.Errorf("off sollte die Sprache behalten, erhalten %q", got)
+	}
+	if got := applyLangGuess("ask", "de", "Berlin", never); got != "de" {
+		t.Errorf("Ohne Vermutung sollte die Sprache bleiben, erhalten %q", got)
+	}
+	if got := applyLangGuess("ask", "ru", "Москва", never); got != "ru" {
+		t.Errorf("Eine passende Sprache sollte bleiben, erhalten %q", got)
+	}
+
+	var asked string
+	got := applyLangGuess("ask", "de", "서울", func(lang, guess string) bool {
+		asked = lang + "→" + guess
+		return false
+	})
+	if got != "de" || asked != "de→ko" {
+		t.Errorf("Abgelehnt sollte die Sprache bleiben: %q, gefragt %q", got, asked)
+	}
+	if got := applyLangGuess("ask", "de", "서울", func(string, string) bool { return true }); got != "ko" {
+		t.Errorf("Angenommen sollte ko gelten, erhalten %q", got)
+	}
+
+	var got2 string
+	_, stderr := captureOutput(t, func() {
+		got2 = applyLangGuess("auto", "de", "Αθήνα", never)
+	})
+	if got2 != "el" || !strings.Contains(stderr, "searching the el Wikipedia") {
+		t.Errorf("auto sollte mit Hinweis wechseln: %q, %q", got2, stderr)
+	}
+}
+
+func TestRunLangGuess(t *testing.T) {
+	setTestHome(t)
+
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Query().Get("list") == "search" {
+			fmt.Fprint(w, `{"query": {"search": [{"title": "Москва"}]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"title": "Москва", "extract": "Москва — столица России.", "content_urls": {"desktop": {"page": "https://ru.wikipedia.org/wiki/Москва"}}}`)
+	}))
+	defer server.Close()
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	var code int
+	stdout, _ := captureOutput(t, func() {
+		code = run([]string{"-lang-guess", "auto", "Москва"})
+	})
+	if code != exitOK || !strings.Contains(stdout, "столица России") {
+		t.Fatalf("Die russische Zusammenfassung sollte erscheinen: %d, %q", code, stdout)
+	}
+	for _, path := range paths {
+		if !strings.HasPrefix(path, "/ru/") {
+			t.Errorf("Alle Anfragen sollten an ru gehen, erhalten %q", path)
+		}
+	}
+
+	// Ein ausdrückliches -lang gewinnt über die Vermutung
+	paths = nil
+	captureOutput(t, func() {
+		code = run([]string{"-lang", "de", "-lang-guess", "auto", "Москва"})
+	})
+	if len(paths) == 0 || !strings.HasPrefix(paths[0], "/de/") {
+		t.Errorf("Mit -lang sollte de gesucht werden, erhalten %v", paths)
+	}
+
+	_, stderr := captureOutput(t, func() {
+		code = run([]string{"-lang-guess", "always", "Москва"})
+	})
+	if code != exitUsage || !strings.Contains(stderr, "unknown -lang-guess mode") {
+		t.Errorf("Ein unbekannter Modus sollte ein Bedienfehler sein: %d, %q", code, stderr)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+// langStats sums up the cached articles of one language.
+type langStats struct {
+	Lang       string `json:"lang"`
+	Entries    int    `json:"entries"`
+	Characters int    `json:"characters"`
+	// AverageAge is how long ago the articles were cached on average
+	AverageAge time.Duration `json:"-"`
+	AgeSeconds int64         `json:"average_age_seconds"`
+}
+
+// keyLang returns the language of a cache key. Keys may start with a
+// project, e.g. "wiktionary:en:Word", and titles may contain colons, so
+// the first segment that is a supported language wins.
+func keyLang(key string) (string, bool) {
+	for _, segment := range strings.Split(key, ":") {
+		if _, ok := supportedLanguages[segment]; ok {
+			return segment, true
+		}
+	}
+	return "", false
+}
+
+// collectLangStats groups the cached articles by language, most used
+// language first. Redirects, page ID lookups, language versions and
+// titles without an article hold no summary and are not counted.
+func collectLangStats(cache wiki.Cache, now time.Time) []langStats {
+	byLang := make(map[string]*langStats)
+	totalAge := make(map[string]time.Duration)
+	for key, entry := range cache {
+		if entry.NotFound || entry.RedirectTo != "" || strings.HasPrefix(key, "pageid:") || strings.HasPrefix(key, "langlinks:") {
+			continue
+		}
+		lang, ok := keyLang(key)
+		if !ok {
+			continue
+		}
+		stats, ok := byLang[lang]
+		if !ok {
+			stats = &langStats{Lang: lang}
+			byLang[lang] = stats
+		}
+		stats.Entries++
+		stats.Characters += utf8.RuneCountInString(entry.Summary)
+		totalAge[lang] += now.Sub(entry.Timestamp)
+	}
+
+	result := make([]langStats, 0, len(byLang))
+	for lang, stats := range byLang {
+		stats.AverageAge = totalAge[lang] / time.Duration(stats.Entries)
+		stats.AgeSeconds = int64(stats.AverageAge / time.Second)
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Entries != result[j].Entries {
+			return result[i].Entries > result[j].Entries
+		}
+		return result[i].Lang < result[j].Lang
+	})
+	return result
+}
+
+func printLangStats(out io.Writer, stats []langStats, asJSON bool) error {
+	if asJSON {
+		data, err := marshalJSON(stats)
+		if err != nil {
+			return fmt.Errorf("error encoding language statistics: %v", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+	if len(stats) == 0 {
+		fmt.Fprintln(out, "The cache is empty.")
+		return nil
+	}
+	fmt.Fprintf(out, "%-7s %8s %11s  %s\n", "Lang", "Entries", "Characters", "Average age")
+	for _, s := range stats {
+		fmt.Fprintf(out, "%-7s %8d %11d  %s\n", s.Lang, s.Entries, s.Characters, strings.TrimSuffix(formatAge(s.AverageAge), " ago"))
+	}
+	return nil
+}
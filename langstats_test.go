@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestCollectLangStats(t *testing.T) {
+	now := time.Now()
+	cache := wiki.Cache{
+		"de:Berlin":             {Summary: "Hauptstadt", Timestamp: now.Add(-2 * time.Hour)},
+		"de:München":            {Summary: "Großstadt", Timestamp: now.Add(-4 * time.Hour)},
+		"de:Star_Trek:_Voyager": {Summary: "Serie", Timestamp: now.Add(-6 * time.Hour)},
+		"en:Paris":              {Summary: "Capital", Timestamp: now.Add(-time.Hour)},
+		"wiktionary:en:Word":    {Summary: "Noun", Timestamp: now.Add(-3 * time.Hour)},
+		"de:Gibtesnicht":        {NotFound: true, Timestamp: now},
+		"de:Hauptstadt_Berlin":  {RedirectTo: "Berlin", Timestamp: now},
+		"pageid:de:2013":        {Title: "Berlin", Timestamp: now},
+		"unbekannt:Irgendetwas": {Summary: "?", Timestamp: now},
+	}
+
+	stats := collectLangStats(cache, now)
+	if len(stats) != 2 {
+		t.Fatalf("Erwartete 2 Sprachen, erhielt %+v", stats)
+	}
+	de, en := stats[0], stats[1]
+	if de.Lang != "de" || de.Entries != 3 || de.Characters != 24 || de.AverageAge != 4*time.Hour {
+		t.Errorf("Unerwartete Statistik für de: %+v", de)
+	}
+	if en.Lang != "en" || en.Entries != 2 || en.Characters != 11 || en.AverageAge != 2*time.Hour {
+		t.Errorf("Unerwartete Statistik für en: %+v", en)
+	}
+}
+
+func TestRunLangStatsJSON(t *testing.T) {
+	setTestHome(t)
+	fileCache().Set("de", "Berlin", wiki.CacheEntry{Summary: "Hauptstadt"})
+	fileCache().Set("en", "Paris", wiki.CacheEntry{Summary: "Capital"})
+
+	var code int
+	stdout, _ := captureOutput(t, func() {
+		code = run([]string{"-lang-stats", "-json"})
+	})
+	if code != exitOK {
+		t.Fatalf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+	var stats []langStats
+	if err := json.Unmarshal([]byte(stdout), &stats); err != nil {
+		t.Fatalf("Die Ausgabe sollte JSON sein: %v, %q", err, stdout)
+	}
+	if got := fmt.Sprintf("%s %d %s %d", stats[0].Lang, stats[0].Characters, stats[1].Lang, stats[1].Characters); got != "de 10 en 7" {
+		t.Errorf("Unerwartete Statistik: %s", got)
+	}
+
+	stdout, _ = captureOutput(t, func() {
+		run([]string{"-lang-stats"})
+	})
+	if !strings.Contains(stdout, "Average age") || !strings.Contains(stdout, "de") {
+		t.Errorf("Die Tabelle sollte die Sprachen zeigen: %q", stdout)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type nextAction int
+
+const (
+	nextExit nextAction = iota
+	nextBack
+	nextSwap
+)
+
+// nextPrompt lists the choices after a summary was shown.
+func nextPrompt(offerBack, offerSwap bool, swapLang string) string {
+	var choices []string
+	if offerBack {
+		choices = append(choices, "'b' to go back to the results")
+	}
+	if offerSwap {
+		choices = append(choices, fmt.Sprintf("'e' to read the article in %s", swapLang))
+	}
+	return fmt.Sprintf("\nEnter %s (or press Enter to exit): ", strings.Join(choices, ", "))
+}
+
+// askNext asks what to do after a summary was shown. Without anything to
+// offer it does not prompt and the program exits.
+func askNext(offerBack, offerSwap bool, swapLang string) nextAction {
+	if !offerBack && !offerSwap {
+		return nextExit
+	}
+	fmt.Fprintln(promptOut, nextPrompt(offerBack, offerSwap, swapLang))
+	done := waitForInput()
+	input, _ := stdinReader.ReadString('\n')
+	done()
+	switch strings.TrimSpace(input) {
+	case "b":
+		if offerBack {
+			return nextBack
+		}
+	case "e":
+		if offerSwap {
+			return nextSwap
+		}
+	}
+	return nextExit
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestNextPrompt(t *testing.T) {
+	got := nextPrompt(true, true, "en")
+	if !strings.Contains(got, "'b' to go back to the results, 'e' to read the article in en") {
+		t.Errorf("Unerwartete Eingabeaufforderung: %q", got)
+	}
+	if got := nextPrompt(false, true, "en"); strings.Contains(got, "'b'") {
+		t.Errorf("Ohne Ergebnisliste sollte 'b' nicht angeboten werden: %q", got)
+	}
+}
+
+func TestSearchStateSwapsLanguage(t *testing.T) {
+	setTestHome(t)
+	stdinReader = bufio.NewReader(strings.NewReader("e\n\n"))
+	defer func() { stdinReader = bufio.NewReader(os.Stdin) }()
+
+	var fetched []string
+	fetch := func(ctx context.Context, lang, title string) (wiki.CacheEntry, bool, error) {
+		fetched = append(fetched, lang+":"+title)
+		return wiki.CacheEntry{Summary: "Go (" + lang + ")", URL: "https://" + lang + ".wikipedia.org/wiki/Go"}, false, nil
+	}
+
+	maxResults := 5
+	state := &searchState{Lang: "de", Results: []string{"Go"}, Output: &plainWriter{out: io.Discard}, SwapLang: "en"}
+	if err := state.run(context.Background(), &maxResults, fetch); err != nil {
+		t.Fatalf("run sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if strings.Join(fetched, " ") != "de:Go en:Go" {
+		t.Errorf("Erwartete Abrufe [de:Go en:Go], erhielt %v", fetched)
+	}
+}
+
+func TestSearchStateNoSwapInSameLanguage(t *testing.T) {
+	setTestHome(t)
+	// Es darf nicht gefragt werden, sonst würde "e" gelesen
+	stdinReader = bufio.NewReader(strings.NewReader("e\n"))
+	defer func() { stdinReader = bufio.NewReader(os.Stdin) }()
+
+	var fetched []string
+	fetch := func(ctx context.Context, lang, title string) (wiki.CacheEntry, bool, error) {
+		fetched = append(fetched, lang+":"+title)
+		return wiki.CacheEntry{Summary: "Go", URL: "https://en.wikipedia.org/wiki/Go"}, false, nil
+	}
+
+	maxResults := 5
+	state := &searchState{Lang: "en", Results: []string{"Go"}, Output: &plainWriter{out: io.Discard}, SwapLang: "en"}
+	if err := state.run(context.Background(), &maxResults, fetch); err != nil {
+		t.Fatalf("run sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if len(fetched) != 1 {
+		t.Errorf("Erwartete einen Abruf, erhielt %v", fetched)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// supportedLanguages maps the codes of the larger Wikipedia language
+// editions to their English names.
+var supportedLanguages = map[string]string{
+	"ar":     "Arabic",
+	"ca":     "Catalan",
+	"cs":     "Czech",
+	"da":     "Danish",
+	"de":     "German",
+	"el":     "Greek",
+	"en":     "English",
+	"eo":     "Esperanto",
+	"es":     "Spanish",
+	"fa":     "Persian",
+	"fi":     "Finnish",
+	"fr":     "French",
+	"he":     "Hebrew",
+	"hi":     "Hindi",
+	"hu":     "Hungarian",
+	"id":     "Indonesian",
+	"it":     "Italian",
+	"ja":     "Japanese",
+	"ko":     "Korean",
+	"nl":     "Dutch",
+	"no":     "Norwegian",
+	"pl":     "Polish",
+	"pt":     "Portuguese",
+	"ro":     "Romanian",
+	"ru":     "Russian",
+	"simple": "Simple English",
+	"sv":     "Swedish",
+	"th":     "Thai",
+	"tr":     "Turkish",
+	"uk":     "Ukrainian",
+	"vi":     "Vietnamese",
+	"zh":     "Chinese",
+}
+
+// languageAliases maps native and other common names of the languages to
+// their codes. The English names of supportedLanguages are accepted as
+// well.
+var languageAliases = map[string]string{
+	"deutsch":    "de",
+	"englisch":   "en",
+	"français":   "fr",
+	"francais":   "fr",
+	"español":    "es",
+	"espanol":    "es",
+	"italiano":   "it",
+	"nederlands": "nl",
+	"português":  "pt",
+	"portugues":  "pt",
+	"polski":     "pl",
+	"svenska":    "sv",
+	"dansk":      "da",
+	"norsk":      "no",
+	"suomi":      "fi",
+	"čeština":    "cs",
+	"magyar":     "hu",
+	"română":     "ro",
+	"türkçe":     "tr",
+	"русский":    "ru",
+	"українська": "uk",
+	"ελληνικά":   "el",
+	"日本語":        "ja",
+	"中文":         "zh",
+	"한국어":        "ko",
+	"العربية":    "ar",
+	"עברית":      "he",
+	"हिन्दी":     "hi",
+	"tiếng việt": "vi",
+}
+
+// resolveLanguage returns the code for a language given by code or by
+// name, so -lang german works like -lang de.
+func resolveLanguage(value string) (string, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	if _, ok := supportedLanguages[name]; ok {
+		return name, nil
+	}
+	if code, ok := languageAliases[name]; ok {
+		return code, nil
+	}
+	for code, english := range supportedLanguages {
+		if strings.ToLower(english) == name {
+			return code, nil
+		}
+	}
+	codes := make([]string, 0, len(supportedLanguages))
+	for _, language := range languageList() {
+		codes = append(codes, language.Code)
+	}
+	return "", fmt.Errorf("unknown language %q, supported languages: %s", value, strings.Join(codes, ", "))
+}
+
+type Language struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// languageList returns the supported languages sorted by code.
+func languageList() []Language {
+	languages := make([]Language, 0, len(supportedLanguages))
+	for code, name := range supportedLanguages {
+		languages = append(languages, Language{Code: code, Name: name})
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i].Code < languages[j].Code
+	})
+	return languages
+}
+
+func printLanguages(asJSON bool) error {
+	languages := languageList()
+	if asJSON {
+		data, err := marshalJSON(languages)
+		if err != nil {
+			return fmt.Errorf("error encoding languages: %v", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	for _, language := range languages {
+		fmt.Printf("%-7s %s\n", language.Code, language.Name)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLanguageListSorted(t *testing.T) {
+	languages := languageList()
+	if len(languages) != len(supportedLanguages) {
+		t.Fatalf("Erwartete %d Sprachen, erhielt %d", len(supportedLanguages), len(languages))
+	}
+
+	for i := 1; i < len(languages); i++ {
+		if languages[i-1].Code > languages[i].Code {
+			t.Errorf("Die Sprachen sollten nach Code sortiert sein: '%s' vor '%s'", languages[i-1].Code, languages[i].Code)
+		}
+	}
+}
+
+func TestLanguageListContainsDefaults(t *testing.T) {
+	found := map[string]bool{}
+	for _, language := range languageList() {
+		found[language.Code] = true
+	}
+
+	for _, code := range []string{"de", "en"} {
+		if !found[code] {
+			t.Errorf("Die Sprachliste sollte '%s' enthalten", code)
+		}
+	}
+}
+
+func TestResolveLanguage(t *testing.T) {
+	tests := map[string]string{
+		"de":             "de",
+		"EN":             "en",
+		"german":         "de",
+		"Deutsch":        "de",
+		"english":        "en",
+		"français":       "fr",
+		"Francais":       "fr",
+		"español":        "es",
+		"日本語":            "ja",
+		"simple english": "simple",
+	}
+	for value, want := range tests {
+		if got, err := resolveLanguage(value); err != nil || got != want {
+			t.Errorf("resolveLanguage(%q): erwartete %q, erhielt %q (%v)", value, want, got, err)
+		}
+	}
+
+	_, err := resolveLanguage("klingonisch")
+	if err == nil || !strings.Contains(err.Error(), "ar, ca, cs") {
+		t.Errorf("Eine unbekannte Sprache sollte die unterstützten Codes nennen: %v", err)
+	}
+}
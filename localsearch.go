@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+type localMatch struct {
+	Key   string
+	Lang  string
+	Title string
+	Entry wiki.CacheEntry
+}
+
+// label is shown in the selection menu.
+func (m localMatch) label() string {
+	return fmt.Sprintf("[%s] %s", m.Lang, m.Title)
+}
+
+// localSearch matches the query case-insensitively against the titles in
+// the cache. Matches are ranked by their edit distance to the query.
+func localSearch(cache wiki.Cache, query string) []localMatch {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var matches []localMatch
+	for key, entry := range cache {
+		if entry.NotFound || entry.RedirectTo != "" || strings.HasPrefix(key, "langlinks:") {
+			continue
+		}
+		lang, title, found := strings.Cut(key, ":")
+		if !found {
+			continue
+		}
+		title = strings.ReplaceAll(title, "_", " ")
+		if strings.Contains(strings.ToLower(title), query) {
+			matches = append(matches, localMatch{Key: key, Lang: lang, Title: title, Entry: entry})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		di := levenshtein(query, strings.ToLower(matches[i].Title))
+		dj := levenshtein(query, strings.ToLower(matches[j].Title))
+		if di != dj {
+			return di < dj
+		}
+		return matches[i].Key < matches[j].Key
+	})
+	return matches
+}
+
+// levenshtein returns the number of single-character edits needed to turn
+// a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// runLocalSearch lets the user pick a cached article matching the query
+// and shows it without any network access.
+func runLocalSearch(query string, maxResults *int, output OutputWriter) int {
+	matches := localSearch(fileCache().Load(), query)
+	if len(matches) == 0 {
+		fmt.Fprintln(os.Stderr, "No results found.")
+		return exitNoResults
+	}
+
+	match := matches[0]
+	if len(matches) > 1 {
+		labels := make([]string, len(matches))
+		for i, m := range matches {
+			labels[i] = m.label()
+		}
+		selected := chooseResult(labels, maxResults)
+		for _, m := range matches {
+			if m.label() == selected {
+				match = m
+				break
+			}
+		}
+	}
+
+	if err := output.Write(newResult(match.Lang, match.Title, match.Entry, true)); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitNoResults
+	}
+	return exitOK
+}
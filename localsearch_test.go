@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestLocalSearch(t *testing.T) {
+	cache := wiki.Cache{
+		"de:Berlin":            wiki.CacheEntry{Summary: "Berlin", Timestamp: time.Now()},
+		"de:Berlin-Mitte":      wiki.CacheEntry{Summary: "Berlin-Mitte", Timestamp: time.Now()},
+		"en:Berlin_Wall":       wiki.CacheEntry{Summary: "Berlin Wall", Timestamp: time.Now()},
+		"de:Paris":             wiki.CacheEntry{Summary: "Paris", Timestamp: time.Now()},
+		"de:Berliner_Gibtsnet": wiki.CacheEntry{NotFound: true, Timestamp: time.Now()},
+	}
+
+	matches := localSearch(cache, "BERLIN")
+	if len(matches) != 3 {
+		t.Fatalf("Erwartete 3 Treffer, erhielt %d: %+v", len(matches), matches)
+	}
+
+	// Der exakte Treffer sollte zuerst kommen
+	want := []string{"[de] Berlin", "[en] Berlin Wall", "[de] Berlin-Mitte"}
+	for i, label := range want {
+		if matches[i].label() != label {
+			t.Errorf("Treffer %d: erwartete '%s', erhielt '%s'", i, label, matches[i].label())
+		}
+	}
+
+	if matches := localSearch(cache, "london"); len(matches) != 0 {
+		t.Errorf("Für 'london' sollte es keine Treffer geben, erhielt %+v", matches)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"berlin", "berlin", 0},
+		{"berlin", "berlin wall", 5},
+		{"köln", "koln", 1},
+		{"", "paris", 5},
+	}
+
+	for _, test := range tests {
+		if got := levenshtein(test.a, test.b); got != test.want {
+			t.Errorf("levenshtein(%q, %q): erwartete %d, erhielt %d", test.a, test.b, test.want, got)
+		}
+	}
+}
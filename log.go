@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// logger writes diagnostics to stderr. Without -verbose only warnings are
+// shown, e.g. about a corrupt cache file.
+var logger = newLogger(io.Discard, slog.LevelInfo)
+
+func newLogger(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// newWarningLogger only prints warnings, without the timestamp that is
+// noise outside of -verbose.
+func newWarningLogger(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) == 0 && attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+}
+
+// verbosity is the value of -verbose and -v. Used as a boolean flag it
+// enables info messages, "-verbose=debug" also enables debug messages.
+type verbosity struct {
+	enabled bool
+	level   slog.Level
+}
+
+func (v *verbosity) String() string {
+	if v == nil || !v.enabled {
+		return "off"
+	}
+	if v.level == slog.LevelDebug {
+		return "debug"
+	}
+	return "info"
+}
+
+func (v *verbosity) Set(value string) error {
+	switch value {
+	case "true", "info":
+		v.enabled, v.level = true, slog.LevelInfo
+	case "debug":
+		v.enabled, v.level = true, slog.LevelDebug
+	case "false", "off":
+		v.enabled = false
+	default:
+		return fmt.Errorf("unknown verbosity %q, use info or debug", value)
+	}
+	return nil
+}
+
+func (v *verbosity) IsBoolFlag() bool {
+	return true
+}
+
+// configureLogger logs to stderr at the requested verbosity, or only
+// warnings when verbosity is disabled.
+func configureLogger(v verbosity) {
+	if v.enabled {
+		logger = newLogger(os.Stderr, v.level)
+	} else {
+		logger = newWarningLogger(os.Stderr)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+// setTestHome points $HOME and the XDG directories, which take precedence
+// over it, into a temporary directory and returns it, so tests never touch
+// the real config, cache or history.
+func setTestHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	return home
+}
+
+// captureOutput runs fn with stdout and stderr redirected and returns what
+// was written to them.
+func captureOutput(t *testing.T, fn func()) (string, string) {
+	t.Helper()
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderrReader, stderrWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	originalStdout, originalStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdoutWriter, stderrWriter
+	defer func() { os.Stdout, os.Stderr = originalStdout, originalStderr }()
+
+	stdout := make(chan string)
+	stderr := make(chan string)
+	go func() { data, _ := io.ReadAll(stdoutReader); stdout <- string(data) }()
+	go func() { data, _ := io.ReadAll(stderrReader); stderr <- string(data) }()
+
+	fn()
+
+	stdoutWriter.Close()
+	stderrWriter.Close()
+	return <-stdout, <-stderr
+}
+
+// exerciseDiagnostics triggers the code paths that log diagnostics.
+func exerciseDiagnostics(t *testing.T) {
+	setTestHome(t)
+	writeTestFile(t, getConfigPath(), []byte("{kaputt"))
+	writeTestFile(t, getHistoryPath(), []byte("{kaputt"))
+
+	loadConfig()
+	loadHistory()
+	fileCache().Get("de", "Berlin")
+	newClient("de")
+}
+
+func TestNoDebugOutputWhenQuiet(t *testing.T) {
+	defer configureLogger(verbosity{})
+
+	stdout, stderr := captureOutput(t, func() {
+		configureLogger(verbosity{})
+		exerciseDiagnostics(t)
+	})
+
+	if stdout != "" {
+		t.Errorf("Ohne -verbose sollte nichts auf stdout ausgegeben werden, erhielt %q", stdout)
+	}
+	if stderr != "" {
+		t.Errorf("Ohne -verbose sollte nichts auf stderr ausgegeben werden, erhielt %q", stderr)
+	}
+}
+
+func TestVerboseLogsToStderr(t *testing.T) {
+	defer configureLogger(verbosity{})
+
+	stdout, stderr := captureOutput(t, func() {
+		configureLogger(verbosity{enabled: true, level: slog.LevelDebug})
+		exerciseDiagnostics(t)
+	})
+
+	if stdout != "" {
+		t.Errorf("Diagnosen sollten nicht auf stdout landen, erhielt %q", stdout)
+	}
+	for _, want := range []string{"error decoding config", "error decoding history", "cache miss"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("stderr sollte '%s' enthalten, erhielt %q", want, stderr)
+		}
+	}
+}
+
+func TestVerbosityFlag(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{[]string{}, "off", false},
+		{[]string{"-v"}, "info", false},
+		{[]string{"-verbose"}, "info", false},
+		{[]string{"-verbose=debug"}, "debug", false},
+		{[]string{"-v=laut"}, "", true},
+	}
+
+	for _, test := range tests {
+		var v verbosity
+		flags := flag.NewFlagSet("wikr", flag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+		flags.Var(&v, "verbose", "")
+		flags.Var(&v, "v", "")
+
+		err := flags.Parse(test.args)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q sollte einen Fehler zurückgeben", test.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q sollte keinen Fehler zurückgeben: %v", test.args, err)
+		}
+		if v.String() != test.want {
+			t.Errorf("%q: erwartete '%s', erhielt '%s'", test.args, test.want, v.String())
+		}
+	}
+}
+
+func TestCorruptCacheWarnsWithoutVerbose(t *testing.T) {
+	defer configureLogger(verbosity{})
+	setTestHome(t)
+	writeTestFile(t, wiki.DefaultCachePath(), []byte("{kaputt"))
+
+	_, stderr := captureOutput(t, func() {
+		configureLogger(verbosity{})
+		fileCache().Get("de", "Berlin")
+	})
+
+	if !strings.Contains(stderr, "cache file is corrupt") || !strings.Contains(stderr, ".bak") {
+		t.Errorf("Ein kaputter Cache sollte auch ohne -verbose gemeldet werden, erhielt %q", stderr)
+	}
+	if strings.Contains(stderr, "time=") {
+		t.Errorf("Warnungen ohne -verbose sollten keinen Zeitstempel enthalten: %q", stderr)
+	}
+}
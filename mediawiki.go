@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// baseURL points the clients at another MediaWiki than Wikipedia, set by
+// -base-url. Empty uses the Wikimedia project.
+var baseURL string
+
+// authorization is the Authorization header for the wiki at baseURL, set
+// by -auth.
+var authorization string
+
+// checkBaseURL accepts http and https URLs with a host. A "%s" for the
+// language is allowed, e.g. https://%s.wiki.example.com.
+func checkBaseURL(raw string) error {
+	parsed, err := url.Parse(strings.ReplaceAll(raw, "%s", "lang"))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid -base-url %q, expected e.g. https://wiki.example.com", raw)
+	}
+	return nil
+}
+
+// authorizationHeader turns the value of -auth into an Authorization
+// header: "user:password" is sent as basic credentials, anything else as
+// a bearer token. A value that already names its scheme is used as is.
+func authorizationHeader(auth string) string {
+	if strings.HasPrefix(auth, "Bearer ") || strings.HasPrefix(auth, "Basic ") {
+		return auth
+	}
+	if strings.Contains(auth, ":") {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
+	}
+	return "Bearer " + auth
+}
+
+// baseURLProject keeps the cache entries of another wiki apart from those
+// of Wikipedia by using its URL without the scheme as project.
+func baseURLProject(raw string) string {
+	_, rest, _ := strings.Cut(raw, "://")
+	return strings.TrimSuffix(rest, "/")
+}
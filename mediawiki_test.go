@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuthorizationHeader(t *testing.T) {
+	tests := []struct {
+		auth string
+		want string
+	}{
+		{"geheim", "Bearer geheim"},
+		{"anna:passwort", "Basic YW5uYTpwYXNzd29ydA=="},
+		{"Bearer geheim", "Bearer geheim"},
+		{"Basic YW5uYTpwYXNzd29ydA==", "Basic YW5uYTpwYXNzd29ydA=="},
+	}
+	for _, test := range tests {
+		if got := authorizationHeader(test.auth); got != test.want {
+			t.Errorf("%q: erwartete %q, erhielt %q", test.auth, test.want, got)
+		}
+	}
+}
+
+func TestCheckBaseURL(t *testing.T) {
+	for _, raw := range []string{"https://wiki.example.com", "http://localhost:8080/", "https://%s.wiki.example.com"} {
+		if err := checkBaseURL(raw); err != nil {
+			t.Errorf("%q sollte gültig sein: %v", raw, err)
+		}
+	}
+	for _, raw := range []string{"wiki.example.com", "ftp://wiki.example.com", "https://"} {
+		if err := checkBaseURL(raw); err == nil {
+			t.Errorf("%q sollte ungültig sein", raw)
+		}
+	}
+}
+
+func TestRunBaseURLWithAuth(t *testing.T) {
+	setTestHome(t)
+
+	// Ein internes Wiki ohne REST-Endpunkt, das nur mit Token antwortet
+	var unauthorized int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer geheim" {
+			unauthorized++
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Query().Get("list") == "search":
+			fmt.Fprint(w, `{"query": {"search": [{"title": "Onboarding"}]}}`)
+		case r.URL.Query().Get("prop") == "extracts":
+			fmt.Fprint(w, `{"query": {"pages": [{"title": "Onboarding", "extract": "Willkommen im Team."}]}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	var code int
+	stdout, _ := captureOutput(t, func() {
+		code = run([]string{"-base-url", server.URL, "-auth", "geheim", "Onboarding"})
+	})
+	if code != exitOK {
+		t.Fatalf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+	if !strings.Contains(stdout, "Willkommen im Team.") || !strings.Contains(stdout, server.URL+"/wiki/Onboarding") {
+		t.Errorf("Die Zusammenfassung des internen Wikis sollte erscheinen: %q", stdout)
+	}
+	if unauthorized > 0 {
+		t.Errorf("Alle Anfragen sollten den Token senden, %d ohne", unauthorized)
+	}
+	if baseURL != "" || authorization != "" {
+		t.Error("-base-url und -auth sollten nach run zurückgesetzt sein")
+	}
+
+	// Der Cache des internen Wikis ist von Wikipedia getrennt
+	if _, found := fileCache().Get("de", "Onboarding"); found {
+		t.Error("Der Eintrag sollte nicht unter dem Wikipedia-Schlüssel liegen")
+	}
+}
+
+func TestRunAuthNeedsBaseURL(t *testing.T) {
+	setTestHome(t)
+
+	var code int
+	_, stderr := captureOutput(t, func() {
+		code = run([]string{"-auth", "geheim", "Berlin"})
+	})
+	if code != exitUsage || !strings.Contains(stderr, "-auth needs -base-url") {
+		t.Errorf("Ohne -base-url sollte -auth ein Bedienfehler sein: %d, %q", code, stderr)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// classicMenu forces the numeric prompt, set by -classic-menu.
+var classicMenu = false
+
+// promptOut receives the result menu and the prompts. -url-only moves them
+// to stderr so stdout only carries the URL.
+var promptOut = os.Stdout
+
+type menuKey int
+
+const (
+	keyNone menuKey = iota
+	keyUp
+	keyDown
+	keyEnter
+	keyCancel
+)
+
+// useArrowMenu decides whether chooseResult shows the arrow-key menu. It
+// needs a terminal on both ends, otherwise raw mode and redrawing do not
+// work and the numeric prompt is used.
+func useArrowMenu(classic, stdinTerminal, stdoutTerminal bool) bool {
+	return !classic && stdinTerminal && stdoutTerminal
+}
+
+func arrowMenuAvailable() bool {
+	return useArrowMenu(classicMenu, term.IsTerminal(int(os.Stdin.Fd())), term.IsTerminal(int(promptOut.Fd())))
+}
+
+// parseKey maps the bytes of one key press to a menu key. Arrow keys
+// arrive as escape sequences, a lone escape cancels.
+func parseKey(input []byte) menuKey {
+	switch {
+	case len(input) == 0:
+		return keyNone
+	case string(input) == "\x1b[A" || string(input) == "\x1bOA" || string(input) == "k":
+		return keyUp
+	case string(input) == "\x1b[B" || string(input) == "\x1bOB" || string(input) == "j":
+		return keyDown
+	case input[0] == '\r' || input[0] == '\n':
+		return keyEnter
+	case string(input) == "\x1b" || input[0] == 'q' || input[0] == 3: // 3 is Ctrl-C in raw mode
+		return keyCancel
+	}
+	return keyNone
+}
+
+// moveCursor returns the new cursor position after the key, wrapping around
+// at both ends.
+func moveCursor(cursor, count int, key menuKey) int {
+	switch key {
+	case keyUp:
+		return (cursor - 1 + count) % count
+	case keyDown:
+		return (cursor + 1) % count
+	}
+	return cursor
+}
+
+// selectWithArrows lets the user pick a result with the arrow keys and
+// Enter. The boolean is false when the user cancelled.
+func selectWithArrows(results []string) (string, bool, error) {
+	fd := int(os.Stdin.Fd())
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", false, err
+	}
+	defer term.Restore(fd, oldState)
+	setTerminalRestore(func() { term.Restore(fd, oldState) })
+	defer setTerminalRestore(nil)
+
+	fmt.Fprint(promptOut, "\r\nMultiple results found. Use ↑/↓ and Enter to choose, Esc to quit:\r\n")
+	cursor := 0
+	drawMenu(results, cursor, false)
+
+	buf := make([]byte, 8)
+	for {
+		done := waitForInput()
+		n, err := stdinReader.Read(buf)
+		done()
+		if err != nil {
+			return "", false, err
+		}
+		switch key := parseKey(buf[:n]); key {
+		case keyEnter:
+			fmt.Fprint(promptOut, "\r\n")
+			return results[cursor], true, nil
+		case keyCancel:
+			fmt.Fprint(promptOut, "\r\n")
+			return "", false, nil
+		case keyUp, keyDown:
+			cursor = moveCursor(cursor, len(results), key)
+			drawMenu(results, cursor, true)
+		}
+	}
+}
+
+// drawMenu prints the results with the selected one highlighted. With
+// redraw the previously drawn menu is overwritten.
+func drawMenu(results []string, cursor int, redraw bool) {
+	var b strings.Builder
+	if redraw {
+		fmt.Fprintf(&b, "\x1b[%dA", len(results))
+	}
+	for i, result := range results {
+		b.WriteString("\r\x1b[2K")
+		if i == cursor {
+			b.WriteString(activeTheme.Title.Sprint("> " + result))
+		} else {
+			b.WriteString("  " + result)
+		}
+		b.WriteString("\r\n")
+	}
+	fmt.Fprint(promptOut, b.String())
+}
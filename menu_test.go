@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestUseArrowMenu(t *testing.T) {
+	tests := []struct {
+		name                           string
+		classic, stdinTerm, stdoutTerm bool
+		want                           bool
+	}{
+		{"Terminal", false, true, true, true},
+		{"Klassisches Menü", true, true, true, false},
+		{"Eingabe umgeleitet", false, false, true, false},
+		{"Ausgabe umgeleitet", false, true, false, false},
+	}
+
+	for _, test := range tests {
+		if got := useArrowMenu(test.classic, test.stdinTerm, test.stdoutTerm); got != test.want {
+			t.Errorf("%s: erwartete %v, erhielt %v", test.name, test.want, got)
+		}
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := map[string]menuKey{
+		"\x1b[A": keyUp,
+		"\x1bOB": keyDown,
+		"\r":     keyEnter,
+		"\x1b":   keyCancel,
+		"q":      keyCancel,
+		"\x03":   keyCancel,
+		"x":      keyNone,
+		"\x1b[C": keyNone,
+	}
+
+	for input, want := range tests {
+		if got := parseKey([]byte(input)); got != want {
+			t.Errorf("parseKey(%q): erwartete %d, erhielt %d", input, want, got)
+		}
+	}
+}
+
+func TestMoveCursorWraps(t *testing.T) {
+	if got := moveCursor(0, 3, keyUp); got != 2 {
+		t.Errorf("Nach oben vom ersten Eintrag sollte zum letzten springen, erhielt %d", got)
+	}
+	if got := moveCursor(2, 3, keyDown); got != 0 {
+		t.Errorf("Nach unten vom letzten Eintrag sollte zum ersten springen, erhielt %d", got)
+	}
+	if got := moveCursor(1, 3, keyEnter); got != 1 {
+		t.Errorf("Enter sollte den Cursor nicht bewegen, erhielt %d", got)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the upper bounds in seconds of the request duration
+// histogram, from cache hits to slow upstream requests.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// requestKey labels the request counter.
+type requestKey struct {
+	endpoint string
+	code     int
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// serverMetrics counts what the HTTP API of -serve does and writes it in
+// the Prometheus text format. It is safe for concurrent use, and a nil
+// *serverMetrics counts nothing.
+type serverMetrics struct {
+	mu             sync.Mutex
+	requests       map[requestKey]uint64
+	latency        map[string]*histogram
+	cacheHits      uint64
+	cacheMisses    uint64
+	upstreamErrors uint64
+}
+
+func newServerMetrics() *serverMetrics {
+	return &serverMetrics{
+		requests: make(map[requestKey]uint64),
+		latency:  make(map[string]*histogram),
+	}
+}
+
+func (m *serverMetrics) observeRequest(endpoint string, code int, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[requestKey{endpoint, code}]++
+	h := m.latency[endpoint]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(latencyBuckets))}
+		m.latency[endpoint] = h
+	}
+	seconds := elapsed.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+func (m *serverMetrics) observeCache(hit bool) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if hit {
+		m.cacheHits++
+	} else {
+		m.cacheMisses++
+	}
+}
+
+func (m *serverMetrics) observeUpstreamError() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.upstreamErrors++
+}
+
+// statusRecorder remembers the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.code = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// instrument counts the requests of the handler and their duration.
+func (m *serverMetrics) instrument(endpoint string, handler http.HandlerFunc) http.HandlerFunc {
+	if m == nil {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+		handler(recorder, r)
+		m.observeRequest(endpoint, recorder.code, time.Since(start))
+	}
+}
+
+// write prints the metrics in the Prometheus text format, sorted so the
+// output is stable.
+func (m *serverMetrics) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP wikr_requests_total HTTP requests served, by endpoint and status code.")
+	fmt.Fprintln(w, "# TYPE wikr_requests_total counter")
+	keys := make([]requestKey, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].endpoint != keys[j].endpoint {
+			return keys[i].endpoint < keys[j].endpoint
+		}
+		return keys[i].code < keys[j].code
+	})
+	for _, key := range keys {
+		fmt.Fprintf(w, "wikr_requests_total{endpoint=%q,code=\"%d\"} %d\n", key.endpoint, key.code, m.requests[key])
+	}
+
+	fmt.Fprintln(w, "# HELP wikr_request_duration_seconds Time to answer HTTP requests, by endpoint.")
+	fmt.Fprintln(w, "# TYPE wikr_request_duration_seconds histogram")
+	endpoints := make([]string, 0, len(m.latency))
+	for endpoint := range m.latency {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	for _, endpoint := range endpoints {
+		h := m.latency[endpoint]
+		var cumulative uint64
+		for i, bound := range latencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "wikr_request_duration_seconds_bucket{endpoint=%q,le=%q} %d\n", endpoint, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(w, "wikr_request_duration_seconds_bucket{endpoint=%q,le=\"+Inf\"} %d\n", endpoint, h.count)
+		fmt.Fprintf(w, "wikr_request_duration_seconds_sum{endpoint=%q} %g\n", endpoint, h.sum)
+		fmt.Fprintf(w, "wikr_request_duration_seconds_count{endpoint=%q} %d\n", endpoint, h.count)
+	}
+
+	fmt.Fprintln(w, "# HELP wikr_cache_hits_total Summaries served from the cache.")
+	fmt.Fprintln(w, "# TYPE wikr_cache_hits_total counter")
+	fmt.Fprintf(w, "wikr_cache_hits_total %d\n", m.cacheHits)
+	fmt.Fprintln(w, "# HELP wikr_cache_misses_total Summaries that had to be requested from Wikipedia.")
+	fmt.Fprintln(w, "# TYPE wikr_cache_misses_total counter")
+	fmt.Fprintf(w, "wikr_cache_misses_total %d\n", m.cacheMisses)
+	fmt.Fprintln(w, "# HELP wikr_upstream_errors_total Requests to Wikipedia that failed.")
+	fmt.Fprintln(w, "# TYPE wikr_upstream_errors_total counter")
+	fmt.Fprintf(w, "wikr_upstream_errors_total %d\n", m.upstreamErrors)
+}
+
+func (m *serverMetrics) handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.write(w)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func scrapeMetrics(t *testing.T, url string) string {
+	t.Helper()
+	response, err := http.Get(url + "/metrics")
+	if err != nil {
+		t.Fatalf("Die Anfrage sollte erfolgreich sein: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("Erwartete Status 200, erhielt %d", response.StatusCode)
+	}
+	body, _ := io.ReadAll(response.Body)
+	return string(body)
+}
+
+func TestServeMetrics(t *testing.T) {
+	api := newTestAPIWithMetrics(t, newServerMetrics())
+
+	before := scrapeMetrics(t, api.URL)
+	if !strings.Contains(before, "wikr_cache_hits_total 0\n") || strings.Contains(before, "wikr_requests_total{") {
+		t.Errorf("Vor den Anfragen sollten alle Zähler leer sein:\n%s", before)
+	}
+
+	// Erst aus dem Netz, dann aus dem Cache, dann ein fehlender Artikel
+	getJSON(t, api.URL+"/summary?title=Berlin", nil)
+	getJSON(t, api.URL+"/summary?title=Berlin", nil)
+	getJSON(t, api.URL+"/summary?title=Atlantis", nil)
+	getJSON(t, api.URL+"/search?q=Berlin", nil)
+
+	after := scrapeMetrics(t, api.URL)
+	for _, want := range []string{
+		`wikr_requests_total{endpoint="/summary",code="200"} 2`,
+		`wikr_requests_total{endpoint="/summary",code="404"} 1`,
+		`wikr_requests_total{endpoint="/search",code="200"} 1`,
+		`wikr_request_duration_seconds_bucket{endpoint="/summary",le="+Inf"} 3`,
+		`wikr_request_duration_seconds_count{endpoint="/search"} 1`,
+		"wikr_cache_hits_total 1\n",
+		"wikr_cache_misses_total 2\n",
+		"wikr_upstream_errors_total 0\n",
+	} {
+		if !strings.Contains(after, want) {
+			t.Errorf("Metriken enthalten %q nicht:\n%s", want, after)
+		}
+	}
+}
+
+func TestServeWithoutMetrics(t *testing.T) {
+	api := newTestAPI(t)
+	response, err := http.Get(api.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusNotFound {
+		t.Errorf("Ohne -metrics sollte /metrics fehlen, Status %d", response.StatusCode)
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	metrics := newServerMetrics()
+	metrics.observeRequest("/summary", http.StatusOK, 3*time.Millisecond)
+	metrics.observeRequest("/summary", http.StatusOK, 200*time.Millisecond)
+	metrics.observeRequest("/summary", http.StatusOK, time.Minute)
+
+	var output strings.Builder
+	metrics.write(&output)
+	for _, want := range []string{
+		`wikr_request_duration_seconds_bucket{endpoint="/summary",le="0.005"} 1`,
+		`wikr_request_duration_seconds_bucket{endpoint="/summary",le="0.25"} 2`,
+		`wikr_request_duration_seconds_bucket{endpoint="/summary",le="10"} 2`,
+		`wikr_request_duration_seconds_bucket{endpoint="/summary",le="+Inf"} 3`,
+	} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("Histogramm enthält %q nicht:\n%s", want, output.String())
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// modifiedSince is the window set by -modified-since. Articles last edited
+// before it are flagged, 0 disables the check.
+var modifiedSince time.Duration
+
+// editedWithin reports whether the article was edited within window
+// before now. An unknown edit time is never within the window.
+func editedWithin(result Result, window time.Duration, now time.Time) bool {
+	return result.Modified != nil && now.Sub(*result.Modified) <= window
+}
+
+// modifiedLabel formats the last edit in local time, e.g.
+// "2024-05-01 14:34 (5d ago)".
+func modifiedLabel(result Result, now time.Time) string {
+	if result.Modified == nil {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s (%s)", result.Modified.Local().Format("2006-01-02 15:04"), formatAge(now.Sub(*result.Modified)))
+}
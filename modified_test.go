@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEditedWithin(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	modified := now.Add(-48 * time.Hour)
+	result := Result{Modified: &modified}
+
+	if !editedWithin(result, 72*time.Hour, now) {
+		t.Error("Vor 48h bearbeitet sollte innerhalb von 72h liegen")
+	}
+	if editedWithin(result, 24*time.Hour, now) {
+		t.Error("Vor 48h bearbeitet sollte nicht innerhalb von 24h liegen")
+	}
+	if editedWithin(Result{}, 24*time.Hour, now) {
+		t.Error("Ohne Zeitstempel sollte der Artikel nicht als aktuell gelten")
+	}
+}
+
+func TestPlainWriterFlagsOldArticle(t *testing.T) {
+	defer func() { modifiedSince = 0 }()
+	modified := time.Now().Add(-60 * 24 * time.Hour)
+	result := Result{Title: "Berlin", Summary: "Berlin ist die Hauptstadt.", URL: "https://de.wikipedia.org/wiki/Berlin", Modified: &modified}
+
+	var out bytes.Buffer
+	if err := (&plainWriter{out: &out}).Write(result); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Last edited:") || !strings.Contains(out.String(), "60d ago") {
+		t.Errorf("Bearbeitungszeit fehlt in der Ausgabe:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "Not edited within") {
+		t.Errorf("Ohne -modified-since sollte nichts markiert werden:\n%s", out.String())
+	}
+
+	modifiedSince = 720 * time.Hour
+	out.Reset()
+	if err := (&plainWriter{out: &out}).Write(result); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Not edited within the last 720h0m0s") {
+		t.Errorf("Alter Artikel sollte markiert werden:\n%s", out.String())
+	}
+}
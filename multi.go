@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// parseMultiArgs returns the language shortcut and one term per argument
+// for -multi. Blank arguments are skipped.
+func parseMultiArgs(args []string, lang string) (string, []string) {
+	lang, args = splitLangShortcut(args, lang)
+	var terms []string
+	for _, arg := range args {
+		if term := strings.TrimSpace(arg); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return lang, terms
+}
+
+// multiTerm is a -multi term with the language to look it up in.
+type multiTerm struct {
+	// Label is the term as given, with its language prefix if it has one
+	Label string
+	Lang  string
+	Term  string
+}
+
+// parseMultiTerms splits the optional language prefix off every term, so
+// "en:Berlin de:München" looks up Berlin in the English and München in the
+// German Wikipedia. Terms without a prefix use lang. The prefix may also
+// be a language name like for -lang, as in "german:Berlin". A colon behind
+// anything that is neither a language nor shaped like a language code is
+// part of the term, as in "Star Trek: Voyager".
+func parseMultiTerms(terms []string, lang string) ([]multiTerm, error) {
+	parsed := make([]multiTerm, 0, len(terms))
+	for _, term := range terms {
+		entry := multiTerm{Label: term, Lang: lang, Term: term}
+		if prefix, rest, found := strings.Cut(term, ":"); found {
+			code, err := resolveLanguage(prefix)
+			switch {
+			case err == nil:
+				entry.Lang, entry.Term = code, strings.TrimSpace(rest)
+				if entry.Term == "" {
+					return nil, fmt.Errorf("missing search term after %q", term)
+				}
+			case looksLikeLangCode(prefix):
+				return nil, fmt.Errorf("unknown language %q in %q, see -lang-list", prefix, term)
+			}
+		}
+		parsed = append(parsed, entry)
+	}
+	return parsed, nil
+}
+
+// looksLikeLangCode reports whether s has the shape of a Wikipedia
+// language code, two or three lowercase letters or "simple".
+func looksLikeLangCode(s string) bool {
+	if s == "simple" {
+		return true
+	}
+	if len(s) < 2 || len(s) > 3 {
+		return false
+	}
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// runMulti looks up the terms one after another, each under its own
+// header and in the Wikipedia of its language. With -first the most
+// relevant result is used, otherwise the user picks one per term. The
+// exit code of the first failure is returned.
+func runMulti(ctx context.Context, terms []multiTerm, maxResults *int, first bool, output OutputWriter, out io.Writer, headers bool) int {
+	code := exitOK
+	fail := func(failure int) {
+		if code == exitOK {
+			code = failure
+		}
+	}
+	for _, entry := range terms {
+		lang, term := entry.Lang, entry.Term
+		if headers {
+			activeTheme.Title.Fprintf(out, "\n== %s ==\n", entry.Label)
+		}
+		results, err := searchWikipedia(ctx, lang, term)
+		if err != nil {
+			activeTheme.Error.Fprintf(os.Stderr, "Error searching %q: %v\n", term, err)
+			fail(exitNetwork)
+			continue
+		}
+		if len(results) == 0 {
+			fmt.Fprintf(os.Stderr, "No results found for %q.\n", term)
+			fail(exitNoResults)
+			continue
+		}
+		if first {
+			results = results[:1]
+		}
+
+		state := &searchState{Lang: lang, Results: results, Output: output, Once: true}
+		if err := state.run(ctx, maxResults, getWikipediaSummary); err != nil {
+			activeTheme.Error.Fprintf(os.Stderr, "Error fetching summary for %q: %v\n", term, err)
+			fail(exitCodeFor(err))
+		}
+		if ctx.Err() != nil {
+			return exitCodeFor(ctx.Err())
+		}
+	}
+	return code
+}
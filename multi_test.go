@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestParseMultiArgs(t *testing.T) {
+	lang, terms := parseMultiArgs([]string{"en", "Berlin", " ", "New York "}, "de")
+	if lang != "en" {
+		t.Errorf("Erwartete Sprache 'en', erhielt '%s'", lang)
+	}
+	if strings.Join(terms, "|") != "Berlin|New York" {
+		t.Errorf("Erwartete [Berlin New York], erhielt %q", terms)
+	}
+}
+
+func TestRunMultiThreeTerms(t *testing.T) {
+	setTestHome(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if term := r.URL.Query().Get("srsearch"); term != "" {
+			// Mehrere Treffer, damit -first die Auswahl überspringen muss
+			fmt.Fprintf(w, `{"query": {"search": [{"title": %q}, {"title": "%s (Begriffsklärung)"}]}}`, term, term)
+			return
+		}
+		title := strings.TrimPrefix(r.URL.Path, "/de/api/rest_v1/page/summary/")
+		fmt.Fprintf(w, `{"title": %q, "titles": {"canonical": %q}, "extract": "Über %s.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/%s"}}}`, title, title, title, title)
+	}))
+	defer server.Close()
+
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	var code int
+	stdout, _ := captureOutput(t, func() {
+		code = run([]string{"-multi", "-first", "Berlin", "Paris", "Tokyo"})
+	})
+	if code != exitOK {
+		t.Fatalf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+
+	last := -1
+	for _, term := range []string{"Berlin", "Paris", "Tokyo"} {
+		header := strings.Index(stdout, "== "+term+" ==")
+		summary := strings.Index(stdout, "Über "+term+".")
+		if header < 0 || summary < header {
+			t.Errorf("Für %s sollten Überschrift und Zusammenfassung erscheinen: %q", term, stdout)
+		}
+		if header < last {
+			t.Errorf("%s sollte nach dem vorherigen Begriff erscheinen", term)
+		}
+		last = header
+	}
+	if strings.Contains(stdout, "Multiple results found") {
+		t.Errorf("Mit -first sollte nicht nachgefragt werden: %q", stdout)
+	}
+}
+
+func TestParseMultiTerms(t *testing.T) {
+	terms, err := parseMultiTerms([]string{"en:Berlin", "de: München", "Paris", "Star Trek: Voyager"}, "fr")
+	if err != nil {
+		t.Fatalf("parseMultiTerms sollte keinen Fehler zurückgeben: %v", err)
+	}
+	want := []multiTerm{
+		{Label: "en:Berlin", Lang: "en", Term: "Berlin"},
+		{Label: "de: München", Lang: "de", Term: "München"},
+		{Label: "Paris", Lang: "fr", Term: "Paris"},
+		{Label: "Star Trek: Voyager", Lang: "fr", Term: "Star Trek: Voyager"},
+	}
+	if fmt.Sprint(terms) != fmt.Sprint(want) {
+		t.Errorf("Erwartete %v, erhielt %v", want, terms)
+	}
+
+	// Sprachnamen gelten wie bei -lang
+	terms, err = parseMultiTerms([]string{"german:Berlin", "Français: Paris"}, "en")
+	if err != nil || fmt.Sprint(terms) != fmt.Sprint([]multiTerm{
+		{Label: "german:Berlin", Lang: "de", Term: "Berlin"},
+		{Label: "Français: Paris", Lang: "fr", Term: "Paris"},
+	}) {
+		t.Errorf("Sprachnamen sollten aufgelöst werden: %v, %v", terms, err)
+	}
+
+	for _, term := range []string{"xx:Berlin", "en:", "german:"} {
+		if _, err := parseMultiTerms([]string{term}, "de"); err == nil {
+			t.Errorf("%q sollte einen Fehler liefern", term)
+		}
+	}
+}
+
+func TestRunMultiLangPrefix(t *testing.T) {
+	setTestHome(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")[0]
+		if term := r.URL.Query().Get("srsearch"); term != "" {
+			fmt.Fprintf(w, `{"query": {"search": [{"title": %q}]}}`, term)
+			return
+		}
+		title := strings.TrimPrefix(r.URL.Path, "/"+lang+"/api/rest_v1/page/summary/")
+		fmt.Fprintf(w, `{"title": %q, "titles": {"canonical": %q}, "extract": "%s aus %s.", "content_urls": {"desktop": {"page": "https://%s.wikipedia.org/wiki/%s"}}}`, title, title, title, lang, lang, title)
+	}))
+	defer server.Close()
+
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	var code int
+	stdout, _ := captureOutput(t, func() {
+		code = run([]string{"-multi", "-first", "en:Berlin", "Paris", "fr:Lyon"})
+	})
+	if code != exitOK {
+		t.Fatalf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+	for _, want := range []string{"== en:Berlin ==", "Berlin aus en.", "Paris aus de.", "Lyon aus fr."} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Die Ausgabe sollte %q enthalten: %q", want, stdout)
+		}
+	}
+
+	_, stderr := captureOutput(t, func() {
+		code = run([]string{"-multi", "xx:Berlin", "Paris"})
+	})
+	if code != exitUsage || !strings.Contains(stderr, "unknown language") {
+		t.Errorf("Eine unbekannte Sprache sollte ein Bedienfehler sein: %d, %q", code, stderr)
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+// netrcFileName holds credentials for -base-url and the proxy, so they do
+// not have to be given on the command line.
+const netrcFileName = ".wikr_netrc"
+
+// netrcEntry is a machine of the netrc file. The default entry has an
+// empty Machine.
+type netrcEntry struct {
+	Machine  string
+	Login    string
+	Password string
+}
+
+// netrcTransport replaces the shared transport when the proxy needs
+// credentials from the netrc file, nil otherwise.
+var netrcTransport http.RoundTripper
+
+func netrcPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, netrcFileName)
+}
+
+// parseNetrc reads the machine, default, login and password tokens of a
+// netrc file. Other tokens like account are skipped with their value,
+// macro definitions up to the next blank line.
+func parseNetrc(data string) []netrcEntry {
+	var entries []netrcEntry
+	var current *netrcEntry
+	lines := strings.Split(data, "\n")
+	for i := 0; i < len(lines); i++ {
+		fields := strings.Fields(lines[i])
+		if len(fields) > 0 && strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for j := 0; j < len(fields); j++ {
+			value := func() string {
+				if j+1 < len(fields) {
+					j++
+					return fields[j]
+				}
+				return ""
+			}
+			switch fields[j] {
+			case "machine":
+				entries = append(entries, netrcEntry{Machine: value()})
+				current = &entries[len(entries)-1]
+			case "default":
+				entries = append(entries, netrcEntry{})
+				current = &entries[len(entries)-1]
+			case "login":
+				if login := value(); current != nil {
+					current.Login = login
+				}
+			case "password":
+				if password := value(); current != nil {
+					current.Password = password
+				}
+			case "account":
+				value()
+			case "macdef":
+				for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+					i++
+				}
+				j = len(fields)
+			}
+		}
+	}
+	return entries
+}
+
+// lookupNetrc returns the entry for the host, which may carry a port. An
+// entry with the port wins over one for the host name alone, the default
+// entry is the last resort.
+func lookupNetrc(entries []netrcEntry, host string) (netrcEntry, bool) {
+	if entry, ok := lookupNetrcMachine(entries, host); ok {
+		return entry, true
+	}
+	return findNetrcMachine(entries, "")
+}
+
+// lookupNetrcMachine is lookupNetrc without the default entry, for
+// credentials that must only go to a host they were written down for.
+func lookupNetrcMachine(entries []netrcEntry, host string) (netrcEntry, bool) {
+	hostname := host
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		hostname = name
+	}
+	if hostname == "" {
+		return netrcEntry{}, false
+	}
+	for _, candidate := range []string{host, hostname} {
+		if entry, ok := findNetrcMachine(entries, candidate); ok {
+			return entry, true
+		}
+	}
+	return netrcEntry{}, false
+}
+
+// findNetrcMachine returns the first entry for the machine, the default
+// entry for an empty machine.
+func findNetrcMachine(entries []netrcEntry, machine string) (netrcEntry, bool) {
+	for _, entry := range entries {
+		if entry.Machine == machine {
+			return entry, true
+		}
+	}
+	return netrcEntry{}, false
+}
+
+// loadNetrc reads the netrc file, a missing file has no entries. A file
+// that others can read is used, but with a warning.
+func loadNetrc(path string) ([]netrcEntry, error) {
+	if path == "" {
+		return nil, nil
+	}
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o004 != 0 {
+		logger.Warn("the netrc file is readable by everyone, restrict it with chmod 600", "path", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseNetrc(string(data)), nil
+}
+
+// netrcAuthorization returns the Authorization header for the host: basic
+// credentials for a login with password, a bearer token for a password
+// alone.
+func netrcAuthorization(entries []netrcEntry, host string) string {
+	entry, ok := lookupNetrc(entries, host)
+	switch {
+	case !ok || entry.Password == "":
+		return ""
+	case entry.Login == "":
+		return "Bearer " + entry.Password
+	}
+	return authorizationHeader(entry.Login + ":" + entry.Password)
+}
+
+// proxyWithNetrc adds the credentials of the netrc file to the proxy URL
+// that proxy selects, unless the URL already has some. Only a machine
+// entry for the proxy counts, the default entry is meant for the wiki and
+// must not be sent to whatever proxy the environment names.
+func proxyWithNetrc(entries []netrcEntry, proxy func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(request *http.Request) (*url.URL, error) {
+		proxyURL, err := proxy(request)
+		if err != nil || proxyURL == nil || proxyURL.User != nil {
+			return proxyURL, err
+		}
+		entry, ok := lookupNetrcMachine(entries, proxyURL.Host)
+		if !ok || entry.Login == "" {
+			return proxyURL, nil
+		}
+		withUser := *proxyURL
+		withUser.User = url.UserPassword(entry.Login, entry.Password)
+		return &withUser, nil
+	}
+}
+
+// newNetrcTransport returns the shared transport with a proxy that takes
+// its credentials from the netrc entries.
+func newNetrcTransport(entries []netrcEntry) (http.RoundTripper, error) {
+	base, ok := wiki.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("cannot add proxy credentials to %T", wiki.DefaultTransport)
+	}
+	transport := base.Clone()
+	transport.Proxy = proxyWithNetrc(entries, http.ProxyFromEnvironment)
+	return transport, nil
+}
+
+// baseURLHost returns the host of -base-url for the language, the machine
+// its credentials are stored under.
+func baseURLHost(raw, lang string) string {
+	parsed, err := url.Parse(strings.ReplaceAll(raw, "%s", lang))
+	if err != nil {
+		return ""
+	}
+	return parsed.Host
+}
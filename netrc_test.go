@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleNetrc = `# Zugangsdaten für wikr
+machine wiki.example.com login anna password geheim
+machine wiki.example.com:8443
+	login bob
+	password port-geheim
+	account ignoriert
+machine token.example.com password nur-token
+
+macdef init
+machine falsch.example.com login mallory password böse
+
+default login gast password gast-geheim
+`
+
+func TestParseNetrc(t *testing.T) {
+	entries := parseNetrc(sampleNetrc)
+	want := []netrcEntry{
+		{"wiki.example.com", "anna", "geheim"},
+		{"wiki.example.com:8443", "bob", "port-geheim"},
+		{"token.example.com", "", "nur-token"},
+		{"", "gast", "gast-geheim"},
+	}
+	if fmt.Sprint(entries) != fmt.Sprint(want) {
+		t.Errorf("Erwartete %v, erhielt %v", want, entries)
+	}
+
+	tests := []struct {
+		host  string
+		login string
+	}{
+		{"wiki.example.com", "anna"},
+		{"wiki.example.com:8443", "bob"},
+		{"wiki.example.com:9000", "anna"},
+		{"andere.example.com", "gast"},
+	}
+	for _, test := range tests {
+		if entry, ok := lookupNetrc(entries, test.host); !ok || entry.Login != test.login {
+			t.Errorf("%s: erwartete Login %q, erhielt %+v", test.host, test.login, entry)
+		}
+	}
+}
+
+func TestNetrcAuthorization(t *testing.T) {
+	entries := parseNetrc(sampleNetrc)
+	if got := netrcAuthorization(entries, "wiki.example.com"); got != authorizationHeader("anna:geheim") {
+		t.Errorf("Erwartete Basic-Zugangsdaten, erhielt %q", got)
+	}
+	if got := netrcAuthorization(entries, "token.example.com"); got != "Bearer nur-token" {
+		t.Errorf("Erwartete einen Bearer-Token, erhielt %q", got)
+	}
+	if got := netrcAuthorization(nil, "wiki.example.com"); got != "" {
+		t.Errorf("Ohne Eintrag sollte nichts gesendet werden, erhielt %q", got)
+	}
+}
+
+func TestProxyWithNetrc(t *testing.T) {
+	entries := parseNetrc("machine proxy.example.com:3128 login proxyuser password proxypass\n")
+	for _, raw := range []string{"http://proxy.example.com:3128", "http://andere:pw@proxy.example.com:3128"} {
+		proxyURL, _ := url.Parse(raw)
+		withNetrc := proxyWithNetrc(entries, func(*http.Request) (*url.URL, error) { return proxyURL, nil })
+		got, err := withNetrc(httptest.NewRequest(http.MethodGet, "https://de.wikipedia.org/", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantUser := proxyURL.User.String()
+		if wantUser == "" {
+			wantUser = "proxyuser:proxypass"
+		}
+		if got.User.String() != wantUser {
+			t.Errorf("%s: erwartete %q, erhielt %q", raw, wantUser, got.User.String())
+		}
+	}
+}
+
+func TestProxyWithNetrcIgnoresDefault(t *testing.T) {
+	entries := parseNetrc("machine wiki.example.com login anna password geheim\ndefault login gast password gast-geheim\n")
+	proxyURL, _ := url.Parse("http://proxy.example.com:3128")
+	withNetrc := proxyWithNetrc(entries, func(*http.Request) (*url.URL, error) { return proxyURL, nil })
+	got, err := withNetrc(httptest.NewRequest(http.MethodGet, "https://de.wikipedia.org/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.User != nil {
+		t.Errorf("Die Zugangsdaten von default dürfen nicht an den Proxy gehen, erhielt %q", got.User.String())
+	}
+}
+
+func TestLoadNetrcWarnsWorldReadable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), netrcFileName)
+	if err := os.WriteFile(path, []byte(sampleNetrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf strings.Builder
+	previous := logger
+	logger = newWarningLogger(&buf)
+	defer func() { logger = previous }()
+
+	entries, err := loadNetrc(path)
+	if err != nil || len(entries) != 4 {
+		t.Fatalf("loadNetrc sollte die Einträge liefern: %v, %v", entries, err)
+	}
+	if !strings.Contains(buf.String(), "readable by everyone") {
+		t.Errorf("Es sollte eine Warnung erscheinen: %q", buf.String())
+	}
+
+	buf.Reset()
+	os.Chmod(path, 0600)
+	loadNetrc(path)
+	if buf.Len() != 0 {
+		t.Errorf("Mit 0600 sollte keine Warnung erscheinen: %q", buf.String())
+	}
+	if entries, err := loadNetrc(filepath.Join(t.TempDir(), "fehlt")); entries != nil || err != nil {
+		t.Errorf("Eine fehlende Datei sollte keine Einträge liefern: %v, %v", entries, err)
+	}
+}
+
+func TestRunBaseURLWithNetrc(t *testing.T) {
+	home := setTestHome(t)
+	t.Setenv("WIKR_AUTH", "")
+
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		switch {
+		case r.URL.Query().Get("list") == "search":
+			fmt.Fprint(w, `{"query": {"search": [{"title": "Onboarding"}]}}`)
+		default:
+			fmt.Fprint(w, `{"title": "Onboarding", "extract": "Willkommen im Team.", "content_urls": {"desktop": {"page": "https://wiki.example.com/wiki/Onboarding"}}}`)
+		}
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	netrc := fmt.Sprintf("machine %s login anna password geheim\n", host)
+	if err := os.WriteFile(filepath.Join(home, netrcFileName), []byte(netrc), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var code int
+	captureOutput(t, func() {
+		code = run([]string{"-base-url", server.URL, "Onboarding"})
+	})
+	if code != exitOK {
+		t.Fatalf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+	want := authorizationHeader("anna:geheim")
+	for _, got := range authorizations {
+		if got != want {
+			t.Errorf("Erwartete %q aus der netrc-Datei, erhielt %q", want, got)
+		}
+	}
+	if len(authorizations) == 0 || netrcTransport != nil {
+		t.Errorf("Unerwarteter Zustand: %d Anfragen, Transport %v", len(authorizations), netrcTransport)
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+var outputFormats = []string{"plain", "json", "markdown"}
+
+// hideCachedMarker leaves the "(cached ...)" line out of the plain output,
+// set by -hide-cached-marker. The JSON output keeps its cached field.
+var hideCachedMarker = false
+
+// prettyJSON indents the JSON output for reading, set by -json-pretty.
+// The default stays compact for piping.
+var prettyJSON = false
+
+// marshalJSON encodes v for the JSON output, indented with prettyJSON.
+func marshalJSON(v any) ([]byte, error) {
+	if prettyJSON {
+		return json.MarshalIndent(v, "", "  ")
+	}
+	return json.Marshal(v)
+}
+
+// noSummaryMessage replaces the summary of pages that have neither an
+// extract nor a description, the URL is printed below it.
+const noSummaryMessage = "This page has no summary, open the link to read it."
+
+// Result is everything an OutputWriter needs to display a lookup.
+type Result struct {
+	Title       string            `json:"title"`
+	Lang        string            `json:"lang"`
+	Summary     string            `json:"summary"`
+	Description string            `json:"description,omitempty"`
+	URL         string            `json:"url"`
+	Coordinates *wiki.Coordinates `json:"coordinates,omitempty"`
+	// Modified is when the article was last edited, nil if unknown
+	Modified *time.Time `json:"modified,omitempty"`
+	Cached   bool       `json:"cached"`
+	// CachedAt and CacheTTL are only shown in the plain output
+	CachedAt time.Time     `json:"-"`
+	CacheTTL time.Duration `json:"-"`
+	// RedirectedFrom is the requested title when it redirected to Title
+	RedirectedFrom string `json:"redirected_from,omitempty"`
+	// Stale marks an expired cache entry shown because the fetch failed
+	Stale bool `json:"stale,omitempty"`
+}
+
+// OutputWriter displays a result in one output format. New formats only
+// need a new implementation and an entry in newOutputWriter.
+type OutputWriter interface {
+	Write(result Result) error
+}
+
+func newResult(lang, title string, entry wiki.CacheEntry, cached bool) Result {
+	var redirectedFrom string
+	if entry.Canonical != "" && wiki.CanonicalTitle(title) != entry.Canonical {
+		redirectedFrom = title
+	}
+	if entry.Title != "" {
+		title = entry.Title
+	}
+	if entry.Lang != "" {
+		lang = entry.Lang
+	}
+	result := Result{
+		Title:       title,
+		Lang:        lang,
+		Summary:     shortenSummary(entry.Summary),
+		Description: entry.Description,
+		URL:         entry.URL,
+		Coordinates: entry.Coordinates,
+		Cached:      cached,
+
+		RedirectedFrom: redirectedFrom,
+		Stale:          entry.Stale,
+	}
+	if !entry.Modified.IsZero() {
+		modified := entry.Modified
+		result.Modified = &modified
+	}
+	if cached {
+		result.CachedAt = entry.Timestamp
+		result.CacheTTL = entry.TTL()
+	}
+	return result
+}
+
+// newOutputWriter returns the writer for format. The width is used to
+// wrap plain text output, 0 disables wrapping.
+func newOutputWriter(format string, out io.Writer, width int) (OutputWriter, error) {
+	switch format {
+	case "plain":
+		return &plainWriter{out: out, width: width}, nil
+	case "json":
+		return &jsonWriter{out: out}, nil
+	case "markdown":
+		return &markdownWriter{out: out}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q, available formats: %s", format, strings.Join(outputFormats, ", "))
+}
+
+// plainWriter prints the result with colored headers for the terminal.
+type plainWriter struct {
+	out   io.Writer
+	width int
+}
+
+func (w *plainWriter) Write(result Result) error {
+	fmt.Fprint(w.out, "\n\n")
+	if result.Title != "" {
+		activeTheme.Title.Fprintln(w.out, result.Title)
+		if result.RedirectedFrom != "" {
+			activeTheme.Cached.Fprintf(w.out, "(redirected from %s to %s)\n", result.RedirectedFrom, result.Title)
+		}
+		fmt.Fprintln(w.out)
+	}
+	activeTheme.Summary.Fprintln(w.out, "Summary:")
+	// A stale result is always marked, it may be outdated
+	if result.Cached && (!hideCachedMarker || result.Stale) {
+		now := time.Now()
+		cachedColor(result, now).Fprintln(w.out, cachedLabel(result, now))
+	}
+	summary := result.Summary
+	if summary == "" {
+		summary = noSummaryMessage
+	}
+	if summaryBullets && result.Summary != "" {
+		summary = strings.Join(bulletLines(summary, w.width), "\n")
+	} else if w.width > 0 {
+		summary = strings.Join(wrapText(summary, w.width), "\n")
+	}
+	if colorSummary && result.Summary != "" {
+		summary = highlightSummary(summary, result.Title, activeTheme.Title.Sprint)
+	}
+	fmt.Fprintln(w.out, summary)
+	activeTheme.URL.Fprintln(w.out, "\nURL:")
+	fmt.Fprintln(w.out, result.URL)
+	if result.Modified != nil || modifiedSince > 0 {
+		now := time.Now()
+		activeTheme.Summary.Fprintln(w.out, "\nLast edited:")
+		fmt.Fprintln(w.out, modifiedLabel(result, now))
+		if modifiedSince > 0 && !editedWithin(result, modifiedSince, now) {
+			activeTheme.Error.Fprintf(w.out, "Not edited within the last %s\n", modifiedSince)
+		}
+	}
+	if result.Coordinates != nil {
+		activeTheme.Coordinates.Fprintln(w.out, "\nCoordinates:")
+		fmt.Fprintf(w.out, "%.5f, %.5f\n", result.Coordinates.Lat, result.Coordinates.Lon)
+		fmt.Fprintln(w.out, result.Coordinates.OpenStreetMapURL())
+	}
+	return nil
+}
+
+type jsonWriter struct {
+	out io.Writer
+}
+
+func (w *jsonWriter) Write(result Result) error {
+	data, err := marshalJSON(result)
+	if err != nil {
+		return fmt.Errorf("error encoding result: %v", err)
+	}
+	_, err = fmt.Fprintln(w.out, string(data))
+	return err
+}
+
+type markdownWriter struct {
+	out io.Writer
+}
+
+func (w *markdownWriter) Write(result Result) error {
+	fmt.Fprintf(w.out, "## %s\n\n", result.Title)
+	if result.RedirectedFrom != "" {
+		fmt.Fprintf(w.out, "_Redirected from %s_\n\n", result.RedirectedFrom)
+	}
+	if result.Description != "" {
+		fmt.Fprintf(w.out, "*%s*\n\n", result.Description)
+	}
+	summary := result.Summary
+	if summary == "" {
+		summary = noSummaryMessage
+	} else if summaryBullets {
+		summary = strings.Join(bulletLines(summary, 0), "\n")
+	}
+	fmt.Fprintf(w.out, "%s\n\n", summary)
+	fmt.Fprintf(w.out, "[%s](%s)\n", result.URL, result.URL)
+	if result.Modified != nil {
+		fmt.Fprintf(w.out, "\nLast edited: %s\n", result.Modified.UTC().Format("2006-01-02 15:04 MST"))
+	}
+	if result.Coordinates != nil {
+		fmt.Fprintf(w.out, "\nCoordinates: %.5f, %.5f ([OpenStreetMap](%s))\n", result.Coordinates.Lat, result.Coordinates.Lon, result.Coordinates.OpenStreetMapURL())
+	}
+	return nil
+}
+
+// descriptionWriter prints only the one-line description.
+type descriptionWriter struct {
+	out io.Writer
+}
+
+func (w *descriptionWriter) Write(result Result) error {
+	_, err := fmt.Fprintln(w.out, describe(result))
+	return err
+}
+
+// sentenceWriter prints only the first sentence of the summary, the
+// summary is cut to it already by newResult.
+type sentenceWriter struct {
+	out io.Writer
+}
+
+func (w *sentenceWriter) Write(result Result) error {
+	sentence := firstSentence(result.Summary)
+	if sentence == "" {
+		sentence = noSummaryMessage
+	}
+	_, err := fmt.Fprintln(w.out, sentence)
+	return err
+}
+
+// urlWriter prints only the article URL, for command substitution.
+type urlWriter struct {
+	out io.Writer
+}
+
+func (w *urlWriter) Write(result Result) error {
+	_, err := fmt.Fprintln(w.out, result.URL)
+	return err
+}
+
+// describe returns the short description of the article, or the first
+// sentence of the summary when the API did not provide one.
+func describe(result Result) string {
+	if result.Description != "" {
+		return result.Description
+	}
+	return firstSentence(result.Summary)
+}
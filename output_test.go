@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+var testResult = Result{
+	Title:       "Berlin",
+	Lang:        "de",
+	Summary:     "Berlin ist die Hauptstadt der Bundesrepublik Deutschland.",
+	Description: "Hauptstadt Deutschlands",
+	URL:         "https://de.wikipedia.org/wiki/Berlin",
+	Coordinates: &wiki.Coordinates{Lat: 52.51667, Lon: 13.38333},
+	Cached:      true,
+}
+
+func TestPlainWriter(t *testing.T) {
+	var output bytes.Buffer
+	if err := (&plainWriter{out: &output}).Write(testResult); err != nil {
+		t.Fatalf("Write sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	for _, want := range []string{"Berlin", "Summary:", "(cached)", testResult.Summary, "URL:", testResult.URL, "Coordinates:", "52.51667, 13.38333"} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("Die Ausgabe sollte '%s' enthalten: %q", want, output.String())
+		}
+	}
+}
+
+func TestPlainWriterHidesCachedMarker(t *testing.T) {
+	hideCachedMarker = true
+	defer func() { hideCachedMarker = false }()
+
+	var output bytes.Buffer
+	if err := (&plainWriter{out: &output}).Write(testResult); err != nil {
+		t.Fatalf("Write sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if strings.Contains(output.String(), "(cached") {
+		t.Errorf("Die Ausgabe sollte keine Cache-Markierung enthalten: %q", output.String())
+	}
+
+	output.Reset()
+	if err := (&jsonWriter{out: &output}).Write(testResult); err != nil {
+		t.Fatalf("Write sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if !strings.Contains(output.String(), `"cached":true`) {
+		t.Errorf("Das JSON sollte das Feld cached behalten: %s", output.String())
+	}
+}
+
+func TestPlainWriterWithoutSummary(t *testing.T) {
+	var output bytes.Buffer
+	result := Result{Title: "Stub", URL: "https://de.wikipedia.org/wiki/Stub"}
+	if err := (&plainWriter{out: &output}).Write(result); err != nil {
+		t.Fatalf("Write sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	for _, want := range []string{noSummaryMessage, result.URL} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("Die Ausgabe sollte '%s' enthalten: %q", want, output.String())
+		}
+	}
+}
+
+func TestPlainWriterWrapsSummary(t *testing.T) {
+	var output bytes.Buffer
+	if err := (&plainWriter{out: &output, width: 20}).Write(testResult); err != nil {
+		t.Fatalf("Write sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	if !strings.Contains(output.String(), "Berlin ist die\nHauptstadt der\n") {
+		t.Errorf("Die Zusammenfassung sollte auf 20 Zeichen umbrochen werden: %q", output.String())
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	var output bytes.Buffer
+	if err := (&jsonWriter{out: &output}).Write(testResult); err != nil {
+		t.Fatalf("Write sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	var decoded Result
+	if err := json.Unmarshal(output.Bytes(), &decoded); err != nil {
+		t.Fatalf("Die Ausgabe sollte gültiges JSON sein: %v", err)
+	}
+
+	if decoded.Title != testResult.Title || decoded.URL != testResult.URL || !decoded.Cached {
+		t.Errorf("Unerwartetes Ergebnis: %+v", decoded)
+	}
+	if decoded.Coordinates == nil || decoded.Coordinates.Lat != testResult.Coordinates.Lat {
+		t.Errorf("Die Koordinaten sollten erhalten bleiben: %+v", decoded.Coordinates)
+	}
+}
+
+func TestJSONWriterPretty(t *testing.T) {
+	var compact, pretty bytes.Buffer
+	if err := (&jsonWriter{out: &compact}).Write(testResult); err != nil {
+		t.Fatalf("Write sollte keinen Fehler zurückgeben: %v", err)
+	}
+	prettyJSON = true
+	defer func() { prettyJSON = false }()
+	if err := (&jsonWriter{out: &pretty}).Write(testResult); err != nil {
+		t.Fatalf("Write sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	if strings.Count(compact.String(), "\n") != 1 {
+		t.Errorf("Die Standardausgabe sollte kompakt bleiben: %q", compact.String())
+	}
+	if !strings.Contains(pretty.String(), "{\n  \"title\": ") {
+		t.Errorf("Die Ausgabe sollte eingerückt sein: %q", pretty.String())
+	}
+	var decoded Result
+	if err := json.Unmarshal(pretty.Bytes(), &decoded); err != nil || decoded.Title != testResult.Title {
+		t.Errorf("Die eingerückte Ausgabe sollte gültiges JSON sein: %v, %+v", err, decoded)
+	}
+}
+
+func TestRunJSONPretty(t *testing.T) {
+	setTestHome(t)
+
+	stdout, _ := captureOutput(t, func() {
+		if code := run([]string{"-lang-list", "-json-pretty"}); code != exitOK {
+			t.Errorf("Exit-Code = %d, erwartet %d", code, exitOK)
+		}
+	})
+	if !strings.Contains(stdout, "[\n  {\n    \"code\": \"ar\"") {
+		t.Errorf("Die Sprachliste sollte eingerückt sein: %q", stdout)
+	}
+	if prettyJSON {
+		t.Error("prettyJSON sollte nach run zurückgesetzt sein")
+	}
+}
+
+func TestMarkdownWriter(t *testing.T) {
+	var output bytes.Buffer
+	if err := (&markdownWriter{out: &output}).Write(testResult); err != nil {
+		t.Fatalf("Write sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	if !strings.HasPrefix(output.String(), "## Berlin\n") {
+		t.Errorf("Die Ausgabe sollte mit der Überschrift beginnen: %q", output.String())
+	}
+	if !strings.Contains(output.String(), "[https://de.wikipedia.org/wiki/Berlin](https://de.wikipedia.org/wiki/Berlin)") {
+		t.Errorf("Die Ausgabe sollte einen Markdown-Link enthalten: %q", output.String())
+	}
+}
+
+func TestDescriptionWriter(t *testing.T) {
+	var output bytes.Buffer
+	if err := (&descriptionWriter{out: &output}).Write(testResult); err != nil {
+		t.Fatalf("Write sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	if output.String() != "Hauptstadt Deutschlands\n" {
+		t.Errorf("Erwartete nur die Beschreibung, erhielt %q", output.String())
+	}
+}
+
+func TestNewOutputWriter(t *testing.T) {
+	for _, format := range outputFormats {
+		if _, err := newOutputWriter(format, &bytes.Buffer{}, 80); err != nil {
+			t.Errorf("Das Format '%s' sollte unterstützt werden: %v", format, err)
+		}
+	}
+
+	if _, err := newOutputWriter("xml", &bytes.Buffer{}, 80); err == nil {
+		t.Error("Ein unbekanntes Format sollte einen Fehler liefern")
+	}
+}
+
+func TestRedirectNote(t *testing.T) {
+	entry := wiki.CacheEntry{Title: "John F. Kennedy", Canonical: "John_F._Kennedy", Summary: "John Fitzgerald Kennedy was the 35th president."}
+
+	result := newResult("en", "JFK", entry, false)
+	if result.RedirectedFrom != "JFK" {
+		t.Errorf("Erwartete 'JFK' als Ursprung der Weiterleitung, erhielt '%s'", result.RedirectedFrom)
+	}
+
+	var out bytes.Buffer
+	(&plainWriter{out: &out}).Write(result)
+	if !strings.Contains(out.String(), "(redirected from JFK to John F. Kennedy)") {
+		t.Errorf("Die Ausgabe sollte die Weiterleitung melden: %q", out.String())
+	}
+
+	// Eine andere Schreibweise desselben Titels ist keine Weiterleitung
+	if result := newResult("en", "john F. Kennedy", entry, false); result.RedirectedFrom != "" {
+		t.Errorf("Eine Schreibvariante sollte nicht als Weiterleitung gelten, erhielt '%s'", result.RedirectedFrom)
+	}
+}
+
+func TestURLOnly(t *testing.T) {
+	setTestHome(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("list") == "search" {
+			fmt.Fprint(w, `{"query": {"search": [{"title": "Berlin"}, {"title": "Berlin (Begriffsklärung)"}]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"title": "Berlin", "titles": {"canonical": "Berlin"}, "extract": "Berlin ist die Hauptstadt Deutschlands.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+	}))
+	defer server.Close()
+
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	// Die Auswahl findet weiterhin statt, das Menü landet aber auf stderr
+	stdinReader = bufio.NewReader(strings.NewReader("1\n"))
+	defer func() { stdinReader = bufio.NewReader(os.Stdin) }()
+
+	var code int
+	stdout, stderr := captureOutput(t, func() {
+		code = run([]string{"-url-only", "Berlin"})
+	})
+	if code != exitOK {
+		t.Fatalf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+	if stdout != "https://de.wikipedia.org/wiki/Berlin\n" {
+		t.Errorf("stdout sollte nur die URL enthalten, erhielt %q", stdout)
+	}
+	if !strings.Contains(stderr, "Multiple results found") {
+		t.Errorf("Das Auswahlmenü sollte auf stderr erscheinen: %q", stderr)
+	}
+}
+
+func TestOutputFile(t *testing.T) {
+	setTestHome(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("list") == "search" {
+			fmt.Fprint(w, `{"query": {"search": [{"title": "Berlin"}]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"title": "Berlin", "titles": {"canonical": "Berlin"}, "extract": "Berlin ist die Hauptstadt Deutschlands.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+	}))
+	defer server.Close()
+
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	path := filepath.Join(t.TempDir(), "berlin.json")
+	// Eine vorhandene Datei wird überschrieben
+	os.WriteFile(path, []byte("alter Inhalt, der länger ist als das Ergebnis\n"+strings.Repeat("x", 500)), 0644)
+
+	var code int
+	stdout, _ := captureOutput(t, func() {
+		code = run([]string{"-json", "-o", path, "Berlin"})
+	})
+	if code != exitOK {
+		t.Fatalf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+	if strings.Contains(stdout, "Hauptstadt") {
+		t.Errorf("Das Ergebnis sollte nicht auf stdout landen: %q", stdout)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Die Datei sollte lesbar sein: %v", err)
+	}
+	var result Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Die Datei sollte nur gültiges JSON enthalten: %v, Inhalt %q", err, data)
+	}
+	if result.Title != "Berlin" || result.URL != "https://de.wikipedia.org/wiki/Berlin" {
+		t.Errorf("Unerwartetes Ergebnis: %+v", result)
+	}
+}
+
+func TestOutputFileNotWritable(t *testing.T) {
+	setTestHome(t)
+	path := filepath.Join(t.TempDir(), "fehlt", "berlin.json")
+
+	var code int
+	_, stderr := captureOutput(t, func() {
+		code = run([]string{"-o", path, "Berlin"})
+	})
+	if code != exitUsage {
+		t.Errorf("Exit-Code = %d, erwartet %d", code, exitUsage)
+	}
+	if !strings.Contains(stderr, "Error:") {
+		t.Errorf("Der Fehler sollte auf stderr gemeldet werden: %q", stderr)
+	}
+}
+
+func TestSentenceWriter(t *testing.T) {
+	tests := map[string]string{
+		"Berlin ist die Hauptstadt Deutschlands. Sie hat 3,8 Mio. Einwohner.":                     "Berlin ist die Hauptstadt Deutschlands.",
+		"Der Tag der Deutschen Einheit am 3. Oktober ist ein Feiertag. Er wurde 1990 eingeführt.": "Der Tag der Deutschen Einheit am 3. Oktober ist ein Feiertag.",
+		"J. R. R. Tolkien war ein britischer Schriftsteller, z. B. von Romanen. Er starb 1973.":   "J. R. R. Tolkien war ein britischer Schriftsteller, z. B. von Romanen.",
+		"Nur ein Satz ohne Punkt": "Nur ein Satz ohne Punkt",
+		"":                        noSummaryMessage,
+	}
+	for summary, want := range tests {
+		var output bytes.Buffer
+		if err := (&sentenceWriter{out: &output}).Write(Result{Summary: summary}); err != nil {
+			t.Fatalf("Write sollte keinen Fehler zurückgeben: %v", err)
+		}
+		if output.String() != want+"\n" {
+			t.Errorf("Für %q erwartet %q, erhielt %q", summary, want, output.String())
+		}
+	}
+}
+
+func TestFirstSentenceIgnoresCharacterLimit(t *testing.T) {
+	setTestHome(t)
+	long := strings.Repeat("sehr ", 250) + "langer Satz. Zweiter Satz."
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/w/api.php") {
+			fmt.Fprint(w, `{"query": {"search": [{"title": "Lang"}]}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"title": "Lang", "extract": %q, "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Lang"}}}`, long)
+	}))
+	defer server.Close()
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	stdout, _ := captureOutput(t, func() {
+		if code := run([]string{"-first-sentence", "Lang"}); code != exitOK {
+			t.Errorf("Exit-Code = %d, erwartet %d", code, exitOK)
+		}
+	})
+	if want := strings.Repeat("sehr ", 250) + "langer Satz.\n"; !strings.HasSuffix(stdout, want) || strings.Contains(stdout, "Zweiter") {
+		t.Errorf("Erwartete den ganzen ersten Satz, erhielt %q", stdout)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+// runPageID prints the summary of the article with the page ID, for -pageid.
+func runPageID(ctx context.Context, lang string, pageID int, output OutputWriter, copy bool) int {
+	stopLoading := startLoadingAnimation()
+	entry, cached, err := newClient(lang).SummaryByPageID(ctx, pageID)
+	if err == nil {
+		entry = formatEntryURL(ctx, lang, entry)
+	}
+	stopLoading()
+	if err != nil {
+		activeTheme.Error.Fprintf(os.Stderr, "Error fetching summary: %v\n", err)
+		return exitCodeFor(err)
+	}
+	return showEntry(lang, entry, cached, output, copy)
+}
+
+// showEntry displays an entry that was looked up without a search, like
+// searchState.run does for a chosen result.
+func showEntry(lang string, entry wiki.CacheEntry, cached bool, output OutputWriter, copy bool) int {
+	result := newResult(lang, entry.Title, entry, cached)
+	addHistoryEntry(result.Lang, result.Title)
+	if err := output.Write(result); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitNoResults
+	}
+	if copy {
+		copyURL(result.URL)
+	}
+	return exitOK
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+// pinArticles pins the articles that are looked up, so they never expire,
+// set by -pin.
+var pinArticles = false
+
+// hasLangPrefix reports whether the argument names a cached article like
+// "de:Berlin", which -pin pins without looking it up.
+func hasLangPrefix(arg string) bool {
+	prefix, _, found := strings.Cut(arg, ":")
+	_, known := supportedLanguages[prefix]
+	return found && known
+}
+
+// runPin pins or unpins one cached article, given as "de:Berlin" or as
+// the title with -lang, without any network access.
+func runPin(lang, arg string, pinned bool, out io.Writer) int {
+	lang, title := splitCacheInfoArg(arg, lang)
+	key := wiki.ProjectCacheKey(project, lang, title)
+	if !newClient(lang).Pin(title, pinned) {
+		fmt.Fprintf(os.Stderr, "Not cached: %s\n", key)
+		return exitNoResults
+	}
+	if pinned {
+		fmt.Fprintf(out, "Pinned: %s\n", key)
+	} else {
+		fmt.Fprintf(out, "Unpinned: %s\n", key)
+	}
+	return exitOK
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestHasLangPrefix(t *testing.T) {
+	tests := map[string]bool{
+		"de:Berlin":          true,
+		"simple:Moon":        true,
+		"Berlin":             false,
+		"Star Trek: Voyager": false,
+	}
+	for arg, want := range tests {
+		if got := hasLangPrefix(arg); got != want {
+			t.Errorf("hasLangPrefix(%q) = %v, erwartet %v", arg, got, want)
+		}
+	}
+}
+
+func TestRunPinAndUnpin(t *testing.T) {
+	setTestHome(t)
+	old := time.Now().Add(-wiki.RevalidateDuration - time.Hour)
+	fileCache().Save(wiki.Cache{
+		"de:Berlin": wiki.CacheEntry{Title: "Berlin", Summary: "Berlin ist die Hauptstadt.", Timestamp: time.Now()},
+	})
+
+	stdout, _ := captureOutput(t, func() {
+		if code := run([]string{"-pin", "de:Berlin"}); code != exitOK {
+			t.Errorf("Exit-Code = %d, erwartet %d", code, exitOK)
+		}
+	})
+	if !strings.Contains(stdout, "Pinned: de:Berlin") {
+		t.Errorf("Die Ausgabe sollte den Schlüssel nennen: %q", stdout)
+	}
+
+	// Age the pinned entry past its TTL and the revalidation window
+	cache := fileCache().Load()
+	entry := cache["de:Berlin"]
+	entry.Timestamp = old
+	cache["de:Berlin"] = entry
+	fileCache().Save(cache)
+	if _, found := fileCache().Get("de", "Berlin"); !found {
+		t.Fatal("Ein angepinnter Artikel sollte nicht ablaufen")
+	}
+	if removed := fileCache().ClearExpired(); removed != 0 {
+		t.Errorf("Angepinnte Artikel sollten nicht entfernt werden, entfernt: %d", removed)
+	}
+
+	captureOutput(t, func() {
+		if code := run([]string{"-unpin", "-lang", "de", "Berlin"}); code != exitOK {
+			t.Errorf("Exit-Code = %d, erwartet %d", code, exitOK)
+		}
+	})
+	if _, found := fileCache().Get("de", "Berlin"); found {
+		t.Error("Nach -unpin sollte der alte Eintrag abgelaufen sein")
+	}
+
+	_, stderr := captureOutput(t, func() {
+		if code := run([]string{"-pin", "de:Paris"}); code != exitNoResults {
+			t.Errorf("Exit-Code = %d, erwartet %d", code, exitNoResults)
+		}
+	})
+	if !strings.Contains(stderr, "Not cached: de:Paris") {
+		t.Errorf("Fehlermeldung fehlt: %q", stderr)
+	}
+}
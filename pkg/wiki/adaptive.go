@@ -0,0 +1,40 @@
+package wiki
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// DefaultMinAdaptiveTTL and DefaultMaxAdaptiveTTL bound the TTL of
+	// WithAdaptiveTTL unless other bounds are given
+	DefaultMinAdaptiveTTL = time.Hour
+	DefaultMaxAdaptiveTTL = 7 * 24 * time.Hour
+	// adaptiveTTLDoubling is how long an article has to stay unedited for
+	// its TTL to double
+	adaptiveTTLDoubling = 7 * 24 * time.Hour
+)
+
+// AdaptiveTTL returns the TTL for an article last edited sinceEdit ago. It
+// starts at min for an article edited just now and doubles for every week
+// the article stays unedited, up to max, so articles that change often are
+// fetched again sooner than stable ones.
+func AdaptiveTTL(sinceEdit, min, max time.Duration) time.Duration {
+	if sinceEdit <= 0 {
+		return min
+	}
+	ttl := float64(min) * math.Exp2(float64(sinceEdit)/float64(adaptiveTTLDoubling))
+	if ttl >= float64(max) {
+		return max
+	}
+	return time.Duration(ttl)
+}
+
+// WithAdaptiveTTL lets the TTL of every article the client caches depend
+// on when it was last edited, see AdaptiveTTL. Articles without an edit
+// time keep CacheDuration, and WithTTL takes precedence.
+func WithAdaptiveTTL(min, max time.Duration) Option {
+	return func(c *Client) {
+		c.adaptiveMin, c.adaptiveMax = min, max
+	}
+}
@@ -0,0 +1,65 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveTTL(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		sinceEdit time.Duration
+		want      time.Duration
+	}{
+		{-time.Hour, time.Hour},
+		{0, time.Hour},
+		{7 * day, 2 * time.Hour},
+		{14 * day, 4 * time.Hour},
+		{28 * day, 16 * time.Hour},
+		{49 * day, 128 * time.Hour},
+		{60 * day, 7 * day},
+		{10 * 365 * day, 7 * day},
+	}
+	for _, test := range tests {
+		if got := AdaptiveTTL(test.sinceEdit, DefaultMinAdaptiveTTL, DefaultMaxAdaptiveTTL); got != test.want {
+			t.Errorf("AdaptiveTTL(%v) = %v, erwartet %v", test.sinceEdit, got, test.want)
+		}
+	}
+
+	// Eine Bearbeitung vor drei Tagen liegt zwischen den Verdopplungen
+	if got := AdaptiveTTL(3*day, time.Hour, day); got <= time.Hour || got >= 2*time.Hour {
+		t.Errorf("Nach drei Tagen sollte die TTL zwischen 1h und 2h liegen, erhielt %v", got)
+	}
+	if got := AdaptiveTTL(day, 10*time.Minute, 30*time.Minute); got < 10*time.Minute || got > 30*time.Minute {
+		t.Errorf("Die TTL sollte in den Grenzen bleiben, erhielt %v", got)
+	}
+}
+
+func TestWithAdaptiveTTLStoresTTL(t *testing.T) {
+	lastEdit := time.Now().Add(-14 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"title": "Berlin", "extract": "Berlin ist die Hauptstadt.", "timestamp": %q, "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`, lastEdit)
+	}))
+	defer server.Close()
+
+	fileCache := newTestCache(t)
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithCache(fileCache), WithAdaptiveTTL(DefaultMinAdaptiveTTL, DefaultMaxAdaptiveTTL))
+	if _, _, err := client.Summary(context.Background(), "Berlin"); err != nil {
+		t.Fatalf("Summary sollte keinen Fehler zurückgeben: %v", err)
+	}
+	entry, _ := fileCache.Get("de", "Berlin")
+	if ttl := entry.TTL(); ttl < 4*time.Hour-time.Minute || ttl > 4*time.Hour+time.Minute {
+		t.Errorf("Zwei Wochen nach der letzten Bearbeitung sollte die TTL etwa 4h sein, erhielt %v", ttl)
+	}
+
+	// -pin-ttl hat Vorrang
+	client = NewClient("de", WithHost(server.URL+"/%s"), WithCache(fileCache), WithAdaptiveTTL(DefaultMinAdaptiveTTL, DefaultMaxAdaptiveTTL), WithTTL(time.Minute))
+	client.FetchSummary(context.Background(), "Berlin")
+	if entry, _ := fileCache.GetStale("de:Berlin"); entry.TTL() != time.Minute {
+		t.Errorf("WithTTL sollte Vorrang haben, erhielt %v", entry.TTL())
+	}
+}
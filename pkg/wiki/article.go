@@ -0,0 +1,91 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// headingPattern matches the headings that start the sections of an
+	// article, the level is the first group
+	headingPattern = regexp.MustCompile(`(?is)<h([2-6])[^>]*>(.*?)</h[2-6]>`)
+	// Infoboxes, navigation boxes and tables do not read as text
+	htmlTablePattern = regexp.MustCompile(`(?is)<table.*?</table>`)
+)
+
+// ArticleSection is one part of an article with its heading. The lead
+// section before the first heading has level 0 and no title.
+type ArticleSection struct {
+	Level int
+	Title string
+	Text  string
+}
+
+// Article returns the whole article as plain text, split at its headings.
+// It replaces the retired mobile-sections endpoint of the REST API with
+// the parse API, which Section uses as well. Articles are not cached. An
+// article longer than WithMaxBytes is returned as far as it was read,
+// together with a TooLargeError.
+func (c *Client) Article(ctx context.Context, title string) ([]ArticleSection, error) {
+	body, _, err := c.get(ctx, c.ArticleTextURL(title))
+	if errors.Is(err, ErrTooLarge) {
+		return splitArticle(truncatedParseText(body)), err
+	}
+	if err != nil {
+		return nil, err
+	}
+	text, err := parseHTML(body)
+	if err != nil {
+		return nil, err
+	}
+	return splitArticle(text), nil
+}
+
+// ArticleTextURL returns the URL that Article requests for the title.
+func (c *Client) ArticleTextURL(title string) string {
+	return c.parseURL(title, "&prop=text&disabletoc=1&disableeditsection=1")
+}
+
+// splitArticle splits the rendered HTML of an article at its headings and
+// converts every part to plain text. Sections without text are left out,
+// unless subsections follow that have some.
+func splitArticle(text string) []ArticleSection {
+	text = htmlTablePattern.ReplaceAllString(text, "")
+	matches := headingPattern.FindAllStringSubmatchIndex(text, -1)
+
+	sections := []ArticleSection{{Text: stripHTML(text[:firstIndex(matches, len(text))])}}
+	for i, match := range matches {
+		end := len(text)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		sections = append(sections, ArticleSection{
+			Level: int(text[match[2]] - '0'),
+			Title: strings.TrimSpace(html.UnescapeString(htmlTagPattern.ReplaceAllString(text[match[4]:match[5]], ""))),
+			Text:  stripHTML(text[match[1]:end]),
+		})
+	}
+
+	// Going backwards, the next kept section tells whether an empty
+	// heading has subsections with text
+	var kept []ArticleSection
+	for i := len(sections) - 1; i >= 0; i-- {
+		section := sections[i]
+		hasSubsections := section.Level > 0 && len(kept) > 0 && kept[0].Level > section.Level
+		if section.Text == "" && !hasSubsections {
+			continue
+		}
+		kept = append([]ArticleSection{section}, kept...)
+	}
+	return kept
+}
+
+func firstIndex(matches [][]int, fallback int) int {
+	if len(matches) == 0 {
+		return fallback
+	}
+	return matches[0][0]
+}
@@ -0,0 +1,60 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testArticleResponse = `{
+	"parse": {
+		"title": "Berlin",
+		"text": "<div class=\"mw-parser-output\"><table class=\"infobox\"><tr><td>Einwohner</td><td>3,8 Mio.</td></tr></table><p><b>Berlin</b> ist die Hauptstadt Deutschlands.</p><div class=\"mw-heading mw-heading2\"><h2 id=\"Geschichte\">Geschichte</h2></div><div class=\"mw-heading mw-heading3\"><h3 id=\"Mittelalter\">Mittelalter</h3></div><p>Berlin wurde 1237 erstmals erw&auml;hnt.</p><h3>Neuzeit</h3><p>Seit 1990 ist Berlin die <a href=\"/wiki/Hauptstadt\">Hauptstadt</a>.</p><h2>Einzelnachweise</h2></div>"
+	}
+}`
+
+func TestSplitArticle(t *testing.T) {
+	text, err := parseHTML([]byte(testArticleResponse))
+	if err != nil {
+		t.Fatalf("parseHTML sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	want := []ArticleSection{
+		{Level: 0, Text: "Berlin ist die Hauptstadt Deutschlands."},
+		{Level: 2, Title: "Geschichte"},
+		{Level: 3, Title: "Mittelalter", Text: "Berlin wurde 1237 erstmals erwähnt."},
+		{Level: 3, Title: "Neuzeit", Text: "Seit 1990 ist Berlin die Hauptstadt."},
+	}
+	got := splitArticle(text)
+	if len(got) != len(want) {
+		t.Fatalf("Erwartete %d Abschnitte, erhielt %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Abschnitt %d: erwartet %+v, erhielt %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestArticle(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		fmt.Fprint(w, testArticleResponse)
+	}))
+	defer server.Close()
+
+	client := NewClient("de", WithHost(server.URL+"/%s"))
+	sections, err := client.Article(context.Background(), "Berlin")
+	if err != nil {
+		t.Fatalf("Article sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if len(sections) != 4 {
+		t.Errorf("Erwartete 4 Abschnitte, erhielt %d", len(sections))
+	}
+	if want := "action=parse&page=Berlin&prop=text&disabletoc=1&disableeditsection=1&redirects=1&format=json&formatversion=2"; query != want {
+		t.Errorf("Erwartete Anfrage %q, erhielt %q", want, query)
+	}
+}
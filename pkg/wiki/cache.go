@@ -0,0 +1,456 @@
+package wiki
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// CacheFileName is the legacy name of the cache in the home directory
+	CacheFileName         = ".wikr_cache.json"
+	CacheDuration         = 24 * time.Hour
+	NotFoundCacheDuration = time.Hour
+	// RevalidateDuration is how long expired entries with an ETag are
+	// kept so they can be revalidated instead of downloaded again
+	RevalidateDuration = 30 * 24 * time.Hour
+	// DefaultCacheMode keeps the cache, which reveals what the user looked
+	// up, readable only by the user
+	DefaultCacheMode os.FileMode = 0600
+)
+
+type Coordinates struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// OpenStreetMapURL returns a map link centered on the coordinates.
+func (c Coordinates) OpenStreetMapURL() string {
+	return fmt.Sprintf("https://www.openstreetmap.org/?mlat=%.5f&mlon=%.5f#map=12/%.5f/%.5f", c.Lat, c.Lon, c.Lat, c.Lon)
+}
+
+// CacheEntry is an article summary as returned by Client.Summary and
+// stored in the cache.
+type CacheEntry struct {
+	Title       string       `json:"title,omitempty"`
+	Lang        string       `json:"lang,omitempty"`
+	Canonical   string       `json:"canonical,omitempty"`
+	Summary     string       `json:"summary"`
+	Description string       `json:"description,omitempty"`
+	URL         string       `json:"url"`
+	Coordinates *Coordinates `json:"coordinates,omitempty"`
+	// PageID and Revision identify the article and the revision the
+	// summary was taken from, zero if unknown
+	PageID   int   `json:"pageid,omitempty"`
+	Revision int64 `json:"revision,omitempty"`
+	// Modified is when the article was last edited, zero if unknown
+	Modified time.Time `json:"modified"`
+	// ETag of the summary response, used to revalidate expired entries
+	ETag string `json:"etag,omitempty"`
+	// RedirectTo marks an entry that only points to the canonical title
+	// of a redirect target, which holds the content
+	RedirectTo string `json:"redirect_to,omitempty"`
+	// LangLinks are the versions of the article in other languages, only
+	// set for entries stored under LangLinksKey
+	LangLinks []LangLink `json:"langlinks,omitempty"`
+	// NotFound marks a negative entry for a title without an article
+	NotFound bool `json:"not_found,omitempty"`
+	// CustomTTL overrides CacheDuration for this entry, see WithTTL
+	CustomTTL time.Duration `json:"ttl,omitempty"`
+	// Pinned entries never expire and are never pruned, see WithPin
+	Pinned    bool      `json:"pinned,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	// Stale marks an expired entry returned by Client.Stale, it is never
+	// stored
+	Stale bool `json:"-"`
+}
+
+// TTL returns how long the entry stays valid. Negative entries expire
+// sooner so that newly created articles show up quickly, other entries
+// may carry their own TTL.
+func (e CacheEntry) TTL() time.Duration {
+	if e.NotFound {
+		return NotFoundCacheDuration
+	}
+	if e.CustomTTL > 0 {
+		return e.CustomTTL
+	}
+	return CacheDuration
+}
+
+func (e CacheEntry) Expired() bool {
+	if e.Pinned {
+		return false
+	}
+	return time.Since(e.Timestamp) >= e.TTL()
+}
+
+// Cache maps the keys built by CacheKey to their entries.
+type Cache map[string]CacheEntry
+
+// Revalidatable reports whether the entry can still be renewed with a
+// conditional request.
+func (e CacheEntry) Revalidatable() bool {
+	return e.ETag != "" && time.Since(e.Timestamp) < RevalidateDuration
+}
+
+// Prune removes all expired entries that cannot be revalidated anymore and
+// returns how many were removed.
+func (c Cache) Prune() int {
+	return c.removeIf(func(entry CacheEntry) bool {
+		return entry.Expired() && !entry.Revalidatable()
+	})
+}
+
+// PruneExpired removes all expired entries, also those Prune keeps for
+// revalidation, and returns how many were removed.
+func (c Cache) PruneExpired() int {
+	return c.removeIf(CacheEntry.Expired)
+}
+
+func (c Cache) removeIf(remove func(CacheEntry) bool) int {
+	removed := 0
+	for key, entry := range c {
+		if remove(entry) {
+			delete(c, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// CacheKey builds the key for a title the way Wikipedia canonicalizes it,
+// so that "berlin" and "Berlin" share one entry.
+func CacheKey(lang, title string) string {
+	return lang + ":" + CanonicalTitle(title)
+}
+
+// CanonicalTitle uppercases the first letter and replaces spaces with
+// underscores, matching the "titles.canonical" field of the API. Titles
+// are composed to NFC first, like MediaWiki stores them.
+func CanonicalTitle(title string) string {
+	title = strings.ReplaceAll(strings.TrimSpace(norm.NFC.String(title)), " ", "_")
+	first, size := utf8.DecodeRuneInString(title)
+	if first == utf8.RuneError {
+		return title
+	}
+	return string(unicode.ToUpper(first)) + title[size:]
+}
+
+// DefaultCachePath returns the cache file in the platform cache directory,
+// e.g. ~/.cache/wikr/cache.json on Linux, or ~/.wikr_cache.json if wikr
+// created it there before.
+func DefaultCachePath() string {
+	return appFilePath(cacheDir, "cache.json", CacheFileName)
+}
+
+// fileMu serializes access to cache files. It is shared by all FileCache
+// values because several of them may point at the same file.
+var fileMu sync.Mutex
+
+// memoryCaches holds the caches whose file cannot be written, by path, so
+// that lookups still hit the cache until the process exits. Guarded by
+// fileMu.
+var memoryCaches = make(map[string]Cache)
+
+// FileCache stores the cache as a JSON file. It is safe for concurrent use
+// within one process.
+type FileCache struct {
+	path     string
+	mode     os.FileMode
+	compress bool
+	readOnly bool
+	log      *slog.Logger
+}
+
+func NewFileCache(path string) *FileCache {
+	return &FileCache{path: path, mode: DefaultCacheMode, log: discardLogger}
+}
+
+// SetMode sets the permissions of the cache file. New files are created
+// with the mode minus the umask, existing files are restricted to it.
+func (c *FileCache) SetMode(mode os.FileMode) {
+	c.mode = mode
+}
+
+// SetReadOnly keeps the cache from writing its file: entries are still
+// read, but new ones, pruning and migrations are not saved.
+func (c *FileCache) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
+
+// SetLogger sets the logger for reading and writing the cache file.
+func (c *FileCache) SetLogger(log *slog.Logger) {
+	c.log = log
+}
+
+func (c *FileCache) Path() string {
+	return c.path
+}
+
+// Load reads the cache file. Expired entries are removed and the file is
+// rewritten if there were any.
+func (c *FileCache) Load() Cache {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	return c.load()
+}
+
+func (c *FileCache) load() Cache {
+	cache, version := c.read()
+	// Only rewrite the file when it was migrated or something was removed
+	removed := cache.Prune()
+	if removed > 0 {
+		c.log.Debug("pruned expired cache entries", "removed", removed)
+	}
+	if version < CacheVersion {
+		c.log.Info("migrated cache file", "path", c.path, "from", version, "to", CacheVersion)
+	}
+	if removed > 0 || version < CacheVersion {
+		c.save(cache)
+	}
+	return cache
+}
+
+// read returns the entries of the cache file without pruning them, and
+// the schema version of the file. A file that cannot be read results in
+// an empty cache of the current version.
+func (c *FileCache) read() (Cache, int) {
+	if cache, ok := memoryCaches[c.path]; ok {
+		return maps.Clone(cache), CacheVersion
+	}
+	c.createIfNotExists()
+	cache := make(Cache)
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		c.log.Info("error reading cache file", "path", c.path, "error", err)
+		return cache, CacheVersion
+	}
+	data, err = decompressCache(data)
+	if err != nil {
+		c.backupCorrupt(err)
+		return cache, CacheVersion
+	}
+	entries, version, err := decodeCache(data)
+	if err != nil {
+		var unknown unknownVersionError
+		if errors.As(err, &unknown) {
+			c.log.Warn("ignoring cache file of a newer version", "path", c.path, "version", unknown.version)
+		} else {
+			c.backupCorrupt(err)
+		}
+		return cache, CacheVersion
+	}
+	return entries, version
+}
+
+// backupCorrupt moves a cache file that cannot be decoded aside, so the
+// next write starts fresh without destroying the old entries.
+func (c *FileCache) backupCorrupt(decodeErr error) {
+	backup := c.path + ".bak"
+	if err := os.Rename(c.path, backup); err != nil {
+		c.log.Warn("cache file is corrupt and could not be backed up", "path", c.path, "error", decodeErr, "backup_error", err)
+		return
+	}
+	c.log.Warn("cache file is corrupt, starting with an empty cache", "path", c.path, "backup", backup, "error", decodeErr)
+}
+
+// Save writes the cache in the current schema version.
+func (c *FileCache) Save(cache Cache) {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	c.save(cache)
+}
+
+func (c *FileCache) save(cache Cache) {
+	if c.readOnly {
+		return
+	}
+	data, err := json.Marshal(cacheFile{Version: CacheVersion, Entries: cache})
+	if err == nil && c.compress {
+		data, err = compressCache(data)
+	}
+	if err != nil {
+		c.log.Info("error encoding cache", "error", err)
+		return
+	}
+	err = c.writeFile(data)
+	if err != nil {
+		if _, ok := memoryCaches[c.path]; !ok {
+			c.log.Warn("cannot write the cache file, caching in memory only", "path", c.path, "error", err)
+		}
+		memoryCaches[c.path] = maps.Clone(cache)
+		return
+	}
+	delete(memoryCaches, c.path)
+}
+
+// Get returns the entry for the title if it has not expired yet.
+func (c *FileCache) Get(lang, title string) (CacheEntry, bool) {
+	return c.GetKey(CacheKey(lang, title))
+}
+
+// GetKey returns the entry stored under the raw key if it has not expired
+// yet.
+func (c *FileCache) GetKey(key string) (CacheEntry, bool) {
+	cache := c.Load()
+	entry, exists := cache[key]
+	if exists && !entry.Expired() {
+		c.log.Debug("cache hit", "key", key, "age", time.Since(entry.Timestamp))
+		return entry, true
+	}
+	c.log.Debug("cache miss", "key", key)
+	return CacheEntry{}, false
+}
+
+// GetStale returns the entry stored under the raw key even if it has
+// expired.
+func (c *FileCache) GetStale(key string) (CacheEntry, bool) {
+	entry, exists := c.Load()[key]
+	return entry, exists
+}
+
+// Set stores the entry for the title with the current time.
+func (c *FileCache) Set(lang, title string, entry CacheEntry) {
+	c.SetKey(CacheKey(lang, title), entry)
+}
+
+// SetKey stores the entry under the raw key with the current time.
+func (c *FileCache) SetKey(key string, entry CacheEntry) {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	cache := c.load()
+	entry.Timestamp = time.Now()
+	// A pinned article stays pinned when it is fetched again
+	if old, ok := cache[key]; ok && old.Pinned && !entry.NotFound {
+		entry.Pinned = true
+	}
+	cache[key] = entry
+	c.log.Debug("save cache entry", "key", key)
+	c.save(cache)
+}
+
+// Update changes the entry stored under the raw key in place and reports
+// whether there is one. Unlike SetKey it keeps the timestamp, so the
+// entry does not look fresher than it is.
+func (c *FileCache) Update(key string, update func(entry *CacheEntry)) bool {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	cache := c.load()
+	entry, ok := cache[key]
+	if !ok {
+		return false
+	}
+	update(&entry)
+	cache[key] = entry
+	c.log.Debug("update cache entry", "key", key)
+	c.save(cache)
+	return true
+}
+
+// SetPinned pins or unpins the entry stored under the raw key and reports
+// whether there is one. Its timestamp is kept, so an unpinned entry
+// expires as if it had never been pinned.
+func (c *FileCache) SetPinned(key string, pinned bool) bool {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	cache := c.load()
+	entry, ok := cache[key]
+	if !ok {
+		return false
+	}
+	if entry.Pinned != pinned {
+		entry.Pinned = pinned
+		cache[key] = entry
+		c.log.Debug("pin cache entry", "key", key, "pinned", pinned)
+		c.save(cache)
+	}
+	return true
+}
+
+// Clear deletes the cache file.
+func (c *FileCache) Clear() error {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	delete(memoryCaches, c.path)
+	err := os.Remove(c.path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error deleting cache file: %v", err)
+	}
+	c.log.Debug("cache deleted", "path", c.path)
+	return nil
+}
+
+// ClearExpired removes every expired entry from the cache file, unlike
+// the pruning on Load also those that could still be revalidated, and
+// returns how many were removed. Fresh entries are kept.
+func (c *FileCache) ClearExpired() int {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	cache, version := c.read()
+	removed := cache.PruneExpired()
+	if removed > 0 || version < CacheVersion {
+		c.save(cache)
+	}
+	c.log.Debug("cleared expired cache entries", "removed", removed)
+	return removed
+}
+
+func (c *FileCache) createIfNotExists() {
+	if c.readOnly {
+		return
+	}
+	if _, err := os.Stat(c.path); os.IsNotExist(err) {
+		data, err := json.Marshal(cacheFile{Version: CacheVersion, Entries: make(Cache)})
+		if err != nil {
+			c.log.Info("error creating empty cache file", "error", err)
+			return
+		}
+		err = c.writeFile(data)
+		if err != nil {
+			c.log.Info("error writing empty cache file", "path", c.path, "error", err)
+		} else {
+			c.log.Debug("empty cache file created", "path", c.path)
+		}
+	}
+}
+
+// writeFile writes the cache file with the configured mode. The data goes
+// to a temporary file first, which is renamed over the cache, so a wikr
+// killed in the middle of a write never leaves a truncated cache behind.
+// The umask still applies, and since the file is replaced an existing
+// cache with wider permissions is restricted as well.
+func (c *FileCache) writeFile(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return err
+	}
+	// A leftover of a killed wikr could carry other permissions
+	tmpPath := fmt.Sprintf("%s.%d.tmp", c.path, os.Getpid())
+	os.Remove(tmpPath)
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, c.mode)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, c.path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+	}
+	return err
+}
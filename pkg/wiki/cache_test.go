@@ -0,0 +1,414 @@
+package wiki
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCache(t *testing.T) *FileCache {
+	return NewFileCache(filepath.Join(t.TempDir(), CacheFileName))
+}
+
+func TestDefaultCachePath(t *testing.T) {
+	path := DefaultCachePath()
+	if path == "" {
+		t.Error("DefaultCachePath sollte einen nicht-leeren Pfad zurückgeben")
+	}
+}
+
+func TestLoadAndSaveCache(t *testing.T) {
+	fileCache := newTestCache(t)
+
+	// Erstelle einen Test-Cache
+	testCache := Cache{
+		"de:Test": CacheEntry{
+			Summary:   "Dies ist ein Test",
+			URL:       "https://de.wikipedia.org/wiki/Test",
+			Timestamp: time.Now(),
+		},
+	}
+
+	// Speichere den Test-Cache
+	fileCache.Save(testCache)
+
+	// Lade den Cache
+	loadedCache := fileCache.Load()
+
+	// Überprüfe, ob der geladene Cache den Test-Eintrag enthält
+	entry, exists := loadedCache["de:Test"]
+	if !exists {
+		t.Error("Der geladene Cache sollte den Test-Eintrag enthalten")
+	}
+
+	if entry.Summary != "Dies ist ein Test" {
+		t.Errorf("Erwartete Zusammenfassung 'Dies ist ein Test', erhielt '%s'", entry.Summary)
+	}
+}
+
+func TestGetAndSetCachedEntry(t *testing.T) {
+	fileCache := newTestCache(t)
+
+	// Setze einen Test-Eintrag
+	fileCache.Set("de", "TestArtikel", CacheEntry{
+		Summary: "Dies ist ein Test-Artikel",
+		URL:     "https://de.wikipedia.org/wiki/TestArtikel",
+	})
+
+	// Hole den Test-Eintrag
+	entry, found := fileCache.Get("de", "TestArtikel")
+
+	if !found {
+		t.Error("Der Test-Eintrag sollte im Cache gefunden werden")
+	}
+
+	if entry.Summary != "Dies ist ein Test-Artikel" {
+		t.Errorf("Erwartete Zusammenfassung 'Dies ist ein Test-Artikel', erhielt '%s'", entry.Summary)
+	}
+
+	if entry.URL != "https://de.wikipedia.org/wiki/TestArtikel" {
+		t.Errorf("Erwartete URL 'https://de.wikipedia.org/wiki/TestArtikel', erhielt '%s'", entry.URL)
+	}
+}
+
+func TestLoadCachePrunesExpiredEntries(t *testing.T) {
+	fileCache := newTestCache(t)
+
+	fileCache.Save(Cache{
+		"de:Alt": CacheEntry{
+			Summary:   "Veralteter Eintrag",
+			Timestamp: time.Now().Add(-CacheDuration - time.Hour),
+		},
+		"de:Neu": CacheEntry{
+			Summary:   "Aktueller Eintrag",
+			Timestamp: time.Now(),
+		},
+	})
+
+	cache := fileCache.Load()
+	if _, exists := cache["de:Alt"]; exists {
+		t.Error("Der abgelaufene Eintrag sollte entfernt werden")
+	}
+	if _, exists := cache["de:Neu"]; !exists {
+		t.Error("Der aktuelle Eintrag sollte erhalten bleiben")
+	}
+
+	// Die Cache-Datei sollte neu geschrieben worden sein
+	data, err := os.ReadFile(fileCache.Path())
+	if err != nil {
+		t.Fatalf("Die Cache-Datei sollte lesbar sein: %v", err)
+	}
+	if strings.Contains(string(data), "Veralteter Eintrag") {
+		t.Error("Die Cache-Datei sollte den abgelaufenen Eintrag nicht mehr enthalten")
+	}
+}
+
+func TestCachedEntryIsNotUnescapedTwice(t *testing.T) {
+	fileCache := newTestCache(t)
+
+	// "&amp;amp;" wird beim Parsen zu "&amp;" und darf danach nicht erneut dekodiert werden
+	entry, err := parseSummary([]byte(`{
+		"extract": "HTML schreibt &amp;amp; für ein kaufmännisches Und.",
+		"content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/HTML"}}
+	}`))
+	if err != nil {
+		t.Fatalf("parseSummary sollte keinen Fehler zurückgeben: %v", err)
+	}
+	fileCache.Set("de", "HTML", entry)
+
+	cached, found := fileCache.Get("de", "HTML")
+	if !found {
+		t.Fatal("Der Eintrag sollte im Cache gefunden werden")
+	}
+	if cached.Summary != "HTML schreibt &amp; für ein kaufmännisches Und." {
+		t.Errorf("Der Cache-Eintrag wurde doppelt dekodiert: '%s'", cached.Summary)
+	}
+}
+
+func TestNegativeCacheExpiresSooner(t *testing.T) {
+	fileCache := newTestCache(t)
+
+	age := NotFoundCacheDuration + time.Minute
+	fileCache.Save(Cache{
+		"de:Gibtesnicht": CacheEntry{NotFound: true, Timestamp: time.Now().Add(-age)},
+		"de:Berlin":      CacheEntry{Summary: "Berlin", Timestamp: time.Now().Add(-age)},
+	})
+
+	if _, found := fileCache.Get("de", "Gibtesnicht"); found {
+		t.Error("Der negative Eintrag sollte bereits abgelaufen sein")
+	}
+	if _, found := fileCache.Get("de", "Berlin"); !found {
+		t.Error("Der positive Eintrag gleichen Alters sollte noch gültig sein")
+	}
+}
+
+func TestCasingVariantsShareCacheEntry(t *testing.T) {
+	fileCache := newTestCache(t)
+
+	fileCache.Set("de", "Berlin", CacheEntry{Summary: "Berlin ist die Hauptstadt Deutschlands."})
+
+	entry, found := fileCache.Get("de", "berlin")
+	if !found {
+		t.Fatal("'berlin' sollte denselben Cache-Eintrag wie 'Berlin' treffen")
+	}
+	if entry.Summary != "Berlin ist die Hauptstadt Deutschlands." {
+		t.Errorf("Unerwartete Zusammenfassung: '%s'", entry.Summary)
+	}
+
+	if key := CacheKey("en", " albert Einstein"); key != "en:Albert_Einstein" {
+		t.Errorf("Erwarteter Schlüssel 'en:Albert_Einstein', erhielt '%s'", key)
+	}
+}
+
+func TestClearCache(t *testing.T) {
+	fileCache := newTestCache(t)
+	fileCache.Set("de", "Berlin", CacheEntry{Summary: "Berlin"})
+
+	if err := fileCache.Clear(); err != nil {
+		t.Fatalf("Clear sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if _, err := os.Stat(fileCache.Path()); !os.IsNotExist(err) {
+		t.Error("Die Cache-Datei sollte gelöscht sein")
+	}
+
+	// Ein fehlender Cache ist kein Fehler
+	if err := fileCache.Clear(); err != nil {
+		t.Errorf("Clear sollte für einen fehlenden Cache keinen Fehler zurückgeben: %v", err)
+	}
+}
+
+func TestClearExpired(t *testing.T) {
+	fileCache := newTestCache(t)
+	expired := time.Now().Add(-CacheDuration - time.Hour)
+	fileCache.Save(Cache{
+		"de:Alt":          CacheEntry{Summary: "Veralteter Eintrag", Timestamp: expired},
+		"de:Mit ETag":     CacheEntry{Summary: "Abgelaufen mit ETag", ETag: `"1"`, Timestamp: expired},
+		"de:Gibtesnicht":  CacheEntry{NotFound: true, Timestamp: time.Now().Add(-2 * NotFoundCacheDuration)},
+		"de:Neu":          CacheEntry{Summary: "Aktueller Eintrag", Timestamp: time.Now()},
+		"de:Festgehalten": CacheEntry{Summary: "Langer TTL", CustomTTL: 7 * 24 * time.Hour, Timestamp: expired},
+	})
+
+	if removed := fileCache.ClearExpired(); removed != 3 {
+		t.Errorf("Erwartete 3 entfernte Einträge, erhielt %d", removed)
+	}
+	cache := fileCache.Load()
+	if len(cache) != 2 {
+		t.Errorf("Nur die aktuellen Einträge sollten bleiben: %v", cache)
+	}
+	for _, key := range []string{"de:Neu", "de:Festgehalten"} {
+		if _, exists := cache[key]; !exists {
+			t.Errorf("%s sollte erhalten bleiben", key)
+		}
+	}
+
+	if removed := fileCache.ClearExpired(); removed != 0 {
+		t.Errorf("Ein zweiter Aufruf sollte nichts entfernen, erhielt %d", removed)
+	}
+}
+
+func TestCorruptCacheIsBackedUp(t *testing.T) {
+	fileCache := newTestCache(t)
+	var logOutput bytes.Buffer
+	fileCache.SetLogger(slog.New(slog.NewTextHandler(&logOutput, nil)))
+
+	garbage := []byte("{kaputt")
+	if err := os.WriteFile(fileCache.Path(), garbage, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if cache := fileCache.Load(); len(cache) != 0 {
+		t.Errorf("Ein kaputter Cache sollte leer geladen werden, erhielt %v", cache)
+	}
+
+	backup, err := os.ReadFile(fileCache.Path() + ".bak")
+	if err != nil {
+		t.Fatalf("Die Sicherung sollte angelegt werden: %v", err)
+	}
+	if !bytes.Equal(backup, garbage) {
+		t.Errorf("Die Sicherung sollte den alten Inhalt enthalten, erhielt %q", backup)
+	}
+	if !strings.Contains(logOutput.String(), "level=WARN") {
+		t.Errorf("Es sollte eine Warnung ausgegeben werden: %q", logOutput.String())
+	}
+
+	// Danach wird mit einem frischen Cache weitergearbeitet
+	fileCache.Set("de", "Berlin", CacheEntry{Summary: "Berlin"})
+	if _, found := fileCache.Get("de", "Berlin"); !found {
+		t.Error("Nach der Sicherung sollte der Cache wieder beschreibbar sein")
+	}
+}
+
+func TestCustomTTLOutlivesGlobalExpiry(t *testing.T) {
+	fileCache := newTestCache(t)
+
+	fileCache.Save(Cache{
+		"de:Berlin": CacheEntry{
+			Summary:   "Angehefteter Eintrag",
+			CustomTTL: 7 * 24 * time.Hour,
+			Timestamp: time.Now().Add(-CacheDuration - time.Hour),
+		},
+		"de:Paris": CacheEntry{
+			Summary:   "Normaler Eintrag",
+			Timestamp: time.Now().Add(-CacheDuration - time.Hour),
+		},
+	})
+
+	if _, found := fileCache.Get("de", "Berlin"); !found {
+		t.Error("Ein Eintrag mit längerer TTL sollte den globalen Ablauf überstehen")
+	}
+	if _, found := fileCache.Get("de", "Paris"); found {
+		t.Error("Ein Eintrag ohne eigene TTL sollte nach CacheDuration ablaufen")
+	}
+}
+
+func TestCustomTTLDoesNotApplyToNotFound(t *testing.T) {
+	entry := CacheEntry{NotFound: true, CustomTTL: 7 * 24 * time.Hour}
+	if entry.TTL() != NotFoundCacheDuration {
+		t.Errorf("Negative Einträge sollten ihre kurze TTL behalten, erhielt %v", entry.TTL())
+	}
+}
+
+func TestSaveLeavesNoTemporaryFile(t *testing.T) {
+	fileCache := newTestCache(t)
+	fileCache.Set("de", "Berlin", CacheEntry{Summary: "Berlin"})
+	fileCache.Set("de", "Paris", CacheEntry{Summary: "Paris"})
+
+	entries, err := os.ReadDir(filepath.Dir(fileCache.Path()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != filepath.Base(fileCache.Path()) {
+		names := make([]string, len(entries))
+		for i, entry := range entries {
+			names[i] = entry.Name()
+		}
+		t.Errorf("Neben dem Cache sollten keine temporären Dateien liegen: %v", names)
+	}
+}
+
+func TestUnwritableCacheIsKeptInMemory(t *testing.T) {
+	// Unter einer Datei kann kein Verzeichnis angelegt werden, auch nicht als root
+	blocker := filepath.Join(t.TempDir(), "datei")
+	if err := os.WriteFile(blocker, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(blocker, "wikr", "cache.json")
+	defer NewFileCache(path).Clear()
+
+	NewFileCache(path).Set("de", "Berlin", CacheEntry{Summary: "Berlin ist die Hauptstadt."})
+
+	entry, found := NewFileCache(path).Get("de", "Berlin")
+	if !found || entry.Summary != "Berlin ist die Hauptstadt." {
+		t.Errorf("Der Eintrag sollte im Speicher gehalten werden, erhielt %+v, %v", entry, found)
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("Es sollte keine Cache-Datei geben")
+	}
+}
+
+func TestReadOnlyCacheDoesNotWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	NewFileCache(path).Set("de", "Berlin", CacheEntry{Summary: "Berlin"})
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewFileCache(path)
+	cache.SetReadOnly(true)
+	if _, found := cache.Get("de", "Berlin"); !found {
+		t.Error("Vorhandene Einträge sollten gelesen werden")
+	}
+	cache.Set("de", "Paris", CacheEntry{Summary: "Paris"})
+
+	after, _ := os.ReadFile(path)
+	if !bytes.Equal(before, after) {
+		t.Error("Die Cache-Datei sollte unverändert bleiben")
+	}
+	if _, found := NewFileCache(path).Get("de", "Paris"); found {
+		t.Error("Der neue Eintrag sollte nicht gespeichert werden")
+	}
+
+	// Ohne Datei wird auch keine leere angelegt
+	missing := filepath.Join(t.TempDir(), "cache.json")
+	readOnly := NewFileCache(missing)
+	readOnly.SetReadOnly(true)
+	readOnly.Get("de", "Berlin")
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Error("Es sollte keine Cache-Datei angelegt werden")
+	}
+}
+
+func TestPinnedEntriesSurvive(t *testing.T) {
+	fileCache := newTestCache(t)
+	expired := time.Now().Add(-RevalidateDuration - time.Hour)
+	fileCache.Save(Cache{
+		"de:Berlin": CacheEntry{Summary: "Festgepinnt", Pinned: true, Timestamp: expired},
+		"de:Paris":  CacheEntry{Summary: "Abgelaufen", Timestamp: expired},
+	})
+
+	if _, found := fileCache.Get("de", "Berlin"); !found {
+		t.Error("Ein angepinnter Eintrag sollte nicht ablaufen")
+	}
+	cache := fileCache.Load()
+	if _, exists := cache["de:Paris"]; exists {
+		t.Error("Der abgelaufene Eintrag sollte entfernt werden")
+	}
+	if removed := fileCache.ClearExpired(); removed != 0 {
+		t.Errorf("-clear-expired sollte angepinnte Einträge behalten, entfernte %d", removed)
+	}
+
+	fileCache.SetKey("de:Berlin", CacheEntry{Summary: "Neu geladen"})
+	if entry, _ := fileCache.Get("de", "Berlin"); !entry.Pinned {
+		t.Error("Ein neu geladener Artikel sollte angepinnt bleiben")
+	}
+
+	if !fileCache.SetPinned("de:Berlin", false) {
+		t.Fatal("SetPinned sollte den Eintrag finden")
+	}
+	if entry, _ := fileCache.GetStale("de:Berlin"); entry.Pinned {
+		t.Error("Der Eintrag sollte nicht mehr angepinnt sein")
+	}
+	if fileCache.SetPinned("de:Gibtesnicht", true) {
+		t.Error("SetPinned sollte für fehlende Einträge false liefern")
+	}
+}
+
+func TestCanonicalTitleNFC(t *testing.T) {
+	tests := map[string]string{
+		"Mu\u0308nchen":             "München",
+		"E\u0301cole normale":       "École_normale",
+		"Franc\u0327ois Mitterrand": "François_Mitterrand",
+		"Vie\u0323\u0302t Nam":      "Việt_Nam",
+		// The marks are reordered before they are composed
+		"Vie\u0302\u0323t Nam":                 "Việt_Nam",
+		"\u1112\u1161\u11ab\u1100\u1173\u11af": "한글",
+		"Berlin":                               "Berlin",
+		// A mark without a precomposed letter stays as it is
+		"q\u0301": "Q\u0301",
+	}
+	for input, want := range tests {
+		if got := CanonicalTitle(input); got != want {
+			t.Errorf("CanonicalTitle(%q) = %q, erwartet %q", input, got, want)
+		}
+	}
+}
+
+func TestNFDAndNFCTitlesShareCacheEntry(t *testing.T) {
+	fileCache := newTestCache(t)
+	fileCache.Set("de", "Mu\u0308nchen", CacheEntry{Summary: "München ist die Hauptstadt Bayerns."})
+
+	if _, found := fileCache.Get("de", "München"); !found {
+		t.Error("Die NFC-Schreibweise sollte den Eintrag der NFD-Schreibweise treffen")
+	}
+	if nfd, nfc := CacheKey("fr", "e\u0301cole"), CacheKey("fr", "école"); nfd != nfc || nfc != "fr:École" {
+		t.Errorf("Erwartete denselben Schlüssel fr:École, erhielt %q und %q", nfd, nfc)
+	}
+	if cache := fileCache.Load(); len(cache) != 1 {
+		t.Errorf("Erwartete genau einen Eintrag, erhielt %v", cache)
+	}
+}
@@ -0,0 +1,44 @@
+package wiki
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// gzipMagic starts every gzip stream. Cache files are recognized by it, so
+// compressed and plain files can be read whatever SetCompression says.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// SetCompression makes the cache write its file gzip compressed. Reading
+// detects compressed files on its own, so switching back and forth keeps
+// the entries.
+func (c *FileCache) SetCompression(compress bool) {
+	c.compress = compress
+}
+
+// decompressCache returns the JSON of a cache file, decompressing it if it
+// is gzip compressed.
+func decompressCache(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func compressCache(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
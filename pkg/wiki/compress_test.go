@@ -0,0 +1,52 @@
+package wiki
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompressedCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	cache := NewFileCache(path)
+	cache.SetCompression(true)
+	cache.Set("de", "Berlin", CacheEntry{Summary: "Berlin ist die Hauptstadt.", URL: "https://de.wikipedia.org/wiki/Berlin"})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, gzipMagic) {
+		t.Fatalf("Die Cache-Datei sollte gzip-komprimiert sein: %q", data[:min(len(data), 16)])
+	}
+
+	// Komprimierte Dateien werden auch ohne SetCompression gelesen
+	entry, found := NewFileCache(path).Get("de", "Berlin")
+	if !found || entry.Summary != "Berlin ist die Hauptstadt." {
+		t.Errorf("Der Eintrag sollte gelesen werden, erhielt %+v, %v", entry, found)
+	}
+}
+
+func TestUncompressedCacheStaysReadable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	NewFileCache(path).Set("de", "Berlin", CacheEntry{Summary: "Berlin"})
+
+	// Eine unkomprimierte Datei wird beim nächsten Schreiben komprimiert
+	cache := NewFileCache(path)
+	cache.SetCompression(true)
+	if _, found := cache.Get("de", "Berlin"); !found {
+		t.Fatal("Der unkomprimierte Eintrag sollte gelesen werden")
+	}
+	cache.Set("de", "Paris", CacheEntry{Summary: "Paris"})
+
+	data, _ := os.ReadFile(path)
+	if !bytes.HasPrefix(data, gzipMagic) {
+		t.Error("Die Cache-Datei sollte nach dem Schreiben komprimiert sein")
+	}
+	for _, title := range []string{"Berlin", "Paris"} {
+		if _, found := cache.Get("de", title); !found {
+			t.Errorf("%s sollte im Cache sein", title)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// maxSearchPageSize is the most results the search API returns per
+// request.
+const maxSearchPageSize = 500
+
+// WithSearchLimit makes Search follow the continuation of the search API
+// until it has collected limit titles or there are no more. Without it
+// Search returns the first page only, 10 titles.
+func WithSearchLimit(limit int) Option {
+	return func(c *Client) {
+		c.searchLimit = limit
+	}
+}
+
+// searchPageURL returns the URL of the search results starting at offset,
+// with at most size results.
+func (c *Client) searchPageURL(term string, offset, size int) string {
+	requestURL := c.SearchURL(term) + "&srlimit=" + strconv.Itoa(min(size, maxSearchPageSize))
+	if offset > 0 {
+		requestURL += "&sroffset=" + strconv.Itoa(offset)
+	}
+	return requestURL
+}
+
+// continueSearch requests the pages after first until the search limit is
+// reached. A continuation that does not move forward or an empty page ends
+// it, so a misbehaving server cannot keep it looping. If Wikipedia starts
+// rate limiting, the titles collected so far are returned.
+func (c *Client) continueSearch(ctx context.Context, term string, first searchPage) (SearchResult, error) {
+	result := SearchResult{Titles: first.titles, Suggestion: first.suggestion}
+	offset, page := 0, first
+	for len(result.Titles) < c.searchLimit && page.next > offset && len(page.titles) > 0 {
+		offset = page.next
+		requestURL := c.searchPageURL(term, offset, c.searchLimit-len(result.Titles))
+		var err error
+		page, err = c.searchPage(ctx, term, requestURL, term+"-"+strconv.Itoa(offset))
+		if errors.Is(err, ErrRateLimited) {
+			c.log.Info("stopped following search continuation", "term", term, "titles", len(result.Titles), "error", err)
+			break
+		}
+		if err != nil {
+			return SearchResult{}, err
+		}
+		result.Titles = append(result.Titles, page.titles...)
+	}
+	if len(result.Titles) > c.searchLimit {
+		result.Titles = result.Titles[:c.searchLimit]
+	}
+	return result, nil
+}
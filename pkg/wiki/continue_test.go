@@ -0,0 +1,115 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// searchPagesServer serves two pages of two titles each, the first one
+// pointing to the second with a continuation.
+func searchPagesServer(t *testing.T, requests *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		switch r.URL.Query().Get("sroffset") {
+		case "":
+			fmt.Fprint(w, `{"continue": {"sroffset": 2, "continue": "-||"}, "query": {"search": [{"title": "Berlin"}, {"title": "Berlin-Mitte"}]}}`)
+		case "2":
+			fmt.Fprint(w, `{"query": {"search": [{"title": "Berliner Mauer"}, {"title": "Berlin Hauptbahnhof"}]}}`)
+		default:
+			t.Errorf("Unerwarteter Offset: %s", r.URL)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSearchFollowsContinuation(t *testing.T) {
+	var requests int
+	server := searchPagesServer(t, &requests)
+
+	titles, err := NewClient("de", WithHost(server.URL+"/%s"), WithSearchLimit(50)).Search(context.Background(), "Berlin")
+	if err != nil {
+		t.Fatalf("Search sollte keinen Fehler zurückgeben: %v", err)
+	}
+	want := []string{"Berlin", "Berlin-Mitte", "Berliner Mauer", "Berlin Hauptbahnhof"}
+	if !slices.Equal(titles, want) {
+		t.Errorf("Erwartete %v, erhielt %v", want, titles)
+	}
+	if requests != 2 {
+		t.Errorf("Erwartete 2 Anfragen, erhielt %d", requests)
+	}
+}
+
+func TestSearchStopsAtLimit(t *testing.T) {
+	var requests int
+	server := searchPagesServer(t, &requests)
+
+	titles, err := NewClient("de", WithHost(server.URL+"/%s"), WithSearchLimit(3)).Search(context.Background(), "Berlin")
+	if err != nil {
+		t.Fatalf("Search sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if len(titles) != 3 {
+		t.Errorf("Erwartete 3 Titel, erhielt %v", titles)
+	}
+
+	requests = 0
+	titles, err = NewClient("de", WithHost(server.URL+"/%s")).Search(context.Background(), "Berlin")
+	if err != nil {
+		t.Fatalf("Search sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if len(titles) != 2 || requests != 1 {
+		t.Errorf("Ohne Limit sollte nur die erste Seite geladen werden: %v, %d Anfragen", titles, requests)
+	}
+}
+
+func TestSearchContinuationWithoutProgress(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// Always points back to the same page
+		fmt.Fprint(w, `{"continue": {"sroffset": 2}, "query": {"search": [{"title": "Berlin"}, {"title": "Berlin-Mitte"}]}}`)
+	}))
+	defer server.Close()
+
+	titles, err := NewClient("de", WithHost(server.URL+"/%s"), WithSearchLimit(50)).Search(context.Background(), "Berlin")
+	if err != nil {
+		t.Fatalf("Search sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if requests != 2 || len(titles) != 4 {
+		t.Errorf("Die Schleife sollte nach der zweiten Seite enden: %d Anfragen, %v", requests, titles)
+	}
+}
+
+func TestSearchContinuationRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sroffset") != "" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"continue": {"sroffset": 2}, "query": {"search": [{"title": "Berlin"}, {"title": "Berlin-Mitte"}]}}`)
+	}))
+	defer server.Close()
+
+	titles, err := NewClient("de", WithHost(server.URL+"/%s"), WithSearchLimit(50)).Search(context.Background(), "Berlin")
+	if err != nil {
+		t.Fatalf("Die bisherigen Titel sollten ohne Fehler zurückgegeben werden: %v", err)
+	}
+	if len(titles) != 2 {
+		t.Errorf("Erwartete die 2 Titel der ersten Seite, erhielt %v", titles)
+	}
+
+	// On the first page the error is not hidden
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	_, err = NewClient("de", WithHost(server.URL+"/%s"), WithSearchLimit(50)).Search(context.Background(), "Berlin")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Erwartete ErrRateLimited, erhielt %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package wiki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// CountURL returns the URL that Count requests for the term. Only the
+// total is needed, so a single result without snippets is requested.
+func (c *Client) CountURL(term string) string {
+	return c.SearchURL(term) + "&srlimit=1&srprop=&srinfo=totalhits"
+}
+
+// Count returns the total number of articles matching term, not just the
+// ones a search returns.
+func (c *Client) Count(ctx context.Context, term string) (int, error) {
+	body, _, err := c.get(ctx, c.CountURL(term))
+	if err != nil {
+		return 0, err
+	}
+	return parseTotalHits(body)
+}
+
+func parseTotalHits(body []byte) (int, error) {
+	var result struct {
+		Query *struct {
+			SearchInfo *struct {
+				TotalHits int `json:"totalhits"`
+			} `json:"searchinfo"`
+		} `json:"query"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, err
+	}
+	if result.Query == nil || result.Query.SearchInfo == nil {
+		return 0, fmt.Errorf("unexpected search response without totalhits")
+	}
+	return result.Query.SearchInfo.TotalHits, nil
+}
@@ -0,0 +1,35 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("srinfo") != "totalhits" {
+			t.Errorf("Die Anfrage sollte die Gesamtzahl anfordern: %s", r.URL)
+		}
+		fmt.Fprint(w, `{"query": {"searchinfo": {"totalhits": 48213}, "search": [{"title": "Berlin"}]}}`)
+	}))
+	defer server.Close()
+
+	count, err := NewClient("de", WithHost(server.URL+"/%s")).Count(context.Background(), "Berlin")
+	if err != nil {
+		t.Fatalf("Count sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if count != 48213 {
+		t.Errorf("Erwartete 48213 Treffer, erhielt %d", count)
+	}
+}
+
+func TestParseTotalHitsInvalid(t *testing.T) {
+	for _, body := range []string{`{kaputt`, `{"batchcomplete": ""}`} {
+		if _, err := parseTotalHits([]byte(body)); err == nil {
+			t.Errorf("%s sollte einen Fehler liefern", body)
+		}
+	}
+}
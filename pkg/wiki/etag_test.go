@@ -0,0 +1,77 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSummaryRevalidatesWithETag(t *testing.T) {
+	var conditional []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"title": "Berlin", "extract": "Berlin ist die Hauptstadt.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+	}))
+	defer server.Close()
+
+	fileCache := newTestCache(t)
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithCache(fileCache))
+
+	entry, _, err := client.Summary(context.Background(), "Berlin")
+	if err != nil {
+		t.Fatalf("Summary sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if entry.ETag != `"v1"` {
+		t.Errorf("Das ETag sollte gespeichert werden, erhielt '%s'", entry.ETag)
+	}
+
+	// Den Eintrag künstlich ablaufen lassen
+	cache := fileCache.Load()
+	expired := cache["de:Berlin"]
+	expired.Timestamp = time.Now().Add(-CacheDuration - time.Hour)
+	cache["de:Berlin"] = expired
+	fileCache.Save(cache)
+
+	entry, cached, err := client.Summary(context.Background(), "Berlin")
+	if err != nil {
+		t.Fatalf("Die Revalidierung sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if !cached {
+		t.Error("Nach einem 304 sollte der Eintrag als gecacht gelten")
+	}
+	if entry.Summary != "Berlin ist die Hauptstadt." {
+		t.Errorf("Der gecachte Inhalt sollte verwendet werden, erhielt '%s'", entry.Summary)
+	}
+	if len(conditional) != 2 || conditional[0] != "" || conditional[1] != `"v1"` {
+		t.Errorf("Erwartete eine bedingte zweite Anfrage, erhielt %q", conditional)
+	}
+
+	// Der Zeitstempel wurde erneuert, der Eintrag ist wieder frisch
+	if fresh, found := fileCache.Get("de", "Berlin"); !found || fresh.ETag != `"v1"` {
+		t.Error("Der Eintrag sollte nach dem 304 wieder frisch im Cache liegen")
+	}
+}
+
+func TestPruneKeepsRevalidatableEntries(t *testing.T) {
+	old := time.Now().Add(-CacheDuration - time.Hour)
+	cache := Cache{
+		"de:MitETag":  CacheEntry{ETag: `"x"`, Timestamp: old},
+		"de:OhneETag": CacheEntry{Timestamp: old},
+		"de:Uralt":    CacheEntry{ETag: `"y"`, Timestamp: time.Now().Add(-RevalidateDuration - time.Hour)},
+	}
+
+	if removed := cache.Prune(); removed != 2 {
+		t.Errorf("Erwartete 2 entfernte Einträge, erhielt %d", removed)
+	}
+	if _, exists := cache["de:MitETag"]; !exists {
+		t.Error("Ein abgelaufener Eintrag mit ETag sollte für die Revalidierung erhalten bleiben")
+	}
+}
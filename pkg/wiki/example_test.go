@@ -0,0 +1,39 @@
+package wiki_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func ExampleClient_Search() {
+	client := wiki.NewClient("en")
+	titles, err := client.Search(context.Background(), "Golang")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, title := range titles {
+		fmt.Println(title)
+	}
+}
+
+func ExampleClient_Summary() {
+	client := wiki.NewClient("de", wiki.WithCache(wiki.NewFileCache(wiki.DefaultCachePath())))
+	entry, cached, err := client.Summary(context.Background(), "Berlin")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(entry.Summary)
+	fmt.Println(entry.URL, cached)
+}
+
+func ExampleClient_Section() {
+	client := wiki.NewClient("de")
+	entry, _, err := client.Section(context.Background(), "Berlin", "Geschichte")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(entry.Summary)
+}
@@ -0,0 +1,76 @@
+package wiki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// LangLink is a version of an article in another language.
+type LangLink struct {
+	Lang  string `json:"lang"`
+	Title string `json:"title"`
+}
+
+// LangLinksKey is the cache key of the language versions of an article.
+func LangLinksKey(lang, title string) string {
+	return "langlinks:" + CacheKey(lang, title)
+}
+
+// LangLinksURL returns the URL that LangLinks requests for the title.
+func (c *Client) LangLinksURL(title string) string {
+	return c.baseURL() + "/w/api.php?action=query&prop=langlinks&titles=" + url.QueryEscape(title) + "&lllimit=max&redirects=1&format=json&formatversion=2"
+}
+
+// LangLinks returns the versions of the article in other languages, in
+// the order of the API. They are cached under LangLinksKey like a summary.
+func (c *Client) LangLinks(ctx context.Context, title string) ([]LangLink, bool, error) {
+	key := projectPrefix(c.project) + LangLinksKey(c.lang, title)
+	if c.cache != nil {
+		if entry, found := c.cache.GetKey(key); found {
+			return entry.LangLinks, true, nil
+		}
+	}
+
+	body, _, err := c.get(ctx, c.LangLinksURL(title))
+	if err != nil {
+		return nil, false, err
+	}
+	links, err := parseLangLinks(body, title)
+	if err != nil {
+		return nil, false, err
+	}
+	if c.cache != nil {
+		c.cache.SetKey(key, CacheEntry{Title: title, LangLinks: links})
+	}
+	return links, false, nil
+}
+
+// parseLangLinks decodes the language links of the page from a query API
+// response.
+func parseLangLinks(body []byte, title string) ([]LangLink, error) {
+	var result struct {
+		Query struct {
+			Pages []struct {
+				Title     string     `json:"title"`
+				Missing   bool       `json:"missing"`
+				Invalid   bool       `json:"invalid"`
+				LangLinks []LangLink `json:"langlinks"`
+			} `json:"pages"`
+		} `json:"query"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	for _, page := range result.Query.Pages {
+		if page.Missing || page.Invalid {
+			continue
+		}
+		if page.LangLinks == nil {
+			return []LangLink{}, nil
+		}
+		return page.LangLinks, nil
+	}
+	return nil, fmt.Errorf("%w for %q", ErrArticleNotFound, title)
+}
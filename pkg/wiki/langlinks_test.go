@@ -0,0 +1,67 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseLangLinks(t *testing.T) {
+	body := []byte(`{"batchcomplete": true, "query": {"pages": [{"pageid": 2013, "ns": 0, "title": "Berlin", "langlinks": [
+		{"lang": "en", "title": "Berlin"},
+		{"lang": "fr", "title": "Berlin"},
+		{"lang": "ja", "title": "ベルリン"}
+	]}]}}`)
+
+	links, err := parseLangLinks(body, "Berlin")
+	if err != nil {
+		t.Fatalf("parseLangLinks sollte keinen Fehler zurückgeben: %v", err)
+	}
+	want := []LangLink{{"en", "Berlin"}, {"fr", "Berlin"}, {"ja", "ベルリン"}}
+	if fmt.Sprint(links) != fmt.Sprint(want) {
+		t.Errorf("Erwartete %v, erhielt %v", want, links)
+	}
+
+	links, err = parseLangLinks([]byte(`{"query": {"pages": [{"pageid": 1, "title": "Nische"}]}}`), "Nische")
+	if err != nil || links == nil || len(links) != 0 {
+		t.Errorf("Ein Artikel ohne andere Sprachen sollte eine leere Liste liefern: %v, %v", links, err)
+	}
+
+	_, err = parseLangLinks([]byte(`{"query": {"pages": [{"title": "Gibtesnicht", "missing": true}]}}`), "Gibtesnicht")
+	if !errors.Is(err, ErrArticleNotFound) {
+		t.Errorf("Ein fehlender Artikel sollte ErrArticleNotFound liefern: %v", err)
+	}
+}
+
+func TestLangLinksAreCached(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("prop") != "langlinks" || r.URL.Query().Get("titles") != "Berlin" {
+			t.Errorf("Unerwartete Anfrage: %s", r.URL)
+		}
+		fmt.Fprint(w, `{"query": {"pages": [{"title": "Berlin", "langlinks": [{"lang": "en", "title": "Berlin"}]}]}}`)
+	}))
+	defer server.Close()
+
+	fileCache := newTestCache(t)
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithCache(fileCache))
+	for i := 0; i < 2; i++ {
+		links, cached, err := client.LangLinks(context.Background(), "Berlin")
+		if err != nil || len(links) != 1 || links[0].Lang != "en" {
+			t.Fatalf("Unerwartete Sprachversionen: %v, %v", links, err)
+		}
+		if cached != (i == 1) {
+			t.Errorf("Abruf %d: cached = %v", i+1, cached)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Der zweite Abruf sollte aus dem Cache kommen, %d Anfragen", requests)
+	}
+	if _, found := fileCache.GetKey("langlinks:de:Berlin"); !found {
+		t.Error("Die Sprachversionen sollten unter 'langlinks:de:Berlin' im Cache liegen")
+	}
+}
@@ -0,0 +1,39 @@
+//go:build live
+
+// The live tests talk to the real Wikipedia API. They are not part of the
+// normal test run, run them with: go test -tags live ./pkg/wiki
+
+package wiki
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestLiveSearch(t *testing.T) {
+	results, err := NewClient("de").Search(context.Background(), "Berlin")
+	if err != nil {
+		t.Fatalf("Search sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if !slices.Contains(results, "Berlin") {
+		t.Errorf("'Berlin' sollte in den Suchergebnissen enthalten sein, erhalten %v", results)
+	}
+}
+
+func TestLiveSummary(t *testing.T) {
+	client := NewClient("de", WithCache(newTestCache(t)))
+	entry, cached, err := client.Summary(context.Background(), "Berlin")
+	if err != nil {
+		t.Fatalf("Summary sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if entry.Summary == "" || entry.URL == "" {
+		t.Errorf("Zusammenfassung und URL sollten nicht leer sein: %+v", entry)
+	}
+	if cached {
+		t.Error("Der erste Aufruf sollte nicht aus dem Cache kommen")
+	}
+	if _, cached, _ = client.Summary(context.Background(), "Berlin"); !cached {
+		t.Error("Der zweite Aufruf sollte aus dem Cache kommen")
+	}
+}
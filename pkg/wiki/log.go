@@ -0,0 +1,18 @@
+package wiki
+
+import (
+	"io"
+	"log/slog"
+)
+
+// discardLogger is used until a logger is set, so the package is silent by
+// default.
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// WithLogger sets the logger for requests and cache access. Requests are
+// logged at info level, cache lookups at debug level.
+func WithLogger(log *slog.Logger) Option {
+	return func(c *Client) {
+		c.log = log
+	}
+}
@@ -0,0 +1,103 @@
+package wiki
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxCachedSize is the length of the text above which an entry is not
+// written to the cache, so a few huge sections do not bloat the file.
+const MaxCachedSize = 256 << 10
+
+// ErrTooLarge matches a TooLargeError with errors.Is.
+var ErrTooLarge = errors.New("response too large")
+
+// TooLargeError is returned when a response is longer than the limit set
+// with WithMaxBytes.
+type TooLargeError struct {
+	Limit int64
+}
+
+func (e *TooLargeError) Error() string {
+	return fmt.Sprintf("the response exceeds the limit of %d bytes", e.Limit)
+}
+
+func (e *TooLargeError) Is(target error) bool {
+	return target == ErrTooLarge
+}
+
+// WithMaxBytes stops reading a response after n bytes of the decompressed
+// body, 0 reads it all. Requests with a longer response fail with a
+// TooLargeError, except Article, which returns the part that was read.
+func WithMaxBytes(n int64) Option {
+	return func(c *Client) {
+		c.maxBytes = n
+	}
+}
+
+// readLimited reads r up to limit bytes, all of it if limit is 0. A longer
+// body is cut at the limit and returned with a TooLargeError.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+	body, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return body[:limit], &TooLargeError{Limit: limit}
+	}
+	return body, nil
+}
+
+// textFieldPattern finds the start of the text in a parse API response.
+var textFieldPattern = regexp.MustCompile(`"text"\s*:\s*"`)
+
+// truncatedParseText recovers the HTML of a parse API response that was
+// cut off. The JSON string of the text is decoded up to the last complete
+// character, a tag that was cut off is dropped.
+func truncatedParseText(body []byte) string {
+	start := textFieldPattern.FindIndex(body)
+	if start == nil {
+		return ""
+	}
+	rest := body[start[1]:]
+	end := 0
+scan:
+	for end < len(rest) {
+		switch rest[end] {
+		case '"':
+			break scan
+		case '\\':
+			size := 2
+			if end+1 < len(rest) && rest[end+1] == 'u' {
+				size = 6
+			}
+			if end+size > len(rest) {
+				break scan
+			}
+			end += size
+		default:
+			if !utf8.FullRune(rest[end:]) {
+				break scan
+			}
+			_, size := utf8.DecodeRune(rest[end:])
+			end += size
+		}
+	}
+	quoted := append(append([]byte{'"'}, rest[:end]...), '"')
+	var text string
+	if err := json.Unmarshal(quoted, &text); err != nil {
+		return ""
+	}
+	if open := strings.LastIndex(text, "<"); open > strings.LastIndex(text, ">") {
+		text = text[:open]
+	}
+	return text
+}
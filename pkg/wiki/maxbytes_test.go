@@ -0,0 +1,96 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// oversizedArticle is a parse API response with a lead section of about
+// 1 MB behind the first heading.
+func oversizedArticle() string {
+	return `{"parse": {"title": "Riesig", "text": "<p>Anfang des Artikels.</p><h2>Teil</h2><p>` +
+		strings.Repeat("Sehr langer Text über nichts. ", 40000) + `</p>"}}`
+}
+
+func TestReadLimited(t *testing.T) {
+	body, err := readLimited(strings.NewReader("0123456789"), 4)
+	var tooLarge *TooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != 4 || !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Erwartete TooLargeError mit Limit 4, erhielt %v", err)
+	}
+	if string(body) != "0123" {
+		t.Errorf("Der Anfang sollte erhalten bleiben, erhielt %q", body)
+	}
+
+	if body, err := readLimited(strings.NewReader("0123"), 4); err != nil || string(body) != "0123" {
+		t.Errorf("Genau am Limit sollte alles gelesen werden: %q, %v", body, err)
+	}
+	if body, err := readLimited(strings.NewReader("0123456789"), 0); err != nil || len(body) != 10 {
+		t.Errorf("Ohne Limit sollte alles gelesen werden: %q, %v", body, err)
+	}
+}
+
+func TestTruncatedParseText(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"parse": {"text": "<p>Hallo</p><p>Welt`, "<p>Hallo</p><p>Welt"},
+		{`{"parse": {"text": "<p>Hallo</p><a href=\"/wiki/X`, "<p>Hallo</p>"},
+		{`{"parse": {"text": "<p>Grü`, "<p>Grü"},
+		{`{"parse": {"text": "<p>Gr\u00f`, "<p>Gr"},
+		{`{"parse": {"text": "<p>a\`, "<p>a"},
+		{"{\"parse\": {\"text\": \"<p>\xc3", "<p>"},
+		{`{"parse": {"text": "<p>fertig</p>"}}`, "<p>fertig</p>"},
+		{`{"parse": {"title": "Riesig"`, ""},
+	}
+	for _, tt := range tests {
+		if got := truncatedParseText([]byte(tt.body)); got != tt.want {
+			t.Errorf("truncatedParseText(%q) = %q, erwartet %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestArticleMaxBytes(t *testing.T) {
+	body := oversizedArticle()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	const limit = 64 << 10
+	sections, err := NewClient("de", WithHost(server.URL+"/%s"), WithMaxBytes(limit)).Article(context.Background(), "Riesig")
+	if !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("Erwartete ErrTooLarge, erhielt %v", err)
+	}
+	if len(sections) != 2 || sections[0].Text != "Anfang des Artikels." || sections[1].Title != "Teil" {
+		t.Fatalf("Der gelesene Teil sollte zurückkommen: %d Abschnitte", len(sections))
+	}
+	if size := len(sections[1].Text); size == 0 || size > limit {
+		t.Errorf("Der Text sollte am Limit abgeschnitten sein, %d Bytes", size)
+	}
+
+	sections, err = NewClient("de", WithHost(server.URL+"/%s")).Article(context.Background(), "Riesig")
+	if err != nil || len(sections[1].Text) < len(body)/2 {
+		t.Errorf("Ohne Limit sollte der ganze Artikel kommen: %v", err)
+	}
+}
+
+func TestStoreSkipsOversizedEntries(t *testing.T) {
+	cache := newTestCache(t)
+	client := NewClient("de", WithCache(cache))
+	client.store("Riesig", CacheEntry{Title: "Riesig", Summary: strings.Repeat("x", MaxCachedSize+1)})
+	client.store("Klein", CacheEntry{Title: "Klein", Summary: "klein"})
+
+	if _, found := cache.Get("de", "Riesig"); found {
+		t.Error("Ein zu großer Eintrag sollte nicht gecacht werden")
+	}
+	if _, found := cache.Get("de", "Klein"); !found {
+		t.Error("Ein kleiner Eintrag sollte gecacht werden")
+	}
+}
@@ -0,0 +1,89 @@
+package wiki
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CacheVersion is the schema version written to the cache file. Bump it
+// and add a migration when the file format changes.
+const CacheVersion = 1
+
+// cacheFile is the layout of the cache file since version 1.
+type cacheFile struct {
+	Version int   `json:"version"`
+	Entries Cache `json:"entries"`
+}
+
+// unknownVersionError reports a cache file written by a newer wikr.
+type unknownVersionError struct {
+	version int
+}
+
+func (e unknownVersionError) Error() string {
+	return fmt.Sprintf("unknown cache version %d, this wikr supports up to %d", e.version, CacheVersion)
+}
+
+// migrations upgrade the raw cache file of the given version to the next
+// version.
+var migrations = map[int]func(data []byte) ([]byte, error){
+	0: migrateV0,
+}
+
+// decodeCache reads a cache file of any known version and returns its
+// entries together with the version the file had. Fields added since the
+// file was written are left empty.
+func decodeCache(data []byte) (Cache, int, error) {
+	version, err := cacheFileVersion(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version > CacheVersion {
+		return nil, version, unknownVersionError{version}
+	}
+
+	original := version
+	for version < CacheVersion {
+		data, err = migrations[version](data)
+		if err != nil {
+			return nil, original, fmt.Errorf("migrating cache from version %d: %w", version, err)
+		}
+		version++
+	}
+
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, original, err
+	}
+	if file.Entries == nil {
+		file.Entries = make(Cache)
+	}
+	return file.Entries, original, nil
+}
+
+// cacheFileVersion returns the version of the cache file. Files without a
+// version field are version 0, a bare map of entries.
+func cacheFileVersion(data []byte) (int, error) {
+	var header map[string]json.RawMessage
+	if err := json.Unmarshal(data, &header); err != nil {
+		return 0, err
+	}
+	raw, ok := header["version"]
+	if !ok {
+		return 0, nil
+	}
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil {
+		return 0, fmt.Errorf("invalid cache version: %w", err)
+	}
+	return version, nil
+}
+
+// migrateV0 wraps the bare map of version 0 into the versioned layout.
+func migrateV0(data []byte) ([]byte, error) {
+	var entries Cache
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return json.Marshal(cacheFile{Version: 1, Entries: entries})
+}
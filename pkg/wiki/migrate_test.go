@@ -0,0 +1,87 @@
+package wiki
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadUnversionedCache(t *testing.T) {
+	fileCache := newTestCache(t)
+
+	// Ein Cache im alten Format: eine Map ohne Version und ohne neuere Felder
+	timestamp := time.Now().Format(time.RFC3339Nano)
+	v0 := `{"de:Berlin": {"summary": "Berlin ist die Hauptstadt.", "url": "https://de.wikipedia.org/wiki/Berlin", "timestamp": "` + timestamp + `"}}`
+	if err := os.WriteFile(fileCache.Path(), []byte(v0), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, found := fileCache.Get("de", "Berlin")
+	if !found {
+		t.Fatal("Der Eintrag aus dem alten Cache sollte gefunden werden")
+	}
+	if entry.Summary != "Berlin ist die Hauptstadt." {
+		t.Errorf("Unerwartete Zusammenfassung: '%s'", entry.Summary)
+	}
+	if entry.Description != "" || entry.Coordinates != nil || entry.NotFound {
+		t.Errorf("Neue Felder sollten leer sein: %+v", entry)
+	}
+
+	// Die Datei sollte im aktuellen Format neu geschrieben worden sein
+	data, err := os.ReadFile(fileCache.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"version":1`) || !strings.Contains(string(data), `"entries"`) {
+		t.Errorf("Die Cache-Datei sollte migriert worden sein: %s", data)
+	}
+}
+
+func TestLoadEmptyUnversionedCache(t *testing.T) {
+	fileCache := newTestCache(t)
+	if err := os.WriteFile(fileCache.Path(), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if cache := fileCache.Load(); len(cache) != 0 {
+		t.Errorf("Erwartete einen leeren Cache, erhielt %v", cache)
+	}
+
+	// Neue Einträge sollten danach normal gespeichert werden
+	fileCache.Set("de", "Paris", CacheEntry{Summary: "Paris"})
+	if _, found := fileCache.Get("de", "Paris"); !found {
+		t.Error("Der neue Eintrag sollte gefunden werden")
+	}
+}
+
+func TestLoadFutureCacheVersion(t *testing.T) {
+	fileCache := newTestCache(t)
+	future := `{"version": 99, "entries": {"de:Berlin": {"summary": "Berlin"}}, "shards": []}`
+	if err := os.WriteFile(fileCache.Path(), []byte(future), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Eine unbekannte Version wird ignoriert statt abzustürzen
+	if cache := fileCache.Load(); len(cache) != 0 {
+		t.Errorf("Eine unbekannte Version sollte ignoriert werden, erhielt %v", cache)
+	}
+	if _, found := fileCache.Get("de", "Berlin"); found {
+		t.Error("Einträge einer unbekannten Version sollten nicht verwendet werden")
+	}
+}
+
+func TestDecodeCacheCurrentVersion(t *testing.T) {
+	data := []byte(`{"version": 1, "entries": {"en:Go": {"summary": "Go", "description": "programming language"}}}`)
+
+	cache, version, err := decodeCache(data)
+	if err != nil {
+		t.Fatalf("decodeCache sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if version != CacheVersion {
+		t.Errorf("Erwartete Version %d, erhielt %d", CacheVersion, version)
+	}
+	if cache["en:Go"].Description != "programming language" {
+		t.Errorf("Unerwarteter Eintrag: %+v", cache["en:Go"])
+	}
+}
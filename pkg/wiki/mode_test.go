@@ -0,0 +1,61 @@
+//go:build unix
+
+package wiki
+
+import (
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestCacheFileMode(t *testing.T) {
+	oldUmask := syscall.Umask(0022)
+	defer syscall.Umask(oldUmask)
+
+	fileCache := newTestCache(t)
+	fileCache.Set("de", "Berlin", CacheEntry{Summary: "Berlin"})
+
+	info, err := os.Stat(fileCache.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("Erwartete Modus 0600, erhielt %o", perm)
+	}
+}
+
+func TestCacheFileModeConfigured(t *testing.T) {
+	oldUmask := syscall.Umask(0027)
+	defer syscall.Umask(oldUmask)
+
+	fileCache := newTestCache(t)
+	fileCache.SetMode(0664)
+	fileCache.Set("de", "Berlin", CacheEntry{Summary: "Berlin"})
+
+	// Die umask schränkt den konfigurierten Modus weiter ein
+	info, err := os.Stat(fileCache.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0640 {
+		t.Errorf("Erwartete Modus 0640, erhielt %o", perm)
+	}
+}
+
+func TestCacheFileModeRestrictsExistingFile(t *testing.T) {
+	fileCache := newTestCache(t)
+	if err := os.WriteFile(fileCache.Path(), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chmod(fileCache.Path(), 0644)
+
+	fileCache.Set("de", "Berlin", CacheEntry{Summary: "Berlin"})
+
+	info, err := os.Stat(fileCache.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("Eine bestehende Datei sollte auf 0600 eingeschränkt werden, erhielt %o", perm)
+	}
+}
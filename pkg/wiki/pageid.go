@@ -0,0 +1,77 @@
+package wiki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// PageIDKey is the cache key of a summary looked up by page ID.
+func PageIDKey(lang string, pageID int) string {
+	return "pageid:" + lang + ":" + strconv.Itoa(pageID)
+}
+
+// PageIDURL returns the URL that TitleForPageID requests for the page ID.
+func (c *Client) PageIDURL(pageID int) string {
+	return c.baseURL() + "/w/api.php?action=query&pageids=" + strconv.Itoa(pageID) + "&format=json&formatversion=2"
+}
+
+// TitleForPageID resolves a numeric page ID to the title of the article.
+func (c *Client) TitleForPageID(ctx context.Context, pageID int) (string, error) {
+	body, _, err := c.get(ctx, c.PageIDURL(pageID))
+	if err != nil {
+		return "", err
+	}
+	return parsePageTitle(body, pageID)
+}
+
+// SummaryByPageID returns the summary of the article with the page ID. The
+// entry is cached under PageIDKey so repeated lookups skip resolving the
+// title.
+func (c *Client) SummaryByPageID(ctx context.Context, pageID int) (CacheEntry, bool, error) {
+	key := projectPrefix(c.project) + PageIDKey(c.lang, pageID)
+	if c.cache != nil {
+		if entry, found := c.cache.GetKey(key); found {
+			return entry, true, nil
+		}
+	}
+
+	title, err := c.TitleForPageID(ctx, pageID)
+	if err != nil {
+		return CacheEntry{}, false, err
+	}
+	entry, cached, err := c.Summary(ctx, title)
+	if err != nil {
+		return CacheEntry{}, false, err
+	}
+	if entry.Title == "" {
+		entry.Title = title
+	}
+	c.storeKey(key, entry)
+	return entry, cached, nil
+}
+
+// parsePageTitle decodes the title of the page from a query API response.
+func parsePageTitle(body []byte, pageID int) (string, error) {
+	var result struct {
+		Query struct {
+			Pages []struct {
+				PageID  int    `json:"pageid"`
+				Title   string `json:"title"`
+				Missing bool   `json:"missing"`
+				Invalid bool   `json:"invalid"`
+			} `json:"pages"`
+		} `json:"query"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", err
+	}
+	for _, page := range result.Query.Pages {
+		if page.Missing || page.Invalid || page.Title == "" {
+			continue
+		}
+		return page.Title, nil
+	}
+	return "", fmt.Errorf("%w for page ID %d", ErrArticleNotFound, pageID)
+}
@@ -0,0 +1,86 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummaryByPageID(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("pageids") == "2013" {
+			fmt.Fprint(w, `{"batchcomplete": true, "query": {"pages": [{"pageid": 2013, "ns": 0, "title": "Berlin"}]}}`)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/summary/Berlin") {
+			fmt.Fprint(w, `{"title": "Berlin", "extract": "Berlin ist die Hauptstadt.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	fileCache := newTestCache(t)
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithCache(fileCache))
+
+	entry, cached, err := client.SummaryByPageID(context.Background(), 2013)
+	if err != nil {
+		t.Fatalf("SummaryByPageID sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if entry.Title != "Berlin" || cached {
+		t.Errorf("Erwartete den Artikel 'Berlin' aus dem Netz, erhielt '%s' (cached=%v)", entry.Title, cached)
+	}
+
+	// Der zweite Abruf kommt ohne Anfrage aus dem Cache
+	if _, found := fileCache.GetKey("pageid:de:2013"); !found {
+		t.Error("Der Eintrag sollte unter 'pageid:de:2013' im Cache liegen")
+	}
+	if _, cached, _ := client.SummaryByPageID(context.Background(), 2013); !cached || requests != 2 {
+		t.Errorf("Der zweite Abruf sollte aus dem Cache kommen, %d Anfragen", requests)
+	}
+}
+
+func TestSummaryByPageIDCachePolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"batchcomplete": true, "query": {"pages": [{"pageid": 2013, "ns": 0, "title": "Berlin"}]}}`)
+	}))
+	defer server.Close()
+
+	// Die Zusammenfassung liegt schon ohne Pin im Cache
+	fileCache := newTestCache(t)
+	fileCache.Set("de", "Berlin", CacheEntry{Title: "Berlin", Summary: "Berlin ist die Hauptstadt."})
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithCache(fileCache), WithPin(), WithTTL(48*time.Hour))
+
+	if _, _, err := client.SummaryByPageID(context.Background(), 2013); err != nil {
+		t.Fatalf("SummaryByPageID sollte keinen Fehler zurückgeben: %v", err)
+	}
+	entry, found := fileCache.GetKey("pageid:de:2013")
+	if !found || !entry.Pinned || entry.CustomTTL != 48*time.Hour {
+		t.Errorf("Pin und TTL sollten auch für die Seiten-ID gelten: %+v", entry)
+	}
+
+	largeCache := newTestCache(t)
+	largeCache.Set("de", "Berlin", CacheEntry{Title: "Berlin", Summary: strings.Repeat("x", MaxCachedSize+1)})
+	large := NewClient("de", WithHost(server.URL+"/%s"), WithCache(largeCache))
+	if _, _, err := large.SummaryByPageID(context.Background(), 2013); err != nil {
+		t.Fatalf("SummaryByPageID sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if _, found := largeCache.GetKey("pageid:de:2013"); found {
+		t.Error("Ein zu großer Eintrag sollte auch unter der Seiten-ID nicht gecacht werden")
+	}
+}
+
+func TestParsePageTitleMissing(t *testing.T) {
+	body := []byte(`{"batchcomplete": true, "query": {"pages": [{"pageid": 999999999, "missing": true}]}}`)
+
+	if _, err := parsePageTitle(body, 999999999); !errors.Is(err, ErrArticleNotFound) {
+		t.Errorf("Erwartete ErrArticleNotFound, erhielt %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package wiki
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// appDirName is the directory wikr uses inside the platform config and
+// cache directories.
+const appDirName = "wikr"
+
+type dirKind int
+
+const (
+	configDir dirKind = iota
+	cacheDir
+)
+
+// platformDir returns the base directory for config or cache files
+// following the conventions of goos: XDG on Linux and other Unix systems,
+// ~/Library on macOS and %APPDATA% or %LOCALAPPDATA% on Windows. It is
+// os.UserConfigDir and os.UserCacheDir with the OS and environment passed
+// in, so all platforms can be tested.
+func platformDir(goos string, kind dirKind, getenv func(string) string) (string, error) {
+	switch goos {
+	case "windows":
+		variable := "APPDATA"
+		if kind == cacheDir {
+			variable = "LOCALAPPDATA"
+		}
+		if dir := getenv(variable); dir != "" {
+			return dir, nil
+		}
+		return "", errors.New("%" + variable + "% is not defined")
+	case "darwin", "ios":
+		home := getenv("HOME")
+		if home == "" {
+			return "", errors.New("$HOME is not defined")
+		}
+		if kind == cacheDir {
+			return filepath.Join(home, "Library", "Caches"), nil
+		}
+		return filepath.Join(home, "Library", "Application Support"), nil
+	}
+
+	variable, fallback := "XDG_CONFIG_HOME", ".config"
+	if kind == cacheDir {
+		variable, fallback = "XDG_CACHE_HOME", ".cache"
+	}
+	// The XDG spec says to ignore relative paths
+	if dir := getenv(variable); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	home := getenv("HOME")
+	if home == "" {
+		return "", errors.New("neither $" + variable + " nor $HOME are defined")
+	}
+	return filepath.Join(home, fallback), nil
+}
+
+// appFilePath returns where wikr keeps the file name of the kind. A file
+// at the legacy location in the home directory, where wikr used to store
+// everything, is still used so existing caches and settings are not lost.
+// Without a platform directory the home directory is used, and without
+// that the temporary directory, see FallbackDir.
+func appFilePath(kind dirKind, name, legacyName string) string {
+	home, homeErr := os.UserHomeDir()
+	if homeErr == nil {
+		legacy := filepath.Join(home, legacyName)
+		if _, err := os.Stat(legacy); err == nil {
+			return legacy
+		}
+	}
+	if dir, err := platformDir(runtime.GOOS, kind, os.Getenv); err == nil {
+		return filepath.Join(dir, appDirName, name)
+	}
+	if homeErr == nil {
+		return filepath.Join(home, legacyName)
+	}
+	return filepath.Join(FallbackDir(), name)
+}
+
+// FallbackDir is where wikr keeps its files when there is no home
+// directory, e.g. in minimal containers. Unlike the current directory it
+// is writable almost everywhere, but it may be cleaned up at any time.
+func FallbackDir() string {
+	return filepath.Join(os.TempDir(), appDirName)
+}
+
+// ConfigFilePath returns the path of a config file of wikr, e.g.
+// ~/.config/wikr/config.json on Linux. legacyName is the file name wikr
+// used in the home directory before, which wins if it exists. The
+// directory may not exist yet.
+func ConfigFilePath(name, legacyName string) string {
+	return appFilePath(configDir, name, legacyName)
+}
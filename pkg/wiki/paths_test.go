@@ -0,0 +1,91 @@
+package wiki
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlatformDir(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	tests := []struct {
+		name string
+		goos string
+		kind dirKind
+		env  map[string]string
+		want string
+	}{
+		{"Linux Konfiguration", "linux", configDir, map[string]string{"HOME": "/home/anna"}, "/home/anna/.config"},
+		{"Linux Cache", "linux", cacheDir, map[string]string{"HOME": "/home/anna"}, "/home/anna/.cache"},
+		{"XDG Konfiguration", "linux", configDir, map[string]string{"HOME": "/home/anna", "XDG_CONFIG_HOME": "/xdg/config"}, "/xdg/config"},
+		{"XDG Cache", "freebsd", cacheDir, map[string]string{"HOME": "/home/anna", "XDG_CACHE_HOME": "/xdg/cache"}, "/xdg/cache"},
+		{"Relativer XDG-Pfad", "linux", cacheDir, map[string]string{"HOME": "/home/anna", "XDG_CACHE_HOME": "cache"}, "/home/anna/.cache"},
+		{"macOS Konfiguration", "darwin", configDir, map[string]string{"HOME": "/Users/anna"}, "/Users/anna/Library/Application Support"},
+		{"macOS Cache", "darwin", cacheDir, map[string]string{"HOME": "/Users/anna"}, "/Users/anna/Library/Caches"},
+		{"Windows Konfiguration", "windows", configDir, map[string]string{"APPDATA": `C:\Users\anna\AppData\Roaming`}, `C:\Users\anna\AppData\Roaming`},
+		{"Windows Cache", "windows", cacheDir, map[string]string{"LOCALAPPDATA": `C:\Users\anna\AppData\Local`}, `C:\Users\anna\AppData\Local`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := platformDir(tt.goos, tt.kind, env(tt.env))
+			if err != nil {
+				t.Fatalf("platformDir sollte keinen Fehler zurückgeben: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("platformDir = %q, erwartet %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, goos := range []string{"linux", "darwin", "windows"} {
+		if _, err := platformDir(goos, cacheDir, env(nil)); err == nil {
+			t.Errorf("%s: ohne Umgebungsvariablen sollte ein Fehler kommen", goos)
+		}
+	}
+}
+
+func TestAppFilePathPrefersLegacyFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "xdg-cache"))
+
+	if got, want := DefaultCachePath(), filepath.Join(home, "xdg-cache", "wikr", "cache.json"); got != want {
+		t.Errorf("DefaultCachePath = %q, erwartet %q", got, want)
+	}
+
+	// Ein vorhandener Cache im Home-Verzeichnis wird weiter verwendet
+	legacy := filepath.Join(home, CacheFileName)
+	if err := os.WriteFile(legacy, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := DefaultCachePath(); got != legacy {
+		t.Errorf("DefaultCachePath = %q, erwartet den alten Pfad %q", got, legacy)
+	}
+}
+
+func TestFileCacheCreatesDirectory(t *testing.T) {
+	fileCache := NewFileCache(filepath.Join(t.TempDir(), "wikr", "cache.json"))
+	fileCache.Set("de", "Berlin", CacheEntry{Summary: "Berlin"})
+
+	if _, found := fileCache.Get("de", "Berlin"); !found {
+		t.Error("Das Verzeichnis des Caches sollte angelegt werden")
+	}
+}
+
+func TestAppFilePathWithoutHomeDirectory(t *testing.T) {
+	// Ohne $HOME schlägt os.UserHomeDir fehl
+	t.Setenv("HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("TMPDIR", t.TempDir())
+
+	got := DefaultCachePath()
+	if want := filepath.Join(os.TempDir(), "wikr", "cache.json"); got != want {
+		t.Errorf("DefaultCachePath = %q, erwartet %q", got, want)
+	}
+	if filepath.Dir(got) != FallbackDir() {
+		t.Errorf("%q sollte in FallbackDir %q liegen", got, FallbackDir())
+	}
+}
@@ -0,0 +1,118 @@
+package wiki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultProject is the Wikimedia project used unless WithProject is given.
+const DefaultProject = "wikipedia"
+
+// Projects lists the Wikimedia projects that have language editions.
+var Projects = []string{"wikipedia", "wiktionary", "wikiquote", "wikibooks", "wikisource", "wikinews", "wikivoyage", "wikiversity"}
+
+// WithProject selects another Wikimedia project than Wikipedia, e.g.
+// "wiktionary". It sets the host, so pass WithHost after it to override.
+func WithProject(project string) Option {
+	return func(c *Client) {
+		c.project = project
+		c.host = "https://%s." + project + ".org"
+	}
+}
+
+func (c *Client) Project() string {
+	return c.project
+}
+
+// ProjectCacheKey is CacheKey for a title of the given project. Wikipedia
+// keys have no prefix so existing caches stay valid.
+func ProjectCacheKey(project, lang, title string) string {
+	return projectPrefix(project) + CacheKey(lang, title)
+}
+
+func projectPrefix(project string) string {
+	if project == "" || project == DefaultProject {
+		return ""
+	}
+	return project + ":"
+}
+
+// fetchExtract gets the introduction of the page from the MediaWiki
+// extracts API, for projects and languages without the REST summary
+// endpoint.
+func (c *Client) fetchExtract(ctx context.Context, title string) (CacheEntry, bool, error) {
+	requestURL := c.baseURL() + "/w/api.php?action=query&prop=extracts&exintro=1&explaintext=1&redirects=1&titles=" + url.QueryEscape(title) + "&format=json&formatversion=2"
+	body, status, err := c.get(ctx, requestURL)
+	if err != nil {
+		return CacheEntry{}, false, err
+	}
+	switch {
+	case status == http.StatusNotFound:
+		c.store(title, CacheEntry{NotFound: true})
+		return CacheEntry{}, false, ErrArticleNotFound
+	case status != http.StatusOK:
+		return CacheEntry{}, false, fmt.Errorf("extracts API returned %d %s", status, http.StatusText(status))
+	}
+
+	entry, err := parseExtract(body)
+	if err != nil {
+		return CacheEntry{}, false, err
+	}
+	if entry.NotFound {
+		c.store(title, entry)
+		return CacheEntry{}, false, ErrArticleNotFound
+	}
+	// The REST summary of Wikipedia is the first paragraph, the other
+	// projects keep the whole introduction
+	if c.project == DefaultProject {
+		entry.Summary = firstParagraph(entry.Summary)
+	}
+	entry.URL = c.ArticleURL(entry.Title)
+	c.log.Debug("summary from the extracts API", "title", entry.Title)
+	c.store(title, entry)
+
+	return entry, false, nil
+}
+
+// parseExtract decodes an extracts API response. A missing page results
+// in a negative entry.
+func parseExtract(body []byte) (CacheEntry, error) {
+	var result struct {
+		Query struct {
+			Pages []struct {
+				PageID  int    `json:"pageid"`
+				Title   string `json:"title"`
+				Extract string `json:"extract"`
+				Missing bool   `json:"missing"`
+			} `json:"pages"`
+		} `json:"query"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return CacheEntry{}, err
+	}
+	if len(result.Query.Pages) == 0 || result.Query.Pages[0].Missing {
+		return CacheEntry{NotFound: true}, nil
+	}
+
+	page := result.Query.Pages[0]
+	return CacheEntry{
+		Title:   page.Title,
+		Summary: strings.TrimSpace(page.Extract),
+		PageID:  page.PageID,
+	}, nil
+}
+
+// firstParagraph returns the text up to the first blank line or line
+// break, the plain text extracts separate paragraphs with them.
+func firstParagraph(text string) string {
+	for _, paragraph := range strings.Split(text, "\n") {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			return paragraph
+		}
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newWiktionaryStub simulates a project without the REST summary endpoint.
+func newWiktionaryStub(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/api/rest_v1/") {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		if r.URL.Query().Get("prop") != "extracts" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.URL.Query().Get("titles") {
+		case "serendipity":
+			fmt.Fprint(w, `{"batchcomplete": true, "query": {"pages": [{"pageid": 123, "ns": 0, "title": "serendipity", "extract": "English\n\nNoun: an unsought, unintended, and/or unexpected, but fortunate, discovery.\n"}]}}`)
+		default:
+			fmt.Fprint(w, `{"batchcomplete": true, "query": {"pages": [{"ns": 0, "title": "Gibtesnicht", "missing": true}]}}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWiktionaryFallsBackToExtracts(t *testing.T) {
+	server := newWiktionaryStub(t)
+	fileCache := newTestCache(t)
+	client := NewClient("en", WithProject("wiktionary"), WithHost(server.URL+"/%s"), WithCache(fileCache))
+
+	entry, _, err := client.Summary(context.Background(), "serendipity")
+	if err != nil {
+		t.Fatalf("Summary sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if !strings.Contains(entry.Summary, "fortunate, discovery") {
+		t.Errorf("Unerwartete Zusammenfassung: '%s'", entry.Summary)
+	}
+	if !strings.HasSuffix(entry.URL, "/wiki/serendipity") {
+		t.Errorf("Unerwartete URL: '%s'", entry.URL)
+	}
+
+	// Der Cache-Schlüssel enthält das Projekt
+	if _, found := fileCache.GetKey("wiktionary:en:Serendipity"); !found {
+		t.Error("Der Eintrag sollte unter 'wiktionary:en:Serendipity' im Cache liegen")
+	}
+	if _, found := fileCache.Get("en", "serendipity"); found {
+		t.Error("Der Eintrag sollte nicht mit Wikipedia geteilt werden")
+	}
+}
+
+func TestWiktionaryMissingWord(t *testing.T) {
+	server := newWiktionaryStub(t)
+	client := NewClient("en", WithProject("wiktionary"), WithHost(server.URL+"/%s"))
+
+	if _, _, err := client.Summary(context.Background(), "Gibtesnicht"); !errors.Is(err, ErrArticleNotFound) {
+		t.Errorf("Erwartete ErrArticleNotFound, erhielt %v", err)
+	}
+}
+
+func TestWithProjectHost(t *testing.T) {
+	client := NewClient("de", WithProject("wikiquote"))
+	if url := client.ArticleURL("Goethe"); url != "https://de.wikiquote.org/wiki/Goethe" {
+		t.Errorf("Erwartete die Wikiquote-URL, erhielt '%s'", url)
+	}
+
+	if key := ProjectCacheKey("wikipedia", "de", "Berlin"); key != "de:Berlin" {
+		t.Errorf("Wikipedia-Schlüssel sollten unverändert bleiben, erhielt '%s'", key)
+	}
+}
+
+func TestWikipediaFallsBackToExtracts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/api/rest_v1/") {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"batchcomplete": true, "query": {"pages": [{"pageid": 2013, "ns": 0, "title": "Berlin", "extract": "Berlin ist die Hauptstadt Deutschlands.\nBerlin ist eine Stadt.\n\nGeschichte"}]}}`)
+	}))
+	defer server.Close()
+
+	var logs strings.Builder
+	log := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithLogger(log))
+	entry, _, err := client.Summary(context.Background(), "Berlin")
+	if err != nil {
+		t.Fatalf("Summary sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if entry.Summary != "Berlin ist die Hauptstadt Deutschlands." {
+		t.Errorf("Erwartete den ersten Absatz, erhielt '%s'", entry.Summary)
+	}
+	if !strings.HasSuffix(entry.URL, "/wiki/Berlin") {
+		t.Errorf("Unerwartete URL: '%s'", entry.URL)
+	}
+	if !strings.Contains(logs.String(), "extracts API") {
+		t.Errorf("Das Log sollte den Weg über die Extracts-API nennen: %s", logs.String())
+	}
+}
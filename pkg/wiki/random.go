@@ -0,0 +1,34 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// RandomURL returns the URL that Random requests.
+func (c *Client) RandomURL() string {
+	return c.baseURL() + "/api/rest_v1/page/random/summary"
+}
+
+// Random returns the summary of a random article. It is cached like any
+// other summary so it can be looked up again later.
+func (c *Client) Random(ctx context.Context) (CacheEntry, error) {
+	body, status, err := c.get(ctx, c.RandomURL())
+	if err != nil {
+		return CacheEntry{}, err
+	}
+	if status != http.StatusOK {
+		return CacheEntry{}, fmt.Errorf("random article: unexpected status %d", status)
+	}
+
+	entry, err := parseSummary(body)
+	if err != nil {
+		return CacheEntry{}, err
+	}
+	c.ensureURL(&entry, "")
+	if entry.Canonical != "" {
+		c.store(entry.Canonical, entry)
+	}
+	return entry, nil
+}
@@ -0,0 +1,36 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRandom(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/de/api/rest_v1/page/random/summary" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"title": "Zugspitze", "titles": {"canonical": "Zugspitze", "normalized": "Zugspitze"}, "extract": "Die Zugspitze ist der höchste Berg Deutschlands.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Zugspitze"}}}`)
+	}))
+	defer server.Close()
+
+	fileCache := newTestCache(t)
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithCache(fileCache))
+
+	entry, err := client.Random(context.Background())
+	if err != nil {
+		t.Fatalf("Random sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if entry.Summary == "" {
+		t.Error("Die Zusammenfassung sollte nicht leer sein")
+	}
+
+	// Der Zufallsartikel lässt sich danach aus dem Cache abrufen
+	if _, found := fileCache.Get("de", "Zugspitze"); !found {
+		t.Error("Der Zufallsartikel sollte im Cache liegen")
+	}
+}
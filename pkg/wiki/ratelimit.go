@@ -0,0 +1,42 @@
+package wiki
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrRateLimited matches a RateLimitError with errors.Is.
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimitError is returned when Wikipedia answers 429 Too Many Requests.
+type RateLimitError struct {
+	// RetryAfter is how long the server asked to wait, 0 if it did not say
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("Wikipedia is rate limiting requests, try again in %s", e.RetryAfter)
+	}
+	return "Wikipedia is rate limiting requests, try again later"
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// rateLimitError builds the error for a 429 response from its Retry-After
+// header, which holds either seconds or an HTTP date.
+func rateLimitError(response *http.Response, now time.Time) *RateLimitError {
+	value := response.Header.Get("Retry-After")
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return &RateLimitError{RetryAfter: time.Duration(seconds) * time.Second}
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return &RateLimitError{RetryAfter: date.Sub(now).Round(time.Second)}
+	}
+	return &RateLimitError{}
+}
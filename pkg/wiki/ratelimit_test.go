@@ -0,0 +1,60 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("Too many requests"))
+	}))
+	defer server.Close()
+
+	fileCache := newTestCache(t)
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithCache(fileCache))
+
+	_, _, err := client.Summary(context.Background(), "Berlin")
+	var rateLimit *RateLimitError
+	if !errors.As(err, &rateLimit) {
+		t.Fatalf("Erwartet RateLimitError, bekommen: %v", err)
+	}
+	if rateLimit.RetryAfter != 30*time.Second {
+		t.Errorf("RetryAfter = %v, erwartet 30s", rateLimit.RetryAfter)
+	}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Error("Der Fehler sollte ErrRateLimited entsprechen")
+	}
+	if err.Error() != "Wikipedia is rate limiting requests, try again in 30s" {
+		t.Errorf("Unerwartete Fehlermeldung: %q", err.Error())
+	}
+
+	// Die Drosselung darf nicht als fehlender Artikel gespeichert werden
+	if _, found := fileCache.Get("de", "Berlin"); found {
+		t.Error("Eine 429-Antwort sollte nicht im Cache landen")
+	}
+
+	if _, err := client.Search(context.Background(), "Berlin"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Search sollte ErrRateLimited zurückgeben, bekommen: %v", err)
+	}
+}
+
+func TestRateLimitErrorRetryAfterDate(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	response := &http.Response{Header: http.Header{"Retry-After": {now.Add(2 * time.Minute).Format(http.TimeFormat)}}}
+
+	if got := rateLimitError(response, now).RetryAfter; got != 2*time.Minute {
+		t.Errorf("RetryAfter = %v, erwartet 2m", got)
+	}
+
+	response.Header.Set("Retry-After", "bald")
+	if got := rateLimitError(response, now).RetryAfter; got != 0 {
+		t.Errorf("Ein ungültiger Header sollte ignoriert werden, bekommen %v", got)
+	}
+}
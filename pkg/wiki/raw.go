@@ -0,0 +1,41 @@
+package wiki
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// WithRawDir writes the raw JSON bodies of the search and summary
+// responses to files in dir, independent of the cache. Meant for debugging
+// when the API changes shape.
+func WithRawDir(dir string) Option {
+	return func(c *Client) {
+		c.rawDir = dir
+	}
+}
+
+// RawFileName returns the name of the file WithRawDir writes for a
+// response, e.g. "summary-de-Berlin.json". The query or title is escaped
+// so it is a valid file name.
+func RawFileName(kind, lang, name string) string {
+	return kind + "-" + lang + "-" + url.PathEscape(name) + ".json"
+}
+
+// saveRaw writes the body if a raw directory is set. Failures are only
+// logged, they must not break the lookup.
+func (c *Client) saveRaw(kind, name string, body []byte) {
+	if c.rawDir == "" {
+		return
+	}
+	if err := os.MkdirAll(c.rawDir, 0700); err != nil {
+		c.log.Warn("error creating raw response directory", "path", c.rawDir, "error", err)
+		return
+	}
+	path := filepath.Join(c.rawDir, RawFileName(kind, c.lang, name))
+	if err := os.WriteFile(path, body, 0600); err != nil {
+		c.log.Warn("error writing raw response", "path", path, "error", err)
+		return
+	}
+	c.log.Debug("saved raw response", "path", path)
+}
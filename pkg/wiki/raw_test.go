@@ -0,0 +1,56 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithRawDir(t *testing.T) {
+	const searchBody = `{"query": {"search": [{"title": "AC/DC"}]}}`
+	const summaryBody = `{"title": "AC/DC", "titles": {"canonical": "AC/DC"}, "extract": "AC/DC ist eine Rockband.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/AC/DC"}}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("list") == "search" {
+			fmt.Fprint(w, searchBody)
+			return
+		}
+		fmt.Fprint(w, summaryBody)
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "raw")
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithCache(newTestCache(t)), WithRawDir(dir))
+	if _, err := client.Search(context.Background(), "AC/DC"); err != nil {
+		t.Fatalf("Search sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if _, _, err := client.Summary(context.Background(), "AC/DC"); err != nil {
+		t.Fatalf("Summary sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"search-de-AC%2FDC.json":  searchBody,
+		"summary-de-AC%2FDC.json": summaryBody,
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("Die Rohdatei %s sollte geschrieben werden: %v", name, err)
+			continue
+		}
+		if string(data) != want {
+			t.Errorf("%s: erwartete %q, erhielt %q", name, want, data)
+		}
+	}
+
+	// Ein Treffer aus dem Cache schreibt keine neue Rohdatei
+	os.RemoveAll(dir)
+	if _, _, err := client.Summary(context.Background(), "AC/DC"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("Ohne Anfrage sollte keine Rohdatei entstehen")
+	}
+}
@@ -0,0 +1,55 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSummaryRedirect(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// Die API folgt der Weiterleitung und meldet den kanonischen Titel
+		if r.URL.Path == "/en/api/rest_v1/page/summary/JFK" {
+			http.Redirect(w, r, "/en/api/rest_v1/page/summary/John_F._Kennedy", http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, `{"title": "John F. Kennedy", "titles": {"canonical": "John_F._Kennedy", "normalized": "John F. Kennedy"}, "extract": "John Fitzgerald Kennedy was the 35th president.", "content_urls": {"desktop": {"page": "https://en.wikipedia.org/wiki/John_F._Kennedy"}}}`)
+	}))
+	defer server.Close()
+
+	fileCache := newTestCache(t)
+	client := NewClient("en", WithHost(server.URL+"/%s"), WithCache(fileCache))
+
+	entry, _, err := client.Summary(context.Background(), "JFK")
+	if err != nil {
+		t.Fatalf("Summary sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if entry.Canonical != "John_F._Kennedy" {
+		t.Errorf("Erwarteter kanonischer Titel 'John_F._Kennedy', erhielt '%s'", entry.Canonical)
+	}
+
+	// Der Inhalt liegt nur unter dem kanonischen Titel, der angefragte zeigt darauf
+	cache := fileCache.Load()
+	if cache["en:JFK"].RedirectTo != "John_F._Kennedy" || cache["en:JFK"].Summary != "" {
+		t.Errorf("Unter 'en:JFK' sollte nur die Weiterleitung liegen: %+v", cache["en:JFK"])
+	}
+	if cache["en:John_F._Kennedy"].Summary == "" {
+		t.Error("Unter 'en:John_F._Kennedy' sollte der Inhalt liegen")
+	}
+
+	// Beide Titel treffen danach den Cache
+	requestsBefore := requests
+	for _, title := range []string{"JFK", "John F. Kennedy"} {
+		entry, cached, err := client.Summary(context.Background(), title)
+		if err != nil || !cached || entry.Title != "John F. Kennedy" {
+			t.Errorf("%s: erwartete den gecachten Artikel, erhielt %+v (cached=%v, err=%v)", title, entry, cached, err)
+		}
+	}
+	if requests != requestsBefore {
+		t.Errorf("Es sollten keine weiteren Anfragen gestellt werden, erhielt %d", requests-requestsBefore)
+	}
+}
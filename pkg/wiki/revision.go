@@ -0,0 +1,82 @@
+package wiki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PermalinkURL returns the link to the revision of the article, which
+// keeps showing the text as it was when cited.
+func (c *Client) PermalinkURL(title string, revision int64) string {
+	return c.baseURL() + "/w/index.php?title=" + url.QueryEscape(strings.ReplaceAll(title, " ", "_")) + "&oldid=" + strconv.FormatInt(revision, 10)
+}
+
+// CurIDURL returns the link to the article by its page ID, which survives
+// renaming the article.
+func (c *Client) CurIDURL(pageID int) string {
+	return c.baseURL() + "/w/index.php?curid=" + strconv.Itoa(pageID)
+}
+
+// RevisionURL returns the URL that Revision requests for the title.
+func (c *Client) RevisionURL(title string) string {
+	return c.baseURL() + "/w/api.php?action=query&prop=revisions&rvprop=ids&redirects=1&titles=" + url.QueryEscape(title) + "&format=json&formatversion=2"
+}
+
+// Revision fills in the page ID and the revision of the entry if it does
+// not have them yet, e.g. because it was cached by an older version or
+// read from the extracts API. The cached entry is updated without
+// renewing it, and the latest revision is used.
+func (c *Client) Revision(ctx context.Context, entry CacheEntry) (CacheEntry, error) {
+	if entry.PageID > 0 && entry.Revision > 0 {
+		return entry, nil
+	}
+	title := entry.Title
+	if entry.Canonical != "" {
+		title = entry.Canonical
+	}
+	body, _, err := c.get(ctx, c.RevisionURL(title))
+	if err != nil {
+		return entry, err
+	}
+	pageID, revision, err := parseRevision(body, title)
+	if err != nil {
+		return entry, err
+	}
+	entry.PageID, entry.Revision = pageID, revision
+	if c.cache != nil {
+		c.cache.Update(c.key(title), func(cached *CacheEntry) {
+			cached.PageID, cached.Revision = pageID, revision
+		})
+	}
+	return entry, nil
+}
+
+// parseRevision decodes the page ID and the latest revision from a query
+// API response.
+func parseRevision(body []byte, title string) (int, int64, error) {
+	var result struct {
+		Query struct {
+			Pages []struct {
+				PageID    int  `json:"pageid"`
+				Missing   bool `json:"missing"`
+				Revisions []struct {
+					RevID int64 `json:"revid"`
+				} `json:"revisions"`
+			} `json:"pages"`
+		} `json:"query"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, 0, err
+	}
+	for _, page := range result.Query.Pages {
+		if page.Missing || len(page.Revisions) == 0 {
+			continue
+		}
+		return page.PageID, page.Revisions[0].RevID, nil
+	}
+	return 0, 0, fmt.Errorf("%w for %q", ErrArticleNotFound, title)
+}
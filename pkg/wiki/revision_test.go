@@ -0,0 +1,69 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRevisionURLs(t *testing.T) {
+	client := NewClient("de")
+	if got, want := client.PermalinkURL("Albert Einstein", 241234567), "https://de.wikipedia.org/w/index.php?title=Albert_Einstein&oldid=241234567"; got != want {
+		t.Errorf("PermalinkURL = %q, erwartet %q", got, want)
+	}
+	if got, want := client.CurIDURL(2013), "https://de.wikipedia.org/w/index.php?curid=2013"; got != want {
+		t.Errorf("CurIDURL = %q, erwartet %q", got, want)
+	}
+}
+
+func TestParseSummaryRevision(t *testing.T) {
+	entry, err := parseSummary([]byte(`{"title": "Berlin", "pageid": 2013, "revision": "241234567", "extract": "Berlin ist die Hauptstadt."}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.PageID != 2013 || entry.Revision != 241234567 {
+		t.Errorf("Erwartete Seite 2013 in Version 241234567, erhielt %d und %d", entry.PageID, entry.Revision)
+	}
+}
+
+func TestRevisionFetchesMissingIDs(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("prop") != "revisions" || r.URL.Query().Get("titles") != "Berlin" {
+			t.Errorf("Unerwartete Anfrage: %s", r.URL)
+		}
+		fmt.Fprint(w, `{"query": {"pages": [{"pageid": 2013, "title": "Berlin", "revisions": [{"revid": 241234567, "parentid": 241234000}]}]}}`)
+	}))
+	defer server.Close()
+
+	fileCache := newTestCache(t)
+	stored := time.Now().Add(-time.Hour).Round(time.Second)
+	fileCache.Save(Cache{"de:Berlin": CacheEntry{Title: "Berlin", Summary: "Berlin ist die Hauptstadt.", Timestamp: stored}})
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithCache(fileCache))
+
+	entry, err := client.Revision(context.Background(), CacheEntry{Title: "Berlin"})
+	if err != nil {
+		t.Fatalf("Revision sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if entry.PageID != 2013 || entry.Revision != 241234567 {
+		t.Errorf("Unerwartete IDs: %d, %d", entry.PageID, entry.Revision)
+	}
+	cached, _ := fileCache.GetStale("de:Berlin")
+	if cached.Revision != 241234567 || !cached.Timestamp.Equal(stored) {
+		t.Errorf("Der Cache sollte die Version ohne neuen Zeitstempel speichern: %+v", cached)
+	}
+
+	// Mit beiden IDs wird nichts angefragt
+	if _, err := client.Revision(context.Background(), entry); err != nil || requests != 1 {
+		t.Errorf("Erwartete keine weitere Anfrage, %d Anfragen, Fehler %v", requests, err)
+	}
+
+	if _, _, err := parseRevision([]byte(`{"query": {"pages": [{"title": "Gibtesnicht", "missing": true}]}}`), "Gibtesnicht"); !errors.Is(err, ErrArticleNotFound) {
+		t.Errorf("Ein fehlender Artikel sollte ErrArticleNotFound liefern: %v", err)
+	}
+}
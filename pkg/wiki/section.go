@@ -0,0 +1,162 @@
+package wiki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Markup that has no place in the plain text of a section
+	htmlNoisePattern = regexp.MustCompile(`(?s)<style.*?</style>|<sup[^>]*class="[^"]*reference[^"]*"[^>]*>.*?</sup>`)
+	htmlBlockPattern = regexp.MustCompile(`(?i)</?(p|div|h[1-6]|li|ul|ol|table|tr|br)[^>]*>`)
+	htmlTagPattern   = regexp.MustCompile(`<[^>]+>`)
+	blankLinePattern = regexp.MustCompile(`\n\s*\n+`)
+)
+
+type Section struct {
+	Title  string `json:"line"`
+	Index  string `json:"index"`
+	Anchor string `json:"anchor"`
+}
+
+// Section returns the plain text of the section of an article with the
+// given heading. The entry is cached under the title and the heading.
+func (c *Client) Section(ctx context.Context, title, name string) (CacheEntry, bool, error) {
+	// The section name is part of the key so it does not clash with the summary
+	key := title + "#" + name
+	if entry, found := c.Cached(key); found {
+		return entry, true, nil
+	}
+
+	body, _, err := c.get(ctx, c.SectionsURL(title))
+	if err != nil {
+		return CacheEntry{}, false, err
+	}
+
+	sections, err := parseSections(body)
+	if err != nil {
+		return CacheEntry{}, false, err
+	}
+
+	section, err := findSection(sections, name)
+	if err != nil {
+		return CacheEntry{}, false, err
+	}
+
+	body, _, err = c.get(ctx, c.parseURL(title, "&section="+section.Index+"&prop=text&disabletoc=1&disableeditsection=1"))
+	if err != nil {
+		return CacheEntry{}, false, err
+	}
+
+	text, err := parseSectionText(body)
+	if err != nil {
+		return CacheEntry{}, false, err
+	}
+
+	entry := CacheEntry{
+		Summary: text,
+		URL:     c.ArticleURL(title) + "#" + section.Anchor,
+	}
+	c.store(key, entry)
+
+	return entry, false, nil
+}
+
+// SectionsURL returns the URL of the section list that Section requests
+// first for the title.
+func (c *Client) SectionsURL(title string) string {
+	return c.parseURL(title, "&prop=sections")
+}
+
+// parseURL builds a request to the parse API for the page with the given
+// extra parameters.
+func (c *Client) parseURL(title, params string) string {
+	return c.baseURL() + "/w/api.php?action=parse&page=" + url.QueryEscape(title) + params + "&redirects=1&format=json&formatversion=2"
+}
+
+// parseSections decodes the section list of a parse API response.
+func parseSections(body []byte) ([]Section, error) {
+	var result struct {
+		Parse struct {
+			Sections []Section `json:"sections"`
+		} `json:"parse"`
+		Error struct {
+			Info string `json:"info"`
+		} `json:"error"`
+	}
+	err := json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.Error.Info != "" {
+		return nil, fmt.Errorf("%s", result.Error.Info)
+	}
+	return result.Parse.Sections, nil
+}
+
+// findSection looks up a section by its heading, ignoring case. If there is
+// no such section the error lists the available ones.
+func findSection(sections []Section, name string) (Section, error) {
+	names := make([]string, len(sections))
+	for i, section := range sections {
+		title := html.UnescapeString(htmlTagPattern.ReplaceAllString(section.Title, ""))
+		if strings.EqualFold(title, strings.TrimSpace(name)) {
+			return section, nil
+		}
+		names[i] = title
+	}
+	if len(names) == 0 {
+		return Section{}, fmt.Errorf("section %q not found, the article has no sections", name)
+	}
+	return Section{}, fmt.Errorf("section %q not found, available sections:\n  %s", name, strings.Join(names, "\n  "))
+}
+
+// parseSectionText decodes the HTML of a single section into plain text.
+func parseSectionText(body []byte) (string, error) {
+	text, err := parseHTML(body)
+	if err != nil {
+		return "", err
+	}
+	return stripHTML(text), nil
+}
+
+// parseHTML returns the rendered HTML of a parse API response.
+func parseHTML(body []byte) (string, error) {
+	var result struct {
+		Parse struct {
+			Text string `json:"text"`
+		} `json:"parse"`
+		Error struct {
+			Info string `json:"info"`
+		} `json:"error"`
+	}
+	err := json.Unmarshal(body, &result)
+	if err != nil {
+		return "", err
+	}
+	if result.Error.Info != "" {
+		return "", fmt.Errorf("%s", result.Error.Info)
+	}
+	return result.Parse.Text, nil
+}
+
+// stripHTML turns rendered article HTML into readable plain text.
+func stripHTML(text string) string {
+	text = htmlNoisePattern.ReplaceAllString(text, "")
+	text = htmlBlockPattern.ReplaceAllString(text, "\n")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text = strings.Join(lines, "\n")
+	text = blankLinePattern.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,83 @@
+package wiki
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSectionsResponse = `{
+	"parse": {
+		"title": "Berlin",
+		"pageid": 2013,
+		"sections": [
+			{"toclevel": 1, "level": "2", "line": "Geographie", "number": "1", "index": "1", "anchor": "Geographie"},
+			{"toclevel": 1, "level": "2", "line": "Geschichte", "number": "2", "index": "2", "anchor": "Geschichte"},
+			{"toclevel": 1, "level": "2", "line": "<i>Politik</i>", "number": "3", "index": "3", "anchor": "Politik"}
+		]
+	}
+}`
+
+func TestFindSection(t *testing.T) {
+	sections, err := parseSections([]byte(testSectionsResponse))
+	if err != nil {
+		t.Fatalf("parseSections sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	section, err := findSection(sections, "geschichte")
+	if err != nil {
+		t.Fatalf("Der Abschnitt 'Geschichte' sollte gefunden werden: %v", err)
+	}
+	if section.Index != "2" || section.Anchor != "Geschichte" {
+		t.Errorf("Erwartete Index '2' und Anker 'Geschichte', erhielt '%s' und '%s'", section.Index, section.Anchor)
+	}
+
+	// Überschriften mit Markup sollten ebenfalls gefunden werden
+	if _, err := findSection(sections, "Politik"); err != nil {
+		t.Errorf("Der Abschnitt 'Politik' sollte gefunden werden: %v", err)
+	}
+}
+
+func TestFindSectionMissing(t *testing.T) {
+	sections, err := parseSections([]byte(testSectionsResponse))
+	if err != nil {
+		t.Fatalf("parseSections sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	_, err = findSection(sections, "Wirtschaft")
+	if err == nil {
+		t.Fatal("Für einen fehlenden Abschnitt sollte ein Fehler zurückgegeben werden")
+	}
+
+	for _, name := range []string{"Geographie", "Geschichte", "Politik"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("Die Fehlermeldung sollte den verfügbaren Abschnitt '%s' auflisten: %v", name, err)
+		}
+	}
+}
+
+func TestParseSectionText(t *testing.T) {
+	body := []byte(`{
+		"parse": {
+			"title": "Berlin",
+			"text": "<div class=\"mw-parser-output\"><h2 id=\"Geschichte\">Geschichte</h2><style>.x{color:red}</style><p>Berlin wurde 1237 erstmals erw&auml;hnt.<sup class=\"reference\"><a href=\"#cite_note-1\">[1]</a></sup></p>\n\n\n<p>Seit 1990 ist Berlin die <a href=\"/wiki/Hauptstadt\">Hauptstadt</a>.</p></div>"
+		}
+	}`)
+
+	text, err := parseSectionText(body)
+	if err != nil {
+		t.Fatalf("parseSectionText sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	want := "Geschichte\n\nBerlin wurde 1237 erstmals erwähnt.\n\nSeit 1990 ist Berlin die Hauptstadt."
+	if text != want {
+		t.Errorf("Erwartete\n%q\nerhielt\n%q", want, text)
+	}
+}
+
+func TestParseSectionsAPIError(t *testing.T) {
+	body := []byte(`{"error": {"code": "missingtitle", "info": "The page you specified doesn't exist."}}`)
+
+	if _, err := parseSections(body); err == nil {
+		t.Error("Ein API-Fehler sollte als Fehler zurückgegeben werden")
+	}
+}
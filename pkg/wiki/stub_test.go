@@ -0,0 +1,84 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// cannedSearches and cannedSummaries are the responses of the stub
+// Wikipedia, keyed by search term and article title.
+var cannedSearches = map[string]string{
+	"Berlin": `{"query": {"search": [{"title": "Berlin"}, {"title": "Berlin-Mitte"}, {"title": "Berliner Mauer"}]}}`,
+}
+
+var cannedSummaries = map[string]string{
+	"Berlin": `{"title": "Berlin", "extract": "Berlin ist die Hauptstadt der Bundesrepublik Deutschland.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`,
+}
+
+// stubWiki is an httptest server that answers searches and summaries with
+// the canned responses and counts the requests it got.
+type stubWiki struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests int
+}
+
+// newStubWiki starts the stub, it is closed when the test ends.
+func newStubWiki(t *testing.T) *stubWiki {
+	t.Helper()
+	stub := &stubWiki{}
+	stub.Server = httptest.NewServer(http.HandlerFunc(stub.serve))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func (s *stubWiki) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	s.mu.Unlock()
+
+	if term := r.URL.Query().Get("srsearch"); term != "" {
+		if body, ok := cannedSearches[term]; ok {
+			fmt.Fprint(w, body)
+			return
+		}
+		fmt.Fprint(w, `{"query": {"search": []}}`)
+		return
+	}
+	if _, title, ok := strings.Cut(r.URL.Path, "/api/rest_v1/page/summary/"); ok {
+		if body, ok := cannedSummaries[title]; ok {
+			fmt.Fprint(w, body)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// Requests returns how many requests the stub has answered.
+func (s *stubWiki) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// client returns a client for the language that talks to the stub.
+func (s *stubWiki) client(lang string, opts ...Option) *Client {
+	return NewClient(lang, append([]Option{WithHost(s.URL + "/%s")}, opts...)...)
+}
+
+func TestStubWikiUnknownTitle(t *testing.T) {
+	stub := newStubWiki(t)
+	_, _, err := stub.client("de").Summary(context.Background(), "Gibt es nicht")
+	if err == nil {
+		t.Error("Ein unbekannter Titel sollte einen Fehler liefern")
+	}
+	results, err := stub.client("de").Search(context.Background(), "Gibt es nicht")
+	if err != nil || len(results) != 0 {
+		t.Errorf("Eine unbekannte Suche sollte leer sein, erhalten %v, %v", results, err)
+	}
+}
@@ -0,0 +1,30 @@
+package wiki
+
+import "time"
+
+// Phases reported to the function passed to WithTimings.
+const (
+	PhaseSearch     = "search request"
+	PhaseSummary    = "summary request"
+	PhaseCacheRead  = "cache read"
+	PhaseCacheWrite = "cache write"
+)
+
+// WithTimings calls record with the duration of every search and summary
+// request and every cache access of the client.
+func WithTimings(record func(phase string, elapsed time.Duration)) Option {
+	return func(c *Client) {
+		c.record = record
+	}
+}
+
+// track reports the time since start for the phase, if timings are
+// recorded. Use it as defer c.track(phase, time.Now()).
+func (c *Client) track(phase string, start time.Time) {
+	if c.record == nil {
+		return
+	}
+	elapsed := time.Since(start)
+	c.log.Debug("timing", "phase", phase, "elapsed", elapsed)
+	c.record(phase, elapsed)
+}
@@ -0,0 +1,43 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithTimings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("list") == "search" {
+			fmt.Fprint(w, `{"query": {"search": [{"title": "Berlin"}]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"title": "Berlin", "titles": {"canonical": "Berlin"}, "extract": "Berlin ist die Hauptstadt Deutschlands.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+	}))
+	defer server.Close()
+
+	phases := make(map[string]int)
+	record := func(phase string, elapsed time.Duration) {
+		if elapsed < 0 {
+			t.Errorf("Die Dauer von %s sollte nicht negativ sein: %v", phase, elapsed)
+		}
+		phases[phase]++
+	}
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithCache(newTestCache(t)), WithTimings(record))
+
+	if _, err := client.Search(context.Background(), "Berlin"); err != nil {
+		t.Fatalf("Search sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if _, _, err := client.Summary(context.Background(), "Berlin"); err != nil {
+		t.Fatalf("Summary sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	for _, phase := range []string{PhaseSearch, PhaseSummary, PhaseCacheRead, PhaseCacheWrite} {
+		if phases[phase] == 0 {
+			t.Errorf("Für %q sollte eine Zeit gemessen werden: %v", phase, phases)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package wiki
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultTransport is shared by all clients without WithHTTPClient, so the
+// connections to Wikipedia are kept alive and reused across requests. It
+// keeps more idle connections per host than http.DefaultTransport so that
+// parallel lookups do not have to reconnect.
+var DefaultTransport http.RoundTripper = newTransport()
+
+var defaultHTTPClient = &http.Client{Transport: DefaultTransport}
+
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 100
+	transport.MaxIdleConnsPerHost = 16
+	transport.IdleConnTimeout = 90 * time.Second
+	return transport
+}
@@ -0,0 +1,47 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClientsReuseConnections(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"query": {"search": [{"title": "Berlin"}]}}`)
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	// Jede Suche mit einem neuen Client, wie bei -batch
+	for i := 0; i < 5; i++ {
+		client := NewClient("de", WithHost(server.URL+"/%s"))
+		if _, err := client.Search(context.Background(), "Berlin"); err != nil {
+			t.Fatalf("Search sollte keinen Fehler zurückgeben: %v", err)
+		}
+	}
+	if n := connections.Load(); n != 1 {
+		t.Errorf("Erwartete 1 Verbindung für 5 Anfragen, erhielt %d", n)
+	}
+}
+
+func TestDefaultTransportKeepsIdleConnections(t *testing.T) {
+	transport, ok := DefaultTransport.(*http.Transport)
+	if !ok {
+		t.Fatalf("DefaultTransport sollte ein *http.Transport sein, erhielt %T", DefaultTransport)
+	}
+	// -batch schickt standardmäßig 4 Anfragen parallel
+	if transport.DisableKeepAlives || transport.MaxIdleConnsPerHost < 4 {
+		t.Errorf("Keep-Alive aus oder zu wenige Verbindungen pro Host: %d", transport.MaxIdleConnsPerHost)
+	}
+}
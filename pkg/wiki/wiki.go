@@ -0,0 +1,577 @@
+// Package wiki is a small client for the Wikipedia search and summary
+// APIs with an optional file cache. It is the core of the wikr command.
+package wiki
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultHost = "https://%s.wikipedia.org"
+
+var (
+	ErrArticleNotFound = errors.New("article not found")
+	ErrNoResults       = errors.New("no results found")
+)
+
+// Client talks to the Wikipedia of one language.
+type Client struct {
+	lang       string
+	project    string
+	host       string
+	httpClient *http.Client
+	cache      *FileCache
+	ttl        time.Duration
+	rawDir     string
+	// searchLimit is the number of search results to collect across
+	// pages, set by WithSearchLimit
+	searchLimit int
+	// authorization is sent with every request, see WithAuthorization
+	authorization string
+	// adaptiveMin and adaptiveMax bound the TTL that depends on the last
+	// edit, see WithAdaptiveTTL
+	adaptiveMin, adaptiveMax time.Duration
+	// pin marks every article the client caches or reads from the cache
+	// as pinned, see WithPin
+	pin bool
+	// maxBytes caps the length of a response body, see WithMaxBytes
+	maxBytes int64
+	log      *slog.Logger
+	// record receives the timings set with WithTimings
+	record func(phase string, elapsed time.Duration)
+}
+
+type Option func(*Client)
+
+// WithHost sets the base URL of the wiki. A "%s" in host is replaced with
+// the language, see DefaultHost.
+func WithHost(host string) Option {
+	return func(c *Client) {
+		c.host = strings.TrimSuffix(host, "/")
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAuthorization sends the value as Authorization header with every
+// request, e.g. "Bearer <token>" for a private MediaWiki. Combine it with
+// WithHost pointing to the wiki.
+func WithAuthorization(value string) Option {
+	return func(c *Client) {
+		c.authorization = value
+	}
+}
+
+// WithCache enables caching of summaries. Without it every call to
+// Summary hits the network.
+func WithCache(cache *FileCache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithPin pins the articles the client fetches or finds in the cache, so
+// they never expire. Missing articles are not pinned.
+func WithPin() Option {
+	return func(c *Client) {
+		c.pin = true
+	}
+}
+
+// WithTTL keeps the articles the client caches for ttl instead of
+// CacheDuration. Missing articles keep their shorter TTL.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.ttl = ttl
+	}
+}
+
+// NewClient returns a client for the Wikipedia in lang, e.g. "de".
+func NewClient(lang string, opts ...Option) *Client {
+	c := &Client{
+		lang:       lang,
+		project:    DefaultProject,
+		host:       DefaultHost,
+		httpClient: defaultHTTPClient,
+		log:        discardLogger,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) Lang() string {
+	return c.lang
+}
+
+func (c *Client) baseURL() string {
+	if strings.Contains(c.host, "%s") {
+		return fmt.Sprintf(c.host, c.lang)
+	}
+	return c.host
+}
+
+// ArticleURL returns the link to the article with the given title.
+func (c *Client) ArticleURL(title string) string {
+	return c.baseURL() + "/wiki/" + url.PathEscape(strings.ReplaceAll(title, " ", "_"))
+}
+
+// SearchURL returns the URL that Search requests for the term.
+func (c *Client) SearchURL(term string) string {
+	return c.baseURL() + "/w/api.php?action=query&list=search&srsearch=" + url.QueryEscape(term) + "&format=json"
+}
+
+// SummaryURL returns the URL that FetchSummary requests for the title.
+func (c *Client) SummaryURL(title string) string {
+	return c.baseURL() + "/api/rest_v1/page/summary/" + url.PathEscape(title)
+}
+
+// Search returns the titles of the articles matching term, most relevant
+// first.
+func (c *Client) Search(ctx context.Context, term string) ([]string, error) {
+	result, err := c.SearchWithSuggestion(ctx, term)
+	return result.Titles, err
+}
+
+// SearchResult holds the titles found by a search, most relevant first.
+type SearchResult struct {
+	Titles []string
+	// Suggestion is a corrected spelling of the term offered by the API,
+	// empty if it has none
+	Suggestion string
+}
+
+// SearchWithSuggestion is Search that also returns the spelling suggestion
+// of the API.
+func (c *Client) SearchWithSuggestion(ctx context.Context, term string) (SearchResult, error) {
+	if c.searchLimit > 0 {
+		page, err := c.searchPage(ctx, term, c.searchPageURL(term, 0, c.searchLimit), term)
+		if err != nil {
+			return SearchResult{}, err
+		}
+		return c.continueSearch(ctx, term, page)
+	}
+	page, err := c.searchPage(ctx, term, c.SearchURL(term), term)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	return SearchResult{Titles: page.titles, Suggestion: page.suggestion}, nil
+}
+
+// searchPage is one response of the search API.
+type searchPage struct {
+	titles     []string
+	suggestion string
+	// next is the offset of the following page, 0 if there is none
+	next int
+}
+
+// searchPage requests one page of search results. rawName names the file
+// for WithRawDir.
+func (c *Client) searchPage(ctx context.Context, term, requestURL, rawName string) (searchPage, error) {
+	start := time.Now()
+	body, status, err := c.get(ctx, requestURL)
+	c.track(PhaseSearch, start)
+	if err != nil {
+		return searchPage{}, err
+	}
+	c.saveRaw("search", rawName, body)
+
+	var result struct {
+		Query struct {
+			Search []struct {
+				Title string `json:"title"`
+			} `json:"search"`
+			SearchInfo struct {
+				Suggestion string `json:"suggestion"`
+			} `json:"searchinfo"`
+		} `json:"query"`
+		Continue struct {
+			SrOffset int `json:"sroffset"`
+		} `json:"continue"`
+		Error struct {
+			Code string `json:"code"`
+			Info string `json:"info"`
+		} `json:"error"`
+	}
+	// A MediaWiki error, e.g. readapidenied of a private wiki, comes with
+	// status 200 and an error object instead of the query
+	if err := json.Unmarshal(body, &result); err != nil && status == http.StatusOK {
+		return searchPage{}, err
+	}
+	switch {
+	case result.Error.Code != "":
+		return searchPage{}, fmt.Errorf("search API error %s: %s", result.Error.Code, result.Error.Info)
+	case status != http.StatusOK:
+		return searchPage{}, fmt.Errorf("search API returned %d %s", status, http.StatusText(status))
+	}
+
+	titles := make([]string, len(result.Query.Search))
+	for i, item := range result.Query.Search {
+		titles[i] = item.Title
+	}
+	suggestion, next := result.Query.SearchInfo.Suggestion, result.Continue.SrOffset
+	return searchPage{titles: titles, suggestion: suggestion, next: next}, nil
+}
+
+// Summary returns the summary of the article, from the cache if possible.
+// The boolean reports whether it came from the cache.
+func (c *Client) Summary(ctx context.Context, title string) (CacheEntry, bool, error) {
+	// Try to get the entry from the cache first
+	if entry, found := c.Cached(title); found {
+		if entry.NotFound {
+			return CacheEntry{}, true, ErrArticleNotFound
+		}
+		return entry, true, nil
+	}
+
+	return c.FetchSummary(ctx, title)
+}
+
+// FetchSummary always requests the summary from the API, without looking
+// at the cache first, and caches the result. If an expired entry with an
+// ETag is cached, the request is conditional and a 304 Not Modified
+// response renews the cached entry without downloading it again.
+func (c *Client) FetchSummary(ctx context.Context, title string) (CacheEntry, bool, error) {
+	requestURL := c.SummaryURL(title)
+	header := make(http.Header)
+	stale, hasStale := c.staleEntry(title)
+	if hasStale {
+		header.Set("If-None-Match", stale.ETag)
+	}
+
+	start := time.Now()
+	body, response, err := c.do(ctx, requestURL, header)
+	c.track(PhaseSummary, start)
+	if err != nil {
+		return CacheEntry{}, false, err
+	}
+	if response.StatusCode == http.StatusOK {
+		c.saveRaw("summary", title, body)
+	}
+
+	switch response.StatusCode {
+	case http.StatusNotModified:
+		if hasStale {
+			c.log.Debug("revalidated cache entry", "title", title, "etag", stale.ETag)
+			if stale.Canonical != "" {
+				title = stale.Canonical
+			}
+			c.store(title, stale)
+			return stale, true, nil
+		}
+	}
+	// Not every project and language serves the REST summary endpoint, the
+	// extracts API also answers for missing articles and caches them
+	if response.StatusCode >= 400 {
+		c.log.Info("summary endpoint failed, using the extracts API", "title", title, "status", response.StatusCode)
+		return c.fetchExtract(ctx, title)
+	}
+	c.log.Debug("summary from the REST endpoint", "title", title)
+
+	entry, err := parseSummary(body)
+	if err != nil {
+		return CacheEntry{}, false, err
+	}
+	c.ensureURL(&entry, title)
+	entry.ETag = response.Header.Get("ETag")
+
+	// Cache the new entry under the canonical title of the API. For a
+	// redirect the requested title only points to it, so the content is
+	// not stored twice.
+	if entry.Canonical != "" {
+		if c.key(title) != c.key(entry.Canonical) {
+			c.log.Debug("redirect", "from", title, "to", entry.Canonical)
+			c.store(title, CacheEntry{RedirectTo: entry.Canonical})
+		}
+		title = entry.Canonical
+	}
+	c.store(title, entry)
+
+	return entry, false, nil
+}
+
+// staleEntry returns the cached entry for the title, even if it has
+// expired, when it can be revalidated with its ETag.
+func (c *Client) staleEntry(title string) (CacheEntry, bool) {
+	if c.cache == nil {
+		return CacheEntry{}, false
+	}
+	defer c.track(PhaseCacheRead, time.Now())
+	entry, found := c.cache.GetStale(c.key(title))
+	if found && entry.RedirectTo != "" {
+		entry, found = c.cache.GetStale(c.key(entry.RedirectTo))
+	}
+	if !found || entry.NotFound || entry.ETag == "" {
+		return CacheEntry{}, false
+	}
+	return entry, true
+}
+
+// Cached returns the entry for the title from the cache, if it has not
+// expired yet. Cached redirects are followed.
+func (c *Client) Cached(title string) (CacheEntry, bool) {
+	if c.cache == nil {
+		return CacheEntry{}, false
+	}
+	defer c.track(PhaseCacheRead, time.Now())
+	entry, found := c.cache.GetKey(c.key(title))
+	if found && c.pin && !entry.Pinned && !entry.NotFound {
+		c.Pin(title, true)
+	}
+	if found && entry.RedirectTo != "" {
+		return c.cache.GetKey(c.key(entry.RedirectTo))
+	}
+	return entry, found
+}
+
+// Stale returns the cached entry for the title even if it has expired,
+// marked as Stale, for when a fresh one cannot be fetched. Cached
+// redirects are followed, titles without an article are not returned.
+func (c *Client) Stale(title string) (CacheEntry, bool) {
+	if c.cache == nil {
+		return CacheEntry{}, false
+	}
+	defer c.track(PhaseCacheRead, time.Now())
+	entry, found := c.cache.GetStale(c.key(title))
+	if found && entry.RedirectTo != "" {
+		entry, found = c.cache.GetStale(c.key(entry.RedirectTo))
+	}
+	if !found || entry.NotFound {
+		return CacheEntry{}, false
+	}
+	entry.Stale = true
+	return entry, true
+}
+
+// Pin pins or unpins the cached article, following a cached redirect to
+// the entry that holds the content. It reports whether the article is
+// cached.
+func (c *Client) Pin(title string, pinned bool) bool {
+	if c.cache == nil {
+		return false
+	}
+	entry, found := c.cache.GetStale(c.key(title))
+	if !found {
+		return false
+	}
+	c.cache.SetPinned(c.key(title), pinned)
+	if entry.RedirectTo != "" {
+		return c.cache.SetPinned(c.key(entry.RedirectTo), pinned)
+	}
+	return true
+}
+
+func (c *Client) store(title string, entry CacheEntry) {
+	c.storeKey(c.key(title), entry)
+}
+
+// storeKey caches the entry under the full key, applying the TTL, pinning
+// and size limit of the client like for titles.
+func (c *Client) storeKey(key string, entry CacheEntry) {
+	if c.cache != nil {
+		defer c.track(PhaseCacheWrite, time.Now())
+		switch {
+		case entry.NotFound:
+		case c.ttl > 0:
+			entry.CustomTTL = c.ttl
+		case c.adaptiveMax > 0 && !entry.Modified.IsZero():
+			entry.CustomTTL = AdaptiveTTL(time.Since(entry.Modified), c.adaptiveMin, c.adaptiveMax)
+		}
+		if c.pin && !entry.NotFound {
+			entry.Pinned = true
+		}
+		if len(entry.Summary) > MaxCachedSize {
+			c.log.Info("entry too large to cache", "key", key, "bytes", len(entry.Summary))
+			return
+		}
+		c.cache.SetKey(key, entry)
+	}
+}
+
+// key returns the cache key of the title for the language and project of
+// the client.
+func (c *Client) key(title string) string {
+	return ProjectCacheKey(c.project, c.lang, title)
+}
+
+// get requests the URL and returns the body and the status code.
+func (c *Client) get(ctx context.Context, requestURL string) ([]byte, int, error) {
+	body, response, err := c.do(ctx, requestURL, nil)
+	if err != nil {
+		// A response cut off at the limit keeps the part that was read
+		if response != nil {
+			return body, response.StatusCode, err
+		}
+		return nil, 0, err
+	}
+	return body, response.StatusCode, nil
+}
+
+// do requests the URL with the extra header and returns the body and the
+// response, whose body is already closed.
+func (c *Client) do(ctx context.Context, requestURL string, header http.Header) ([]byte, *http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	if c.authorization != "" {
+		request.Header.Set("Authorization", c.authorization)
+	}
+	// Asking for gzip explicitly turns off the transparent decompression of
+	// the transport, readBody decompresses the response instead
+	request.Header.Set("Accept-Encoding", "gzip")
+	c.log.Info("request", "url", requestURL)
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		c.log.Info("request failed", "url", requestURL, "error", err)
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+	c.log.Debug("response", "url", requestURL, "status", response.StatusCode)
+	// The body of a 429 is not the requested document, it is only drained
+	// so the connection can be reused
+	if response.StatusCode == http.StatusTooManyRequests {
+		io.Copy(io.Discard, response.Body)
+		err := rateLimitError(response, time.Now())
+		c.log.Info("rate limited", "url", requestURL, "retry_after", err.RetryAfter)
+		return nil, response, err
+	}
+
+	body, err := readBody(response, c.maxBytes)
+	if err != nil {
+		return body, response, err
+	}
+	return body, response, nil
+}
+
+// readBody reads the response body up to limit and decompresses it if the
+// server sent it gzip encoded.
+func readBody(response *http.Response, limit int64) ([]byte, error) {
+	if !strings.EqualFold(response.Header.Get("Content-Encoding"), "gzip") || response.Uncompressed {
+		return readLimited(response.Body, limit)
+	}
+	reader, err := gzip.NewReader(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return readLimited(reader, limit)
+}
+
+// contentURL returns the desktop URL of the summary, empty if the
+// response has none, as for some special pages.
+func contentURL(result map[string]interface{}) string {
+	urls, ok := result["content_urls"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	desktop, ok := urls["desktop"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	page, _ := desktop["page"].(string)
+	return page
+}
+
+// ensureURL builds the URL of an entry without content_urls from its
+// title, falling back to the requested title.
+func (c *Client) ensureURL(entry *CacheEntry, title string) {
+	if entry.URL != "" {
+		return
+	}
+	if entry.Canonical != "" {
+		title = entry.Canonical
+	} else if entry.Title != "" {
+		title = entry.Title
+	}
+	entry.URL = c.ArticleURL(title)
+	c.log.Info("summary without content_urls, using a URL built from the title", "title", title, "url", entry.URL)
+}
+
+// parseSummary decodes a REST summary response into a cache entry.
+func parseSummary(body []byte) (CacheEntry, error) {
+	var result map[string]interface{}
+	err := json.Unmarshal(body, &result)
+	if err != nil {
+		return CacheEntry{}, err
+	}
+
+	// Entities are decoded once here, cached entries are stored decoded.
+	// Stub and list pages may come without an extract.
+	extract, _ := result["extract"].(string)
+	summary := html.UnescapeString(extract)
+	entry := CacheEntry{
+		Summary: summary,
+		URL:     contentURL(result),
+	}
+
+	if titles, ok := result["titles"].(map[string]interface{}); ok {
+		if canonical, ok := titles["canonical"].(string); ok {
+			entry.Canonical = canonical
+		}
+		if normalized, ok := titles["normalized"].(string); ok {
+			entry.Title = normalized
+		}
+	}
+	if entry.Title == "" {
+		if title, ok := result["title"].(string); ok {
+			entry.Title = title
+		}
+	}
+
+	if description, ok := result["description"].(string); ok {
+		entry.Description = html.UnescapeString(description)
+	}
+	if strings.TrimSpace(entry.Summary) == "" {
+		entry.Summary = entry.Description
+	}
+
+	if pageID, ok := result["pageid"].(float64); ok {
+		entry.PageID = int(pageID)
+	}
+	// The REST API sends the revision as a string
+	switch revision := result["revision"].(type) {
+	case string:
+		entry.Revision, _ = strconv.ParseInt(revision, 10, 64)
+	case float64:
+		entry.Revision = int64(revision)
+	}
+
+	// The timestamp of the latest revision
+	if timestamp, ok := result["timestamp"].(string); ok {
+		if modified, err := time.Parse(time.RFC3339, timestamp); err == nil {
+			entry.Modified = modified
+		}
+	}
+
+	// Only articles about places carry coordinates
+	if coords, ok := result["coordinates"].(map[string]interface{}); ok {
+		lat, latOK := coords["lat"].(float64)
+		lon, lonOK := coords["lon"].(float64)
+		if latOK && lonOK {
+			entry.Coordinates = &Coordinates{Lat: lat, Lon: lon}
+		}
+	}
+
+	return entry, nil
+}
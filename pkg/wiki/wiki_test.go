@@ -0,0 +1,487 @@
+package wiki
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearch(t *testing.T) {
+	results, err := newStubWiki(t).client("de").Search(context.Background(), "Berlin")
+
+	if err != nil {
+		t.Errorf("Search sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	if len(results) == 0 {
+		t.Error("Search sollte Ergebnisse für 'Berlin' zurückgeben")
+	}
+
+	foundBerlin := false
+	for _, result := range results {
+		if result == "Berlin" {
+			foundBerlin = true
+			break
+		}
+	}
+
+	if !foundBerlin {
+		t.Error("'Berlin' sollte in den Suchergebnissen enthalten sein")
+	}
+}
+
+func TestSummary(t *testing.T) {
+	stub := newStubWiki(t)
+	client := stub.client("de", WithCache(newTestCache(t)))
+	entry, cached, err := client.Summary(context.Background(), "Berlin")
+
+	if err != nil {
+		t.Errorf("Summary sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	if entry.Summary == "" {
+		t.Error("Die Zusammenfassung sollte nicht leer sein")
+	}
+
+	if entry.URL == "" {
+		t.Error("Die URL sollte nicht leer sein")
+	}
+
+	if cached {
+		t.Error("Der erste Aufruf sollte nicht aus dem Cache kommen")
+	}
+
+	// Zweiter Aufruf sollte aus dem Cache kommen
+	_, cached, _ = client.Summary(context.Background(), "Berlin")
+	if !cached {
+		t.Error("Der zweite Aufruf sollte aus dem Cache kommen")
+	}
+	if stub.Requests() != 1 {
+		t.Errorf("Es sollte genau eine Anfrage geben, erhalten %d", stub.Requests())
+	}
+}
+
+func TestSearchWithStubServer(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("srsearch")
+		fmt.Fprint(w, `{"query": {"search": [{"title": "Albert Einstein"}, {"title": "Einstein (Begriffsklärung)"}]}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("de", WithHost(server.URL+"/%s"))
+	titles, err := client.Search(context.Background(), "Albert Einstein")
+	if err != nil {
+		t.Fatalf("Search sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	// Der Suchbegriff wird vom Client kodiert
+	if query != "Albert Einstein" {
+		t.Errorf("Erwarteter Suchbegriff 'Albert Einstein', erhielt '%s'", query)
+	}
+	if len(titles) != 2 || titles[0] != "Albert Einstein" {
+		t.Errorf("Unerwartete Ergebnisse: %v", titles)
+	}
+}
+
+func TestSearchAPIError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"readapidenied", http.StatusOK, `{"error": {"code": "readapidenied", "info": "You need read permission to use this module."}}`, "readapidenied: You need read permission"},
+		{"Serverfehler", http.StatusInternalServerError, `<html>Internal Server Error</html>`, "500 Internal Server Error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			titles, err := NewClient("de", WithHost(server.URL+"/%s")).Search(context.Background(), "Berlin")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Erwartete einen Fehler mit %q, erhielt %v", tt.want, err)
+			}
+			if titles != nil {
+				t.Errorf("Es sollte keine Ergebnisse geben: %v", titles)
+			}
+		})
+	}
+}
+
+func TestSummaryNotFoundIsCached(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithCache(newTestCache(t)))
+	if _, _, err := client.Summary(context.Background(), "Gibtesnicht"); !errors.Is(err, ErrArticleNotFound) {
+		t.Errorf("Erwartete ErrArticleNotFound, erhielt %v", err)
+	}
+
+	// Der negative Eintrag sollte ohne Netzwerkzugriff zu "nicht gefunden" führen
+	_, cached, err := client.Summary(context.Background(), "Gibtesnicht")
+	if !errors.Is(err, ErrArticleNotFound) {
+		t.Errorf("Erwartete ErrArticleNotFound, erhielt %v", err)
+	}
+	// Die erste Suche fragt die Summary- und die Extracts-API
+	if !cached || requests != 2 {
+		t.Errorf("Der negative Eintrag sollte aus dem Cache kommen, %d Anfragen", requests)
+	}
+}
+
+func TestSummaryWithoutCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"title": "Berlin", "extract": "Berlin ist eine Stadt.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("de", WithHost(server.URL+"/%s"))
+	for i := 0; i < 2; i++ {
+		if _, cached, err := client.Summary(context.Background(), "Berlin"); err != nil || cached {
+			t.Fatalf("Ohne Cache sollte jeder Aufruf das Netzwerk nutzen: cached=%v, err=%v", cached, err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Erwartete 2 Anfragen, erhielt %d", requests)
+	}
+}
+
+func TestSummaryCanceled(t *testing.T) {
+	// Der Stub antwortet erst, wenn der Test beendet ist
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	client := NewClient("de", WithHost(server.URL+"/%s"))
+	if _, _, err := client.Summary(ctx, "Berlin"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Erwartete context.Canceled, erhielt %v", err)
+	}
+}
+
+func TestParseSummaryCoordinates(t *testing.T) {
+	body := []byte(`{
+		"title": "Berlin",
+		"extract": "Berlin ist die Hauptstadt der Bundesrepublik Deutschland.",
+		"coordinates": {"lat": 52.516666666666666, "lon": 13.383333333333333},
+		"content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}
+	}`)
+
+	entry, err := parseSummary(body)
+	if err != nil {
+		t.Fatalf("parseSummary sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	if entry.Coordinates == nil {
+		t.Fatal("Für Berlin sollten Koordinaten geparst werden")
+	}
+
+	if entry.Coordinates.Lat != 52.516666666666666 || entry.Coordinates.Lon != 13.383333333333333 {
+		t.Errorf("Unerwartete Koordinaten: %v", *entry.Coordinates)
+	}
+}
+
+func TestParseSummaryWithoutCoordinates(t *testing.T) {
+	body := []byte(`{
+		"title": "Golang",
+		"extract": "Go ist eine Programmiersprache.",
+		"content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Go_(Programmiersprache)"}}
+	}`)
+
+	entry, err := parseSummary(body)
+	if err != nil {
+		t.Fatalf("parseSummary sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	if entry.Coordinates != nil {
+		t.Errorf("Ohne Koordinatenfeld sollten keine Koordinaten gesetzt sein, erhielt %v", *entry.Coordinates)
+	}
+}
+
+func TestParseSummaryDescription(t *testing.T) {
+	body := []byte(`{
+		"title": "Berlin",
+		"description": "Hauptstadt der Bundesrepublik Deutschland",
+		"extract": "Berlin ist die Hauptstadt der Bundesrepublik Deutschland.",
+		"content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}
+	}`)
+
+	entry, err := parseSummary(body)
+	if err != nil {
+		t.Fatalf("parseSummary sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	if entry.Description != "Hauptstadt der Bundesrepublik Deutschland" {
+		t.Errorf("Erwartete Beschreibung 'Hauptstadt der Bundesrepublik Deutschland', erhielt '%s'", entry.Description)
+	}
+}
+
+func TestParseSummaryEmptyExtract(t *testing.T) {
+	body := []byte(`{
+		"title": "Liste der Städte in Deutschland",
+		"description": "Wikimedia-Liste",
+		"extract": "",
+		"content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Liste_der_St%C3%A4dte_in_Deutschland"}}
+	}`)
+
+	entry, err := parseSummary(body)
+	if err != nil {
+		t.Fatalf("parseSummary sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if entry.Summary != "Wikimedia-Liste" {
+		t.Errorf("Ohne Extrakt sollte die Beschreibung verwendet werden, erhielt '%s'", entry.Summary)
+	}
+
+	// Ganz ohne Extrakt und Beschreibung bleibt die Zusammenfassung leer
+	body = []byte(`{"title": "Stub", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Stub"}}}`)
+	entry, err = parseSummary(body)
+	if err != nil {
+		t.Fatalf("parseSummary sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if entry.Summary != "" || entry.URL == "" {
+		t.Errorf("Erwartete leere Zusammenfassung mit URL, erhielt %+v", entry)
+	}
+}
+
+func TestParseSummaryUnescapesEntities(t *testing.T) {
+	body := []byte(`{
+		"title": "Tom & Jerry",
+		"description": "Zeichentrickserie von Hanna &amp; Barbera",
+		"extract": "Tom &amp; Jerry ist eine Zeichentrickserie.&nbsp;Sie l&auml;uft seit 1940.",
+		"content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Tom_und_Jerry"}}
+	}`)
+
+	entry, err := parseSummary(body)
+	if err != nil {
+		t.Fatalf("parseSummary sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	want := "Tom & Jerry ist eine Zeichentrickserie.\u00a0Sie läuft seit 1940."
+	if entry.Summary != want {
+		t.Errorf("Erwartete '%s', erhielt '%s'", want, entry.Summary)
+	}
+
+	if entry.Description != "Zeichentrickserie von Hanna & Barbera" {
+		t.Errorf("Erwartete dekodierte Beschreibung, erhielt '%s'", entry.Description)
+	}
+}
+
+func TestParseSummaryTitles(t *testing.T) {
+	body := []byte(`{
+		"title": "Albert_Einstein",
+		"titles": {"canonical": "Albert_Einstein", "normalized": "Albert Einstein", "display": "<span>Albert Einstein</span>"},
+		"extract": "Albert Einstein war ein Physiker.",
+		"content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Albert_Einstein"}}
+	}`)
+
+	entry, err := parseSummary(body)
+	if err != nil {
+		t.Fatalf("parseSummary sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	if entry.Canonical != "Albert_Einstein" {
+		t.Errorf("Erwarteter kanonischer Titel 'Albert_Einstein', erhielt '%s'", entry.Canonical)
+	}
+	if entry.Title != "Albert Einstein" {
+		t.Errorf("Erwarteter Titel 'Albert Einstein', erhielt '%s'", entry.Title)
+	}
+}
+
+func TestClientLogsRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithLogger(log))
+	client.Summary(context.Background(), "Gibtesnicht")
+
+	if !strings.Contains(buf.String(), "msg=request") || !strings.Contains(buf.String(), "status=404") {
+		t.Errorf("Die Anfrage sollte protokolliert werden, erhielt %q", buf.String())
+	}
+}
+
+func TestSummaryWithoutContentURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"type": "no-extract", "title": "Spezial:Zufällige Seite", "titles": {"canonical": "Spezial:Zufällige_Seite"}, "extract": ""}`)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, nil))
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithLogger(log))
+	entry, _, err := client.Summary(context.Background(), "Spezial:Zufällige Seite")
+	if err != nil {
+		t.Fatalf("Summary sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if want := server.URL + "/de/wiki/Spezial:Zuf%C3%A4llige_Seite"; entry.URL != want {
+		t.Errorf("Erwartete die URL %q, erhielt %q", want, entry.URL)
+	}
+	if !strings.Contains(buf.String(), "without content_urls") {
+		t.Errorf("Das Log sollte die gebildete URL erwähnen: %q", buf.String())
+	}
+
+	// Auch unvollständige content_urls führen nicht zu einem Absturz
+	for _, body := range []string{`{"content_urls": null}`, `{"content_urls": {"mobile": {}}}`, `{"content_urls": {"desktop": {"page": 1}}}`} {
+		if entry, err := parseSummary([]byte(body)); err != nil || entry.URL != "" {
+			t.Errorf("%s: erwartete eine leere URL, erhielt %q, %v", body, entry.URL, err)
+		}
+	}
+}
+
+func TestSummaryGzipResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("Die Anfrage sollte gzip anbieten, erhielt '%s'", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		writer := gzip.NewWriter(w)
+		fmt.Fprint(writer, `{"title": "Berlin", "extract": "Berlin ist die Hauptstadt.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+		writer.Close()
+	}))
+	defer server.Close()
+
+	// Auch ein Client ohne automatische Dekomprimierung liest den Inhalt
+	httpClient := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithHTTPClient(httpClient))
+	entry, _, err := client.Summary(context.Background(), "Berlin")
+	if err != nil {
+		t.Fatalf("Summary sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if entry.Summary != "Berlin ist die Hauptstadt." {
+		t.Errorf("Unerwartete Zusammenfassung: '%s'", entry.Summary)
+	}
+}
+
+func TestWithTTLStoresCustomTTL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"title": "Berlin", "titles": {"canonical": "Berlin"}, "extract": "Berlin ist die Hauptstadt Deutschlands.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+	}))
+	defer server.Close()
+
+	fileCache := newTestCache(t)
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithCache(fileCache), WithTTL(time.Hour))
+	if _, _, err := client.Summary(context.Background(), "Berlin"); err != nil {
+		t.Fatalf("Summary sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	entry, found := fileCache.Get("de", "Berlin")
+	if !found {
+		t.Fatal("Der Eintrag sollte im Cache liegen")
+	}
+	if entry.TTL() != time.Hour {
+		t.Errorf("Die TTL sollte 1h sein, erhielt %v", entry.TTL())
+	}
+}
+
+func TestParseSummaryTimestamp(t *testing.T) {
+	body := []byte(`{
+		"title": "Berlin",
+		"extract": "Berlin ist die Hauptstadt Deutschlands.",
+		"timestamp": "2024-05-01T12:34:56Z",
+		"content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}
+	}`)
+
+	entry, err := parseSummary(body)
+	if err != nil {
+		t.Fatalf("parseSummary sollte keinen Fehler zurückgeben: %v", err)
+	}
+	want := time.Date(2024, 5, 1, 12, 34, 56, 0, time.UTC)
+	if !entry.Modified.Equal(want) {
+		t.Errorf("Modified = %v, erwartet %v", entry.Modified, want)
+	}
+
+	// Ein ungültiger Zeitstempel wird ignoriert
+	body = []byte(`{"title": "Berlin", "timestamp": "gestern", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+	entry, err = parseSummary(body)
+	if err != nil {
+		t.Fatalf("parseSummary sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if !entry.Modified.IsZero() {
+		t.Errorf("Modified sollte leer sein, erhielt %v", entry.Modified)
+	}
+}
+
+func TestSearchWithSuggestion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"query": {"searchinfo": {"totalhits": 1, "suggestion": "albert einstein"}, "search": [{"title": "Einsteinturm"}]}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("de", WithHost(server.URL+"/%s"))
+	result, err := client.SearchWithSuggestion(context.Background(), "albert einstien")
+	if err != nil {
+		t.Fatalf("SearchWithSuggestion sollte keinen Fehler zurückgeben: %v", err)
+	}
+	if result.Suggestion != "albert einstein" {
+		t.Errorf("Erwarteter Vorschlag 'albert einstein', erhielt '%s'", result.Suggestion)
+	}
+	if len(result.Titles) != 1 || result.Titles[0] != "Einsteinturm" {
+		t.Errorf("Unerwartete Titel: %v", result.Titles)
+	}
+}
+
+func TestWithPinPinsArticleAndRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"title": "Berlin", "titles": {"canonical": "Berlin"}, "extract": "Berlin ist die Hauptstadt Deutschlands.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+	}))
+	defer server.Close()
+
+	fileCache := newTestCache(t)
+	client := NewClient("de", WithHost(server.URL+"/%s"), WithCache(fileCache), WithPin())
+	if _, _, err := client.Summary(context.Background(), "Hauptstadt von Deutschland"); err != nil {
+		t.Fatalf("Summary sollte keinen Fehler zurückgeben: %v", err)
+	}
+	for _, key := range []string{"de:Hauptstadt_von_Deutschland", "de:Berlin"} {
+		if entry, _ := fileCache.GetStale(key); !entry.Pinned {
+			t.Errorf("%s sollte angepinnt sein", key)
+		}
+	}
+
+	unpinned := NewClient("de", WithCache(fileCache))
+	if !unpinned.Pin("Hauptstadt von Deutschland", false) {
+		t.Fatal("Pin sollte den Artikel finden")
+	}
+	if entry, _ := fileCache.GetStale("de:Berlin"); entry.Pinned {
+		t.Error("Pin sollte der Weiterleitung zum Artikel folgen")
+	}
+	if unpinned.Pin("Paris", true) {
+		t.Error("Pin sollte für nicht gecachte Artikel false liefern")
+	}
+
+	// A cached article is pinned when it is read with WithPin
+	if _, cached, _ := client.Summary(context.Background(), "Berlin"); !cached {
+		t.Error("Berlin sollte aus dem Cache kommen")
+	}
+	if entry, _ := fileCache.GetStale("de:Berlin"); !entry.Pinned {
+		t.Error("Ein gelesener Artikel sollte mit WithPin angepinnt werden")
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// profiler sums up how long each phase of a lookup took for -profile.
+type profiler struct {
+	mu      sync.Mutex
+	start   time.Time
+	phases  []string
+	elapsed map[string]time.Duration
+}
+
+// timings is set while -profile is active and nil otherwise.
+var timings *profiler
+
+func newProfiler() *profiler {
+	return &profiler{start: time.Now(), elapsed: make(map[string]time.Duration)}
+}
+
+// record adds the duration to the phase. It is safe for concurrent use,
+// batch lookups run in parallel.
+func (p *profiler) record(phase string, elapsed time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, seen := p.elapsed[phase]; !seen {
+		p.phases = append(p.phases, phase)
+	}
+	p.elapsed[phase] += elapsed
+}
+
+// print writes the phases in the order they first occurred, followed by
+// the total time since the profiler was created.
+func (p *profiler) print(w io.Writer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintln(w, "\nProfile:")
+	for _, phase := range p.phases {
+		fmt.Fprintf(w, "  %-16s %v\n", phase, p.elapsed[phase].Round(time.Microsecond))
+	}
+	fmt.Fprintf(w, "  %-16s %v\n", "total", time.Since(p.start).Round(time.Microsecond))
+}
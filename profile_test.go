@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestProfileTimings(t *testing.T) {
+	setTestHome(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("list") == "search" {
+			fmt.Fprint(w, `{"query": {"search": [{"title": "Berlin"}]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"title": "Berlin", "titles": {"canonical": "Berlin"}, "extract": "Berlin ist die Hauptstadt Deutschlands.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+	}))
+	defer server.Close()
+
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	var code int
+	_, stderr := captureOutput(t, func() {
+		code = run([]string{"-profile", "Berlin"})
+	})
+	if code != exitOK {
+		t.Fatalf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+
+	for _, phase := range []string{wiki.PhaseSearch, wiki.PhaseSummary, wiki.PhaseCacheRead, wiki.PhaseCacheWrite, "total"} {
+		line := profileLine(stderr, phase)
+		if line == "" {
+			t.Errorf("Die Zeitmessung für %q fehlt: %q", phase, stderr)
+			continue
+		}
+		elapsed, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(line, phase)))
+		if err != nil || elapsed < 0 {
+			t.Errorf("Ungültige Dauer in %q: %v", line, err)
+		}
+	}
+	if timings != nil {
+		t.Error("Der Profiler sollte nach run zurückgesetzt werden")
+	}
+}
+
+// profileLine returns the trimmed line of the profile for the phase.
+func profileLine(output, phase string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, phase+" ") {
+			return line
+		}
+	}
+	return ""
+}
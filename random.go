@@ -0,0 +1,21 @@
+package main
+
+import (
+	"context"
+	"os"
+)
+
+// runRandom prints the summary of a random article, for -random.
+func runRandom(ctx context.Context, lang string, output OutputWriter, copy bool) int {
+	stopLoading := startLoadingAnimation()
+	entry, err := newClient(lang).Random(ctx)
+	if err == nil {
+		entry = formatEntryURL(ctx, lang, entry)
+	}
+	stopLoading()
+	if err != nil {
+		activeTheme.Error.Fprintf(os.Stderr, "Error fetching random article: %v\n", err)
+		return exitCodeFor(err)
+	}
+	return showEntry(lang, entry, false, output, copy)
+}
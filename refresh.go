@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+// refreshTarget is a cached summary that -refresh fetches again.
+type refreshTarget struct {
+	Key   string
+	Lang  string
+	Title string
+	Old   wiki.CacheEntry
+}
+
+// refreshResult is the outcome of fetching one target again. Skipped
+// targets were not fetched because Wikipedia started rate limiting.
+type refreshResult struct {
+	Target  refreshTarget
+	Changed bool
+	Skipped bool
+	Err     error
+}
+
+// refreshTargets returns the cached summaries of the project, sorted by
+// key. Redirects, titles without an article, sections, page IDs and
+// language versions are left out, the lookups that need them renew them.
+func refreshTargets(cache wiki.Cache, project string) []refreshTarget {
+	prefix := ""
+	if project != "" && project != wiki.DefaultProject {
+		prefix = project + ":"
+	}
+	var targets []refreshTarget
+	for key, entry := range cache {
+		if entry.NotFound || entry.RedirectTo != "" || strings.HasPrefix(key, "pageid:") || strings.HasPrefix(key, "langlinks:") {
+			continue
+		}
+		rest, found := strings.CutPrefix(key, prefix)
+		if !found {
+			continue
+		}
+		lang, title, found := strings.Cut(rest, ":")
+		// Keys of other projects start with the project instead of a language
+		if !found || containsString(wiki.Projects, lang) || strings.Contains(title, "#") {
+			continue
+		}
+		targets = append(targets, refreshTarget{Key: key, Lang: lang, Title: strings.ReplaceAll(title, "_", " "), Old: entry})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Key < targets[j].Key
+	})
+	return targets
+}
+
+// refreshEntries fetches the targets again with a bounded number of
+// workers and calls progress after each one that was fetched, from one
+// goroutine at a time. Once Wikipedia rate limits a request, the targets
+// not started yet are skipped. The results are in the order of targets.
+func refreshEntries(ctx context.Context, targets []refreshTarget, workers int, fetch summaryFetcher, progress func(done int, result refreshResult)) []refreshResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([]refreshResult, len(targets))
+	jobs := make(chan int)
+	var mu sync.Mutex
+	done := 0
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := refreshResult{Target: targets[i]}
+				if ctx.Err() != nil {
+					result.Skipped = true
+				} else {
+					entry, _, err := fetch(ctx, targets[i].Lang, targets[i].Title)
+					switch {
+					case errors.Is(err, wiki.ErrRateLimited):
+						cancel()
+						result.Err = err
+					case err != nil && ctx.Err() != nil:
+						result.Skipped = true
+					default:
+						result.Err = err
+						result.Changed = err == nil && entry.Summary != targets[i].Old.Summary
+					}
+				}
+				results[i] = result
+				if !result.Skipped {
+					mu.Lock()
+					done++
+					progress(done, result)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range targets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// runRefresh fetches every cached summary of the project again, for
+// -refresh. Each entry is reported as it is done, followed by the totals.
+func runRefresh(ctx context.Context, workers int, out io.Writer) int {
+	targets := refreshTargets(fileCache().Load(), project)
+	if len(targets) == 0 {
+		fmt.Fprintln(out, "No cached summaries to refresh.")
+		return exitOK
+	}
+
+	progress := func(done int, result refreshResult) {
+		status := "unchanged"
+		switch {
+		case result.Err != nil:
+			status = activeTheme.Error.Sprintf("error: %v", result.Err)
+		case result.Changed:
+			status = "changed"
+		}
+		fmt.Fprintf(out, "[%d/%d] %s: %s\n", done, len(targets), result.Target.Key, status)
+	}
+	byTitle := make(map[string]refreshTarget, len(targets))
+	for _, target := range targets {
+		byTitle[target.Lang+":"+target.Title] = target
+	}
+	fetch := func(ctx context.Context, lang, title string) (wiki.CacheEntry, bool, error) {
+		entry, cached, err := fetchWikipediaSummary(ctx, lang, title)
+		// A TTL set with -pin-ttl belongs to the entry, the refreshed one
+		// keeps it unless it got a TTL of its own
+		if old := byTitle[lang+":"+title].Old; err == nil && old.CustomTTL > 0 {
+			fileCache().Update(byTitle[lang+":"+title].Key, func(entry *wiki.CacheEntry) {
+				if entry.CustomTTL == 0 {
+					entry.CustomTTL = old.CustomTTL
+				}
+			})
+		}
+		return entry, cached, err
+	}
+	previous := spinnerEnabled
+	spinnerEnabled = false
+	results := refreshEntries(ctx, targets, clampConcurrency(workers), fetch, progress)
+	spinnerEnabled = previous
+
+	code := exitOK
+	var changed, unchanged, failed, skipped int
+	rateLimited := false
+	for _, result := range results {
+		switch {
+		case result.Skipped:
+			skipped++
+		case result.Err != nil:
+			failed++
+			rateLimited = rateLimited || errors.Is(result.Err, wiki.ErrRateLimited)
+			if code == exitOK {
+				code = exitCodeFor(result.Err)
+			}
+		case result.Changed:
+			changed++
+		default:
+			unchanged++
+		}
+	}
+	fmt.Fprintf(out, "Refreshed %d of %d entries: %d changed, %d unchanged, %d failed.\n", changed+unchanged, len(targets), changed, unchanged, failed)
+	if rateLimited && skipped > 0 {
+		fmt.Fprintf(os.Stderr, "Wikipedia is rate limiting requests, %d entries were skipped, run -refresh again later.\n", skipped)
+	}
+	return code
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestRefreshTargets(t *testing.T) {
+	cache := wiki.Cache{
+		"de:Berlin":                 {Title: "Berlin", Summary: "Berlin"},
+		"en:New_York_City":          {Title: "New York City", Summary: "NYC"},
+		"de:Berlin#Geschichte":      {Title: "Berlin", Summary: "Geschichte"},
+		"de:Bärlin":                 {RedirectTo: "Berlin"},
+		"de:Gibtsnicht":             {NotFound: true},
+		"pageid:de:123":             {Title: "Berlin", Summary: "Berlin"},
+		"langlinks:de:Berlin":       {Title: "Berlin"},
+		"wiktionary:en:Serendipity": {Title: "serendipity", Summary: "luck"},
+	}
+
+	targets := refreshTargets(cache, wiki.DefaultProject)
+	var keys []string
+	for _, target := range targets {
+		keys = append(keys, target.Lang+"|"+target.Title)
+	}
+	if got, want := strings.Join(keys, ", "), "de|Berlin, en|New York City"; got != want {
+		t.Errorf("Erwartete %q, erhielt %q", want, got)
+	}
+
+	targets = refreshTargets(cache, "wiktionary")
+	if len(targets) != 1 || targets[0].Lang != "en" || targets[0].Title != "Serendipity" {
+		t.Errorf("Mit -project sollten nur dessen Einträge kommen: %+v", targets)
+	}
+}
+
+func TestRefreshEntriesStopsWhenRateLimited(t *testing.T) {
+	targets := make([]refreshTarget, 10)
+	for i := range targets {
+		targets[i] = refreshTarget{Lang: "de", Title: fmt.Sprintf("Artikel %d", i)}
+	}
+	var mu sync.Mutex
+	fetched := 0
+	fetch := func(ctx context.Context, lang, title string) (wiki.CacheEntry, bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetched++
+		if title == "Artikel 2" {
+			return wiki.CacheEntry{}, false, &wiki.RateLimitError{RetryAfter: time.Minute}
+		}
+		return wiki.CacheEntry{Summary: "neu"}, false, nil
+	}
+
+	var reported int
+	results := refreshEntries(context.Background(), targets, 1, fetch, func(done int, result refreshResult) {
+		reported = done
+	})
+	if fetched != 3 || reported != 3 {
+		t.Errorf("Nach der Drosselung sollte nichts mehr abgefragt werden: %d abgefragt, %d gemeldet", fetched, reported)
+	}
+	if !results[0].Changed || results[2].Err == nil || !results[3].Skipped || !results[9].Skipped {
+		t.Errorf("Unerwartete Ergebnisse: %+v", results)
+	}
+}
+
+func TestRunRefresh(t *testing.T) {
+	setTestHome(t)
+
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/de/api/rest_v1/page/summary/Berlin":
+			fmt.Fprint(w, `{"title": "Berlin", "extract": "Berlin ist die Hauptstadt und hat 3,9 Millionen Einwohner.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+		case "/en/api/rest_v1/page/summary/Paris":
+			fmt.Fprint(w, `{"title": "Paris", "extract": "Paris is the capital of France.", "content_urls": {"desktop": {"page": "https://en.wikipedia.org/wiki/Paris"}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	old := time.Now().Add(-20 * time.Hour)
+	fileCache().Save(wiki.Cache{
+		"de:Berlin":            {Title: "Berlin", Summary: "Berlin ist die Hauptstadt.", Timestamp: old, CustomTTL: 168 * time.Hour},
+		"en:Paris":             {Title: "Paris", Summary: "Paris is the capital of France.", Timestamp: old},
+		"de:Bärlin":            {RedirectTo: "Berlin", Timestamp: old},
+		"de:Berlin#Geschichte": {Title: "Berlin", Summary: "Geschichte", Timestamp: old},
+	})
+
+	var code int
+	stdout, _ := captureOutput(t, func() {
+		code = run([]string{"-refresh"})
+	})
+	if code != exitOK {
+		t.Fatalf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+	for _, want := range []string{"de:Berlin: changed", "en:Paris: unchanged", "Refreshed 2 of 2 entries: 1 changed, 1 unchanged, 0 failed."} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Die Ausgabe sollte %q enthalten: %q", want, stdout)
+		}
+	}
+	if len(requested) != 2 {
+		t.Errorf("Nur die zwei Zusammenfassungen sollten abgefragt werden: %v", requested)
+	}
+
+	entry, found := fileCache().Get("de", "Berlin")
+	if !found || !strings.Contains(entry.Summary, "3,9 Millionen") {
+		t.Errorf("Der Eintrag sollte aktualisiert sein: %+v", entry)
+	}
+	if !entry.Timestamp.After(old) {
+		t.Error("Der Zeitstempel sollte erneuert sein")
+	}
+	if entry.CustomTTL != 168*time.Hour {
+		t.Errorf("Die TTL von -pin-ttl sollte erhalten bleiben, erhalten %v", entry.CustomTTL)
+	}
+	if paris, _ := fileCache().Get("en", "Paris"); paris.CustomTTL != 0 {
+		t.Errorf("Ohne eigene TTL sollte keine gesetzt werden, erhalten %v", paris.CustomTTL)
+	}
+
+	_, stderr := captureOutput(t, func() {
+		code = run([]string{"-refresh", "-no-store"})
+	})
+	if code != exitUsage || !strings.Contains(stderr, "-refresh cannot be combined with -no-store") {
+		t.Errorf("-refresh mit -no-store sollte ein Bedienfehler sein: %d, %q", code, stderr)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxSummaryLength is the hard cut applied when neither -sentences nor
+// -words is given.
+const maxSummaryLength = 1000
+
+// summarySentences limits summaries to this many sentences, set by
+// -sentences. 0 keeps the character cut.
+var summarySentences = 0
+
+// summaryWords limits summaries to this many words, set by -words. 0 keeps
+// the character cut.
+var summaryWords = 0
+
+// abbreviations end with a period that does not end the sentence.
+var abbreviations = map[string]bool{
+	"bzw.": true, "ca.": true, "d.h.": true, "dr.": true, "e.g.": true,
+	"etc.": true, "geb.": true, "gest.": true, "i.e.": true, "jh.": true,
+	"mio.": true, "mr.": true, "mrd.": true, "mrs.": true, "ms.": true,
+	"nr.": true, "prof.": true, "s.": true, "st.": true, "u.a.": true,
+	"usw.": true, "vgl.": true, "vs.": true, "z.b.": true,
+}
+
+// shortenSummary applies -sentences or -words, or the character cut by
+// default.
+func shortenSummary(summary string) string {
+	if summarySentences > 0 || summaryWords > 0 {
+		return shortenText(summary)
+	}
+	if runes := []rune(summary); len(runes) > maxSummaryLength {
+		return string(runes[:maxSummaryLength-3]) + "..."
+	}
+	return summary
+}
+
+// shortenText applies -sentences or -words to a text that is shown in
+// full otherwise, like a section.
+func shortenText(text string) string {
+	if summarySentences > 0 {
+		return firstSentences(text, summarySentences)
+	}
+	if summaryWords > 0 {
+		return firstWords(text, summaryWords)
+	}
+	return text
+}
+
+// firstWords returns the first n words of the text and marks the cut with
+// an ellipsis, which replaces punctuation after the last word.
+func firstWords(text string, n int) string {
+	words := strings.Fields(text)
+	if len(words) <= n {
+		return strings.TrimSpace(text)
+	}
+	return strings.TrimRight(strings.Join(words[:n], " "), ".,;:") + "..."
+}
+
+func firstSentence(text string) string {
+	return firstSentences(text, 1)
+}
+
+// firstSentences returns the first n sentences of the text.
+func firstSentences(text string, n int) string {
+	sentences := splitSentences(text)
+	if len(sentences) <= n {
+		return strings.TrimSpace(text)
+	}
+	return strings.Join(sentences[:n], " ")
+}
+
+// splitSentences splits the text after ".", "!" and "?". Periods after
+// abbreviations, initials and ordinal numbers like "3. Oktober" do not end
+// a sentence, neither does one followed by a lowercase word.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i, r := range text {
+		if r != '.' && r != '!' && r != '?' {
+			continue
+		}
+		end := i + 1
+		// Closing quotes and brackets belong to the sentence
+		for end < len(text) {
+			next, size := utf8.DecodeRuneInString(text[end:])
+			if !strings.ContainsRune(`"')]»“”’`, next) {
+				break
+			}
+			end += size
+		}
+		if end < len(text) && text[end] != ' ' && text[end] != '\n' {
+			continue
+		}
+		if r == '.' && !endsSentence(text[start:i+1], text[end:]) {
+			continue
+		}
+		if sentence := strings.TrimSpace(text[start:end]); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = end
+	}
+	if rest := strings.TrimSpace(text[start:]); rest != "" {
+		sentences = append(sentences, rest)
+	}
+	return sentences
+}
+
+// endsSentence reports whether the period at the end of sentence ends it,
+// given the text that follows.
+func endsSentence(sentence, rest string) bool {
+	word := sentence[strings.LastIndexAny(sentence, " \n(")+1:]
+	if abbreviations[strings.ToLower(word)] {
+		return false
+	}
+
+	stem := strings.TrimSuffix(word, ".")
+	if utf8.RuneCountInString(stem) == 1 && unicode.IsLetter([]rune(stem)[0]) {
+		return false // an initial like "J. R. R. Tolkien" or "z. B."
+	}
+	if len(stem) > 0 && len(stem) <= 2 && strings.Trim(stem, "0123456789") == "" {
+		return false // an ordinal like "3. Oktober"
+	}
+
+	next, _ := utf8.DecodeRuneInString(strings.TrimSpace(rest))
+	return !unicode.IsLower(next) && !unicode.IsDigit(next)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestFirstSentences(t *testing.T) {
+	text := "Berlin ist die Hauptstadt Deutschlands. Die Stadt hat 3,7 Mio. Einwohner! Ist sie die größte Stadt? Ja. Sie liegt an der Spree."
+
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{1, "Berlin ist die Hauptstadt Deutschlands."},
+		{2, "Berlin ist die Hauptstadt Deutschlands. Die Stadt hat 3,7 Mio. Einwohner!"},
+		{3, "Berlin ist die Hauptstadt Deutschlands. Die Stadt hat 3,7 Mio. Einwohner! Ist sie die größte Stadt?"},
+		{10, text},
+	}
+
+	for _, test := range tests {
+		if got := firstSentences(text, test.n); got != test.want {
+			t.Errorf("firstSentences(%d): erwartete\n%q\nerhielt\n%q", test.n, test.want, got)
+		}
+	}
+}
+
+func TestSplitSentencesAbbreviations(t *testing.T) {
+	tests := map[string]int{
+		"Die Mauer fiel am 9. November 1989. Danach kam die Einheit.":               2,
+		"J. R. R. Tolkien schrieb den Herrn der Ringe. Er war Professor.":           2,
+		"Es gibt viele Dialekte, z.B. Berlinisch. Sie sind verbreitet.":             2,
+		"Dr. Müller wohnt in der St. Marienstraße. Er ist Arzt.":                    2,
+		"Sie sagte: „Ja.“ Dann ging sie.":                                           2,
+		"Die Stadt wurde 1237 erstmals erwähnt. Im Jahr 1990 wurde sie Hauptstadt.": 2,
+	}
+
+	for text, want := range tests {
+		if got := splitSentences(text); len(got) != want {
+			t.Errorf("%q: erwartete %d Sätze, erhielt %d: %q", text, want, len(got), got)
+		}
+	}
+}
+
+func TestShortenSummary(t *testing.T) {
+	defer func() { summarySentences = 0 }()
+
+	long := strings.Repeat("Ein Satz. ", 200)
+	if got := shortenSummary(long); len(got) != maxSummaryLength || !strings.HasSuffix(got, "...") {
+		t.Errorf("Ohne -sentences sollte nach %d Zeichen gekürzt werden, erhielt %d", maxSummaryLength, len(got))
+	}
+	if got := shortenSummary(strings.Repeat("ä", 600)); !utf8.ValidString(got) || utf8.RuneCountInString(got) != 600 {
+		t.Errorf("600 Umlaute sollten unverändert bleiben, erhielt %d Zeichen", utf8.RuneCountInString(got))
+	}
+	if got := shortenSummary(strings.Repeat("ä", 1200)); !utf8.ValidString(got) || utf8.RuneCountInString(got) != maxSummaryLength {
+		t.Errorf("Die Kürzung sollte keine Zeichen zerteilen, erhielt %d Zeichen", utf8.RuneCountInString(got))
+	}
+
+	summarySentences = 3
+	if got := shortenSummary(long); got != "Ein Satz. Ein Satz. Ein Satz." {
+		t.Errorf("Erwartete drei Sätze, erhielt %q", got)
+	}
+}
+
+func TestFirstWords(t *testing.T) {
+	text := "Berlin ist die Hauptstadt\nund ein Land der Bundesrepublik Deutschland."
+	for _, n := range []int{1, 3, 7} {
+		got := firstWords(text, n)
+		if !strings.HasSuffix(got, "...") {
+			t.Errorf("firstWords(%d) sollte mit ... enden: %q", n, got)
+		}
+		if words := strings.Fields(strings.TrimSuffix(got, "...")); len(words) != n {
+			t.Errorf("firstWords(%d) sollte genau %d Wörter behalten, erhielt %q", n, n, got)
+		}
+	}
+
+	if got := firstWords(text, 11); got != text {
+		t.Errorf("Ein kurzer Text sollte unverändert bleiben, erhielt %q", got)
+	}
+}
+
+func TestShortenSummaryWords(t *testing.T) {
+	defer func() { summaryWords = 0 }()
+
+	summaryWords = 4
+	if got := shortenSummary(strings.Repeat("Ein Satz. ", 200)); got != "Ein Satz. Ein Satz..." {
+		t.Errorf("Erwartete vier Wörter, erhielt %q", got)
+	}
+}
+
+func TestRunSectionNotCut(t *testing.T) {
+	setTestHome(t)
+
+	text := strings.Repeat("Die Geschichte der Stadt ist lang. ", 70)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("list") == "search":
+			fmt.Fprint(w, `{"query": {"search": [{"title": "Berlin"}]}}`)
+		case query.Get("prop") == "sections":
+			fmt.Fprint(w, `{"parse": {"title": "Berlin", "sections": [{"toclevel": 1, "level": "2", "line": "Geschichte", "number": "1", "index": "1", "anchor": "Geschichte"}]}}`)
+		default:
+			fmt.Fprintf(w, `{"parse": {"title": "Berlin", "text": "<h2>Geschichte</h2><p>%s</p>"}}`, text)
+		}
+	}))
+	defer server.Close()
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	var code int
+	stdout, _ := captureOutput(t, func() {
+		code = run([]string{"-section", "Geschichte", "Berlin"})
+	})
+	if code != exitOK {
+		t.Fatalf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+	// Die Ausgabe wird umbrochen, verglichen werden nur die Wörter
+	if !strings.Contains(strings.Join(strings.Fields(stdout), " "), strings.TrimSpace(text)) {
+		t.Errorf("Der Abschnitt sollte nicht nach %d Zeichen gekürzt werden: %d Bytes", maxSummaryLength, len(stdout))
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+const defaultServeAddr = "localhost:8080"
+
+// searchResponse is the body of GET /search.
+type searchResponse struct {
+	Lang    string   `json:"lang"`
+	Query   string   `json:"query"`
+	Results []string `json:"results"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// newServeMux returns the handlers of the HTTP API. Requests without a
+// lang parameter use defaultLang. With metrics the requests are counted
+// and served on /metrics.
+func newServeMux(defaultLang string, metrics *serverMetrics) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /summary", metrics.instrument("/summary", func(w http.ResponseWriter, r *http.Request) {
+		lang, ok := requestLang(w, r, defaultLang)
+		if !ok {
+			return
+		}
+		title := r.URL.Query().Get("title")
+		if title == "" {
+			writeJSONError(w, http.StatusBadRequest, errors.New("missing parameter: title"))
+			return
+		}
+
+		entry, cached, err := newClient(lang).Summary(r.Context(), title)
+		metrics.observeCache(cached)
+		if err != nil {
+			observeError(metrics, err)
+			writeJSONError(w, statusFor(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, newResult(lang, title, entry, cached))
+	}))
+	mux.HandleFunc("GET /search", metrics.instrument("/search", func(w http.ResponseWriter, r *http.Request) {
+		lang, ok := requestLang(w, r, defaultLang)
+		if !ok {
+			return
+		}
+		query := r.URL.Query().Get("q")
+		if query == "" {
+			writeJSONError(w, http.StatusBadRequest, errors.New("missing parameter: q"))
+			return
+		}
+
+		titles, err := newClient(lang).Search(r.Context(), query)
+		if err != nil {
+			observeError(metrics, err)
+			writeJSONError(w, statusFor(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, searchResponse{Lang: lang, Query: query, Results: titles})
+	}))
+	if metrics != nil {
+		mux.HandleFunc("GET /metrics", metrics.handler)
+	}
+	return mux
+}
+
+// observeError counts err as an upstream error unless Wikipedia simply
+// had no article.
+func observeError(metrics *serverMetrics, err error) {
+	if statusFor(err) == http.StatusBadGateway {
+		metrics.observeUpstreamError()
+	}
+}
+
+// requestLang returns the lang parameter of the request. Only supported
+// languages are accepted because the code becomes part of the host name.
+func requestLang(w http.ResponseWriter, r *http.Request, defaultLang string) (string, bool) {
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		lang = defaultLang
+	}
+	if _, ok := supportedLanguages[lang]; !ok {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("unsupported language %q", lang))
+		return "", false
+	}
+	return lang, true
+}
+
+// statusFor maps a lookup error to the HTTP status of the response.
+func statusFor(err error) int {
+	if errors.Is(err, wiki.ErrArticleNotFound) || errors.Is(err, wiki.ErrNoResults) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadGateway
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// runServe serves the HTTP API on addr until ctx is canceled, with
+// /metrics if withMetrics is set.
+func runServe(ctx context.Context, addr, lang string, withMetrics bool) int {
+	var metrics *serverMetrics
+	if withMetrics {
+		metrics = newServerMetrics()
+	}
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           newServeMux(lang, metrics),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+	fmt.Fprintf(os.Stderr, "Serving the wikr API on http://%s (Ctrl-C to stop)\n", addr)
+
+	select {
+	case err := <-errs:
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitNetwork
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+		return exitOK
+	}
+}
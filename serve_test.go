@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+// newTestAPI starts the HTTP API backed by a stub Wikipedia.
+func newTestAPI(t *testing.T) *httptest.Server {
+	return newTestAPIWithMetrics(t, nil)
+}
+
+func newTestAPIWithMetrics(t *testing.T, metrics *serverMetrics) *httptest.Server {
+	setTestHome(t)
+
+	wikipedia := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/summary/Berlin"):
+			fmt.Fprint(w, `{"title": "Berlin", "extract": "Berlin ist die Hauptstadt Deutschlands.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+		case strings.HasSuffix(r.URL.Path, "/w/api.php"):
+			fmt.Fprint(w, `{"query": {"search": [{"title": "Berlin"}, {"title": "Berlin-Mitte"}]}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(wikipedia.Close)
+
+	wikiOptions = []wiki.Option{wiki.WithHost(wikipedia.URL + "/%s")}
+	t.Cleanup(func() { wikiOptions = nil })
+
+	api := httptest.NewServer(newServeMux("de", metrics))
+	t.Cleanup(api.Close)
+	return api
+}
+
+func getJSON(t *testing.T, url string, v any) int {
+	t.Helper()
+	response, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("Die Anfrage sollte erfolgreich sein: %v", err)
+	}
+	defer response.Body.Close()
+	if v != nil {
+		if err := json.NewDecoder(response.Body).Decode(v); err != nil {
+			t.Fatalf("Die Antwort sollte gültiges JSON sein: %v", err)
+		}
+	}
+	return response.StatusCode
+}
+
+func TestServeSummary(t *testing.T) {
+	api := newTestAPI(t)
+
+	var result Result
+	if status := getJSON(t, api.URL+"/summary?lang=de&title=Berlin", &result); status != http.StatusOK {
+		t.Fatalf("Erwartete Status 200, erhielt %d", status)
+	}
+	if result.Title != "Berlin" || result.Lang != "de" || result.Cached {
+		t.Errorf("Unerwartetes Ergebnis: %+v", result)
+	}
+
+	// Der zweite Abruf kommt aus dem gemeinsamen Cache
+	getJSON(t, api.URL+"/summary?title=Berlin", &result)
+	if !result.Cached {
+		t.Error("Der zweite Abruf sollte aus dem Cache kommen")
+	}
+}
+
+func TestServeSearch(t *testing.T) {
+	api := newTestAPI(t)
+
+	var response searchResponse
+	if status := getJSON(t, api.URL+"/search?lang=de&q=Berlin", &response); status != http.StatusOK {
+		t.Fatalf("Erwartete Status 200, erhielt %d", status)
+	}
+	if response.Query != "Berlin" || len(response.Results) != 2 || response.Results[0] != "Berlin" {
+		t.Errorf("Unerwartete Antwort: %+v", response)
+	}
+}
+
+func TestServeErrors(t *testing.T) {
+	api := newTestAPI(t)
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"Ohne Titel", "/summary?lang=de", http.StatusBadRequest},
+		{"Ohne Suchbegriff", "/search", http.StatusBadRequest},
+		{"Unbekannte Sprache", "/summary?lang=xx&title=Berlin", http.StatusBadRequest},
+		{"Fehlender Artikel", "/summary?title=Gibtesnicht", http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var response errorResponse
+			if status := getJSON(t, api.URL+test.path, &response); status != test.want {
+				t.Errorf("Erwartete Status %d, erhielt %d", test.want, status)
+			}
+			if response.Error == "" {
+				t.Error("Die Antwort sollte eine Fehlermeldung enthalten")
+			}
+		})
+	}
+}
+
+func TestServeRejectsPost(t *testing.T) {
+	api := newTestAPI(t)
+
+	response, err := http.Post(api.URL+"/summary?title=Berlin", "text/plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Erwartete Status 405, erhielt %d", response.StatusCode)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+// printLangLinks lists the language versions with their codes.
+func printLangLinks(w io.Writer, links []wiki.LangLink) {
+	for _, link := range links {
+		fmt.Fprintf(w, "%-7s %s\n", link.Lang, link.Title)
+	}
+}
+
+// findLangLink returns the version for the input, a language code or a
+// name like "english".
+func findLangLink(links []wiki.LangLink, input string) (wiki.LangLink, bool) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if code, err := resolveLanguage(input); err == nil {
+		input = code
+	}
+	for _, link := range links {
+		if link.Lang == input {
+			return link, true
+		}
+	}
+	return wiki.LangLink{}, false
+}
+
+// askLangLink asks for the language to read the article in until the
+// input names one of the versions. Enter alone or the end of the input
+// exits.
+func askLangLink(links []wiki.LangLink) (wiki.LangLink, bool) {
+	for {
+		fmt.Fprintln(promptOut, "\nEnter the language code to read the article in (or press Enter to exit): ")
+		done := waitForInput()
+		input, err := stdinReader.ReadString('\n')
+		done()
+		if strings.TrimSpace(input) == "" {
+			return wiki.LangLink{}, false
+		}
+		if link, ok := findLangLink(links, input); ok {
+			return link, true
+		}
+		fmt.Fprintf(promptOut, "The article has no version in %q.\n", strings.TrimSpace(input))
+		if err != nil {
+			return wiki.LangLink{}, false
+		}
+	}
+}
+
+// runShowLangs lists the other language versions of the article and shows
+// the summary of the one picked. With JSON output the list is printed and
+// nothing is asked.
+func runShowLangs(ctx context.Context, lang, title string, output OutputWriter, out io.Writer, asJSON, copy bool) int {
+	stopLoading := startLoadingAnimation()
+	links, _, err := newClient(lang).LangLinks(ctx, title)
+	stopLoading()
+	if err != nil {
+		activeTheme.Error.Fprintf(os.Stderr, "Error fetching language versions: %v\n", err)
+		return exitCodeFor(err)
+	}
+	if asJSON {
+		if links == nil {
+			links = []wiki.LangLink{}
+		}
+		data, err := marshalJSON(links)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error encoding language versions:", err)
+			return exitNoResults
+		}
+		fmt.Fprintln(out, string(data))
+		return exitOK
+	}
+	if len(links) == 0 {
+		fmt.Fprintf(os.Stderr, "%s has no versions in other languages.\n", title)
+		return exitNoResults
+	}
+
+	fmt.Fprintf(out, "%s is available in %d other languages:\n", title, len(links))
+	printLangLinks(out, links)
+	link, ok := askLangLink(links)
+	if !ok {
+		return exitOK
+	}
+	entry, cached, err := getWikipediaSummary(ctx, link.Lang, link.Title)
+	if err != nil {
+		activeTheme.Error.Fprintf(os.Stderr, "Error fetching summary: %v\n", err)
+		return exitCodeFor(err)
+	}
+	return showEntry(link.Lang, entry, cached, output, copy)
+}
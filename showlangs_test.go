@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestFindLangLink(t *testing.T) {
+	links := []wiki.LangLink{{Lang: "en", Title: "Berlin"}, {Lang: "ja", Title: "ベルリン"}}
+	for _, input := range []string{"ja", " JA\n", "japanese"} {
+		if link, ok := findLangLink(links, input); !ok || link.Title != "ベルリン" {
+			t.Errorf("%q sollte die japanische Version finden, erhielt %v", input, link)
+		}
+	}
+	if _, ok := findLangLink(links, "fr"); ok {
+		t.Error("fr sollte nicht gefunden werden")
+	}
+}
+
+func TestRunShowLangs(t *testing.T) {
+	setTestHome(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Query().Get("srsearch") != "":
+			fmt.Fprint(w, `{"query": {"search": [{"title": "Deutschland"}]}}`)
+		case r.URL.Query().Get("prop") == "langlinks":
+			fmt.Fprint(w, `{"query": {"pages": [{"title": "Deutschland", "langlinks": [{"lang": "en", "title": "Germany"}, {"lang": "fr", "title": "Allemagne"}]}]}}`)
+		case strings.HasPrefix(r.URL.Path, "/en/") && strings.HasSuffix(r.URL.Path, "/summary/Germany"):
+			fmt.Fprint(w, `{"title": "Germany", "extract": "Germany is a country in Central Europe.", "content_urls": {"desktop": {"page": "https://en.wikipedia.org/wiki/Germany"}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	stdinReader = bufio.NewReader(strings.NewReader("english\n"))
+	defer func() { stdinReader = bufio.NewReader(os.Stdin) }()
+
+	var code int
+	stdout, _ := captureOutput(t, func() {
+		code = run([]string{"-show-langs", "Deutschland"})
+	})
+	if code != exitOK {
+		t.Fatalf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+	for _, want := range []string{"en      Germany\n", "fr      Allemagne\n", "Germany is a country in Central Europe."} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Die Ausgabe enthält %q nicht: %q", want, stdout)
+		}
+	}
+
+	stdout, _ = captureOutput(t, func() {
+		code = run([]string{"-show-langs", "-json", "Deutschland"})
+	})
+	if code != exitOK || !strings.Contains(stdout, `[{"lang":"en","title":"Germany"},{"lang":"fr","title":"Allemagne"}]`) {
+		t.Errorf("Erwartete die Sprachversionen als JSON, erhielt %d: %q", code, stdout)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// shutdownSignals cancel in-flight requests. Cache writes are atomic and
+// happen right away, so nothing has to be flushed when they arrive.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// waitingForInput is set while a prompt blocks on stdin. A read cannot be
+// canceled, so a signal that arrives then ends the program.
+var waitingForInput atomic.Bool
+
+var (
+	restoreMu sync.Mutex
+	// restoreTerminal undoes raw mode while the arrow menu is shown
+	restoreTerminal func()
+)
+
+// setTerminalRestore registers the function that leaves raw mode, nil
+// once the terminal is back to normal.
+func setTerminalRestore(restore func()) {
+	restoreMu.Lock()
+	defer restoreMu.Unlock()
+	restoreTerminal = restore
+}
+
+// waitForInput marks the program as blocked on stdin until done is called.
+func waitForInput() (done func()) {
+	waitingForInput.Store(true)
+	return func() { waitingForInput.Store(false) }
+}
+
+// exitOnSignalWhileWaiting ends the program like 'q' when ctx is canceled
+// by a signal while a prompt waits for input. It restores the terminal
+// first and returns when ctx ends otherwise.
+func exitOnSignalWhileWaiting(ctx context.Context, exit func(code int)) {
+	<-ctx.Done()
+	if !waitingForInput.Load() {
+		return
+	}
+	restoreMu.Lock()
+	if restoreTerminal != nil {
+		restoreTerminal()
+		restoreTerminal = nil
+	}
+	restoreMu.Unlock()
+	fmt.Fprintln(promptOut, "\nProgram was exited.")
+	exit(exitOK)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExitOnSignalWhileWaiting(t *testing.T) {
+	oldOut := promptOut
+	defer func() { promptOut = oldOut }()
+	file, err := os.CreateTemp(t.TempDir(), "prompt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	promptOut = file
+
+	restored := false
+	setTerminalRestore(func() { restored = true })
+	defer setTerminalRestore(nil)
+	done := waitForInput()
+	defer done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	code := -1
+	exitOnSignalWhileWaiting(ctx, func(c int) { code = c })
+
+	if code != exitOK {
+		t.Errorf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+	if !restored {
+		t.Error("Terminal wurde nicht wiederhergestellt")
+	}
+	data, _ := os.ReadFile(file.Name())
+	if !strings.Contains(string(data), "Program was exited.") {
+		t.Errorf("Ausgabe = %q", data)
+	}
+}
+
+func TestExitOnSignalIgnoredWithoutPrompt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	exited := make(chan struct{})
+	go func() {
+		exitOnSignalWhileWaiting(ctx, func(int) { t.Error("Programm sollte nicht beendet werden") })
+		close(exited)
+	}()
+	cancel()
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("Watcher wurde nicht beendet")
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+var sortModes = []string{"relevance", "alpha", "length"}
+
+// checkSortMode reports an unknown -sort value.
+func checkSortMode(mode string) error {
+	if !slices.Contains(sortModes, mode) {
+		return fmt.Errorf("unknown sort order %q, available orders: %s", mode, strings.Join(sortModes, ", "))
+	}
+	return nil
+}
+
+// sortResults orders the first limit titles for the result menu. The API
+// ranks by relevance, so the most relevant titles are kept and only their
+// order changes. The input is not modified.
+func sortResults(titles []string, mode string, limit int) []string {
+	if limit > 0 && len(titles) > limit {
+		titles = titles[:limit]
+	}
+	sorted := append([]string(nil), titles...)
+	switch mode {
+	case "alpha":
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return strings.ToLower(sorted[i]) < strings.ToLower(sorted[j])
+		})
+	case "length":
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return utf8.RuneCountInString(sorted[i]) < utf8.RuneCountInString(sorted[j])
+		})
+	}
+	return sorted
+}
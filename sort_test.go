@@ -0,0 +1,46 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSortResults(t *testing.T) {
+	titles := []string{"Berlin-Mitte", "berlin", "Bahnhof Berlin Zoo", "Aachen"}
+
+	tests := []struct {
+		mode string
+		want []string
+	}{
+		{"relevance", []string{"Berlin-Mitte", "berlin", "Bahnhof Berlin Zoo", "Aachen"}},
+		{"alpha", []string{"Aachen", "Bahnhof Berlin Zoo", "berlin", "Berlin-Mitte"}},
+		{"length", []string{"berlin", "Aachen", "Berlin-Mitte", "Bahnhof Berlin Zoo"}},
+	}
+	for _, test := range tests {
+		got := sortResults(titles, test.mode, 0)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s: erwartete %v, erhielt %v", test.mode, test.want, got)
+		}
+	}
+	if titles[0] != "Berlin-Mitte" {
+		t.Errorf("Die Eingabe sollte nicht verändert werden: %v", titles)
+	}
+}
+
+func TestSortResultsKeepsMostRelevant(t *testing.T) {
+	got := sortResults([]string{"Zeppelin", "Ulm", "Aachen"}, "alpha", 2)
+	if !slices.Equal(got, []string{"Ulm", "Zeppelin"}) {
+		t.Errorf("Erwartete die zwei relevantesten Titel sortiert, erhielt %v", got)
+	}
+}
+
+func TestCheckSortMode(t *testing.T) {
+	for _, mode := range sortModes {
+		if err := checkSortMode(mode); err != nil {
+			t.Errorf("%s sollte gültig sein: %v", mode, err)
+		}
+	}
+	if err := checkSortMode("random"); err == nil {
+		t.Error("Eine unbekannte Sortierung sollte einen Fehler liefern")
+	}
+}
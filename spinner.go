@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// spinnerStyles are the frame sets -spinner selects from. "none" has no
+// frames and turns the spinner off like -no-spinner.
+var spinnerStyles = map[string][]string{
+	"classic": {"|", "/", "-", "\\"},
+	"dots":    {"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+	"arrow":   {"←", "↖", "↑", "↗", "→", "↘", "↓", "↙"},
+	"none":    nil,
+}
+
+const defaultSpinnerStyle = "classic"
+
+// spinnerFrames are drawn one after another by the loading animation.
+var spinnerFrames = spinnerStyles[defaultSpinnerStyle]
+
+func spinnerStyleNames() []string {
+	names := make([]string, 0, len(spinnerStyles))
+	for name := range spinnerStyles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// setSpinnerStyle selects the frames of the style. For "none" it also
+// disables the spinner, the caller restores spinnerEnabled.
+func setSpinnerStyle(name string) error {
+	frames, ok := spinnerStyles[name]
+	if !ok {
+		return fmt.Errorf("unknown spinner style %q, available styles: %s", name, strings.Join(spinnerStyleNames(), ", "))
+	}
+	spinnerFrames = frames
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSpinnerStyleFrames(t *testing.T) {
+	defer func() { spinnerFrames = spinnerStyles[defaultSpinnerStyle] }()
+
+	if err := setSpinnerStyle("dots"); err != nil {
+		t.Fatalf("setSpinnerStyle sollte keinen Fehler zurückgeben: %v", err)
+	}
+	var out strings.Builder
+	done := make(chan bool)
+	drawn := make(chan bool)
+	go func() {
+		drawn <- showLoadingAnimation(&out, spinnerFrames, done)
+	}()
+	time.Sleep(150 * time.Millisecond)
+	close(done)
+	if !<-drawn {
+		t.Fatal("Der Spinner sollte gezeichnet haben")
+	}
+
+	if !strings.Contains(out.String(), loadingMessage+"⠋") || !strings.Contains(out.String(), loadingMessage+"⠙") {
+		t.Errorf("Die Frames des Stils dots sollten erscheinen: %q", out.String())
+	}
+	if strings.Contains(out.String(), "|") {
+		t.Errorf("Die Frames des Stils classic sollten nicht erscheinen: %q", out.String())
+	}
+}
+
+func TestSetSpinnerStyleUnknown(t *testing.T) {
+	err := setSpinnerStyle("wirbel")
+	if err == nil || !strings.Contains(err.Error(), "arrow, classic, dots, none") {
+		t.Errorf("Erwartete einen Fehler mit den verfügbaren Stilen, erhielt %v", err)
+	}
+	if len(spinnerFrames) != 4 {
+		t.Errorf("Ein unbekannter Stil sollte die Frames nicht ändern: %q", spinnerFrames)
+	}
+}
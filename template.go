@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// templateWriter renders the result through a user supplied text/template,
+// for -template.
+type templateWriter struct {
+	out  io.Writer
+	tmpl *template.Template
+}
+
+// newTemplateWriter parses text and tries it on an empty result, so typos
+// in field names are reported before anything is fetched.
+func newTemplateWriter(out io.Writer, text string) (*templateWriter, error) {
+	tmpl, err := template.New("template").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -template: %v", err)
+	}
+	if err := tmpl.Execute(io.Discard, Result{}); err != nil {
+		return nil, fmt.Errorf("invalid -template: %v", err)
+	}
+	return &templateWriter{out: out, tmpl: tmpl}, nil
+}
+
+func (w *templateWriter) Write(result Result) error {
+	// Render into a buffer so a failing template prints nothing
+	var b bytes.Buffer
+	if err := w.tmpl.Execute(&b, result); err != nil {
+		return fmt.Errorf("error rendering -template: %v", err)
+	}
+	if !strings.HasSuffix(b.String(), "\n") {
+		b.WriteString("\n")
+	}
+	_, err := w.out.Write(b.Bytes())
+	return err
+}
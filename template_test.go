@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTemplateWriter(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"{{.Title}}: {{.URL}}", "Berlin: https://de.wikipedia.org/wiki/Berlin\n"},
+		{"[{{.Lang}}] {{.Description}}\n", "[de] Hauptstadt Deutschlands\n"},
+		{"{{if .Cached}}(cached) {{end}}{{.Title}}", "(cached) Berlin\n"},
+		{"{{.Title | printf \"%-8s\"}}|", "Berlin  |\n"},
+	}
+
+	for _, test := range tests {
+		var output bytes.Buffer
+		writer, err := newTemplateWriter(&output, test.template)
+		if err != nil {
+			t.Fatalf("Die Vorlage %q sollte gültig sein: %v", test.template, err)
+		}
+		if err := writer.Write(testResult); err != nil {
+			t.Fatalf("Write sollte keinen Fehler zurückgeben: %v", err)
+		}
+		if output.String() != test.want {
+			t.Errorf("Vorlage %q: erwartete %q, erhielt %q", test.template, test.want, output.String())
+		}
+	}
+}
+
+func TestTemplateWriterInvalid(t *testing.T) {
+	for _, template := range []string{"{{.Title", "{{.Titel}}", "{{range}}"} {
+		_, err := newTemplateWriter(&bytes.Buffer{}, template)
+		if err == nil {
+			t.Errorf("Die Vorlage %q sollte abgelehnt werden", template)
+			continue
+		}
+		if !strings.Contains(err.Error(), "invalid -template") {
+			t.Errorf("Die Fehlermeldung sollte die Option nennen: %v", err)
+		}
+	}
+}
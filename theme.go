@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Theme holds the colors used for the different parts of the output.
+type Theme struct {
+	Title   *color.Color
+	Summary *color.Color
+	URL     *color.Color
+	Cached  *color.Color
+	// Fresh marks cached results that are well within their TTL
+	Fresh       *color.Color
+	Coordinates *color.Color
+	Error       *color.Color
+}
+
+var themes = map[string]Theme{
+	"default": {
+		Title:       color.New(color.Bold),
+		Summary:     color.New(color.FgBlue),
+		URL:         color.New(color.FgGreen),
+		Cached:      color.New(color.FgYellow),
+		Fresh:       color.New(color.FgGreen),
+		Coordinates: color.New(color.FgMagenta),
+		Error:       color.New(color.FgRed),
+	},
+	"vivid": {
+		Title:       color.New(color.FgHiWhite, color.Bold, color.Underline),
+		Summary:     color.New(color.FgHiCyan, color.Bold),
+		URL:         color.New(color.FgHiGreen, color.Bold),
+		Cached:      color.New(color.FgHiYellow),
+		Fresh:       color.New(color.FgHiGreen),
+		Coordinates: color.New(color.FgHiMagenta, color.Bold),
+		Error:       color.New(color.FgHiRed, color.Bold),
+	},
+	"mono": {
+		Title:       color.New(color.Bold, color.Underline),
+		Summary:     color.New(color.Bold),
+		URL:         color.New(color.Bold),
+		Cached:      color.New(color.Faint),
+		Fresh:       color.New(color.Faint),
+		Coordinates: color.New(color.Bold),
+		Error:       color.New(color.Bold),
+	},
+}
+
+var activeTheme = themes["default"]
+
+// configureColor selects the theme and disables colors if requested. The
+// color package already turns colors off for NO_COLOR and when stdout is
+// not a terminal.
+func configureColor(noColor bool, theme string) error {
+	selected, ok := themes[theme]
+	if !ok {
+		return fmt.Errorf("unknown theme %q, available themes: %s", theme, strings.Join(themeNames(), ", "))
+	}
+	activeTheme = selected
+	if noColor {
+		color.NoColor = true
+	}
+	return nil
+}
+
+func themeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+	"github.com/fatih/color"
+)
+
+func TestNoColorOutput(t *testing.T) {
+	originalNoColor := color.NoColor
+	defer func() {
+		color.NoColor = originalNoColor
+		activeTheme = themes["default"]
+	}()
+
+	// Im Test ist stdout kein Terminal, daher Farben zuerst explizit aktivieren
+	color.NoColor = false
+
+	if err := configureColor(true, "vivid"); err != nil {
+		t.Fatalf("configureColor sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	var output bytes.Buffer
+	writer := &plainWriter{out: &output}
+	err := writer.Write(Result{
+		Title:       "Berlin",
+		Summary:     "Berlin ist die Hauptstadt Deutschlands.",
+		URL:         "https://de.wikipedia.org/wiki/Berlin",
+		Coordinates: &wiki.Coordinates{Lat: 52.52, Lon: 13.405},
+		Cached:      true,
+	})
+	if err != nil {
+		t.Fatalf("Write sollte keinen Fehler zurückgeben: %v", err)
+	}
+
+	if strings.Contains(output.String(), "\x1b[") {
+		t.Errorf("Die Ausgabe sollte keine ANSI-Codes enthalten: %q", output.String())
+	}
+	if !strings.Contains(output.String(), "Summary:") {
+		t.Errorf("Die Ausgabe sollte die Überschriften enthalten: %q", output.String())
+	}
+}
+
+func TestConfigureColorUnknownTheme(t *testing.T) {
+	if err := configureColor(false, "neon"); err == nil {
+		t.Error("Ein unbekanntes Theme sollte einen Fehler liefern")
+	}
+}
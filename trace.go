@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tracer is shared by all clients while -trace is set, nil disables it.
+var tracer *traceTransport
+
+// traceTransport writes every request and response with their headers to
+// out. Each exchange is written as one block so that parallel -batch
+// lookups do not interleave.
+type traceTransport struct {
+	next http.RoundTripper
+	out  io.Writer
+	mu   sync.Mutex
+	now  func() time.Time
+}
+
+func newTraceTransport(next http.RoundTripper, out io.Writer) *traceTransport {
+	return &traceTransport{next: next, out: out, now: time.Now}
+}
+
+func (t *traceTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	var block strings.Builder
+	fmt.Fprintf(&block, "> %s %s\n", request.Method, request.URL)
+	writeHeaders(&block, "> ", request.Header)
+
+	start := t.now()
+	response, err := t.next.RoundTrip(request)
+	elapsed := t.now().Sub(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(&block, "< error after %s: %v\n", elapsed, err)
+	} else {
+		fmt.Fprintf(&block, "< %s %s (%s)\n", response.Proto, response.Status, elapsed)
+		writeHeaders(&block, "< ", response.Header)
+	}
+
+	t.mu.Lock()
+	io.WriteString(t.out, block.String()+"\n")
+	t.mu.Unlock()
+	return response, err
+}
+
+// writeHeaders writes one line per header value, sorted by name.
+func writeHeaders(w io.Writer, prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			// The credentials of -auth must not end up in logs
+			if name == "Authorization" {
+				value = "[redacted]"
+			}
+			fmt.Fprintf(w, "%s%s: %s\n", prefix, name, value)
+		}
+	}
+}
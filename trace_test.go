@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestTraceTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "ja")
+		fmt.Fprint(w, "{}")
+	}))
+	defer server.Close()
+
+	var out strings.Builder
+	client := &http.Client{Transport: newTraceTransport(http.DefaultTransport, &out)}
+	request, _ := http.NewRequest(http.MethodGet, server.URL+"/pfad?q=1", nil)
+	request.Header.Set("User-Agent", "wikr-test")
+	response, err := client.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	for _, want := range []string{"> GET " + server.URL + "/pfad?q=1\n", "> User-Agent: wikr-test\n", "< HTTP/1.1 200 OK (", "< X-Test: ja\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Trace enthält %q nicht:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRunTrace(t *testing.T) {
+	setTestHome(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"query": {"searchinfo": {"totalhits": 7}, "search": [{"title": "Berlin"}]}}`)
+	}))
+	defer server.Close()
+
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	_, stderr := captureOutput(t, func() {
+		run([]string{"-trace", "-count", "Berlin"})
+	})
+	if !strings.Contains(stderr, "> GET "+server.URL+"/de/w/api.php") || !strings.Contains(stderr, "< HTTP/1.1 200 OK") {
+		t.Errorf("Trace fehlt auf stderr:\n%s", stderr)
+	}
+	if tracer != nil {
+		t.Error("tracer sollte nach run zurückgesetzt sein")
+	}
+}
+
+func TestTraceRedactsAuthorization(t *testing.T) {
+	var out strings.Builder
+	writeHeaders(&out, "> ", http.Header{"Authorization": {"Bearer geheim"}, "Accept": {"*/*"}})
+	if strings.Contains(out.String(), "geheim") || !strings.Contains(out.String(), "> Authorization: [redacted]") {
+		t.Errorf("Die Zugangsdaten sollten nicht ausgegeben werden: %q", out.String())
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+var urlFormats = []string{"pretty", "permalink", "curid"}
+
+// urlFormat selects the article URL that is shown, set by -url-format.
+var urlFormat = "pretty"
+
+func checkURLFormat(format string) error {
+	if !containsString(urlFormats, format) {
+		return fmt.Errorf("unknown URL format %q, available formats: %s", format, strings.Join(urlFormats, ", "))
+	}
+	return nil
+}
+
+// formatEntryURL replaces the /wiki/Title URL of the entry according to
+// -url-format. The page ID and revision are fetched if the entry does not
+// have them; if that fails the pretty URL is kept with a warning.
+func formatEntryURL(ctx context.Context, lang string, entry wiki.CacheEntry) wiki.CacheEntry {
+	if urlFormat == "pretty" || entry.NotFound {
+		return entry
+	}
+	client := newClient(lang)
+	withIDs, err := client.Revision(ctx, entry)
+	if err != nil {
+		logger.Warn("cannot build the "+urlFormat+" URL, showing the article URL", "title", entry.Title, "error", err)
+		return entry
+	}
+	switch urlFormat {
+	case "permalink":
+		title := withIDs.Title
+		if withIDs.Canonical != "" {
+			title = withIDs.Canonical
+		}
+		withIDs.URL = client.PermalinkURL(title, withIDs.Revision)
+	case "curid":
+		withIDs.URL = client.CurIDURL(withIDs.PageID)
+	}
+	return withIDs
+}
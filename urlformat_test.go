@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestFormatEntryURL(t *testing.T) {
+	setTestHome(t)
+	defer func() { urlFormat = "pretty" }()
+
+	entry := wiki.CacheEntry{
+		Title:     "Albert Einstein",
+		Canonical: "Albert_Einstein",
+		URL:       "https://de.wikipedia.org/wiki/Albert_Einstein",
+		PageID:    1278360,
+		Revision:  241234567,
+	}
+	tests := map[string]string{
+		"pretty":    "https://de.wikipedia.org/wiki/Albert_Einstein",
+		"permalink": "https://de.wikipedia.org/w/index.php?title=Albert_Einstein&oldid=241234567",
+		"curid":     "https://de.wikipedia.org/w/index.php?curid=1278360",
+	}
+	for format, want := range tests {
+		urlFormat = format
+		if got := formatEntryURL(context.Background(), "de", entry).URL; got != want {
+			t.Errorf("%s: erwartete %q, erhielt %q", format, want, got)
+		}
+	}
+
+	if err := checkURLFormat("kurz"); err == nil {
+		t.Error("Ein unbekanntes Format sollte einen Fehler liefern")
+	}
+}
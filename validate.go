@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// outputModeFlags each replace the output of the others, so only one of
+// them may be given. An explicit -format plain keeps the default output
+// and does not count.
+var outputModeFlags = []string{"json", "json-pretty", "format", "template", "describe", "first-sentence", "compact", "url-only"}
+
+// flagConflicts are further pairs of flags that contradict each other. A
+// name with a value, e.g. "format=json", only counts with that value.
+var flagConflicts = [][2]string{
+	{"sentences", "words"},
+	{"first-sentence", "sentences"},
+	{"first-sentence", "words"},
+	{"full", "section"},
+	{"full", "json"},
+	{"full", "json-pretty"},
+	{"full", "format"},
+	{"clear-expired", "no-store"},
+	{"refresh", "no-store"},
+	{"pin", "unpin"},
+	{"pin", "no-store"},
+	{"unpin", "no-store"},
+	{"bullets", "json"},
+	{"bullets", "json-pretty"},
+	{"bullets", "format=json"},
+	{"bullets", "first-sentence"},
+}
+
+// validateFlags reports the first combination of flags on the command
+// line that cannot work together. Only flags that were given count, not
+// their defaults.
+func validateFlags(flags *flag.FlagSet) error {
+	given := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+		given[f.Name+"="+f.Value.String()] = true
+	})
+
+	var modes []string
+	for _, name := range outputModeFlags {
+		if given[name] && !(name == "format" && given["format=plain"]) {
+			modes = append(modes, "-"+name)
+		}
+	}
+	if len(modes) > 1 {
+		return fmt.Errorf("use only one of %s, each selects a different output", strings.Join(modes, ", "))
+	}
+	for _, conflict := range flagConflicts {
+		if given[conflict[0]] && given[conflict[1]] {
+			return fmt.Errorf("-%s cannot be combined with -%s", conflict[0], conflict[1])
+		}
+	}
+	return nil
+}
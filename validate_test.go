@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+// newValidateFlagSet declares every flag validateFlags knows about as a
+// string flag, only whether it was given matters.
+func newValidateFlagSet(t *testing.T, args []string) *flag.FlagSet {
+	t.Helper()
+	flags := flag.NewFlagSet("wikr", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	for _, name := range append(outputModeFlags, "sentences", "words", "full", "section", "clear-expired", "no-store", "bullets", "count", "lang") {
+		flags.String(name, "", "")
+	}
+	if err := flags.Parse(args); err != nil {
+		t.Fatalf("Parse(%q): %v", args, err)
+	}
+	return flags
+}
+
+func TestValidateFlags(t *testing.T) {
+	tests := []struct {
+		args []string
+		// conflict is part of the error, empty if the combination is valid
+		conflict string
+	}{
+		{[]string{"-json", "x"}, ""},
+		{[]string{"-json", "x", "-count", "x"}, ""},
+		{[]string{"-describe", "x", "-lang", "en"}, ""},
+		{[]string{"-sentences", "2", "-full", "x"}, ""},
+		{[]string{"-json", "x", "-template", "x"}, "-json, -template"},
+		{[]string{"-url-only", "x", "-describe", "x"}, "-describe, -url-only"},
+		{[]string{"-compact", "x", "-format", "markdown", "-first-sentence", "x"}, "-format, -first-sentence, -compact"},
+		{[]string{"-format", "plain", "-describe", "x"}, ""},
+		{[]string{"-compact", "x", "-format", "plain"}, ""},
+		{[]string{"-format", "json", "-describe", "x"}, "-format, -describe"},
+		{[]string{"-sentences", "2", "-words", "30"}, "-sentences cannot be combined with -words"},
+		{[]string{"-words", "30", "-first-sentence", "x"}, "-first-sentence cannot be combined with -words"},
+		{[]string{"-section", "x", "-full", "x"}, "-full cannot be combined with -section"},
+		{[]string{"-no-store", "x", "-clear-expired", "x"}, "-clear-expired cannot be combined with -no-store"},
+		{[]string{"-bullets", "x", "-format", "markdown"}, ""},
+		{[]string{"-json-pretty", "x", "-bullets", "x"}, "-bullets cannot be combined with -json-pretty"},
+		{[]string{"-bullets", "x", "-format", "json"}, "-bullets cannot be combined with -format=json"},
+		{[]string{"-full", "x", "-json", "x"}, "-full cannot be combined with -json"},
+		{[]string{"-full", "x", "-json-pretty", "x"}, "-full cannot be combined with -json-pretty"},
+		{[]string{"-full", "x", "-format", "markdown"}, "-full cannot be combined with -format"},
+	}
+	for _, test := range tests {
+		err := validateFlags(newValidateFlagSet(t, test.args))
+		switch {
+		case test.conflict == "" && err != nil:
+			t.Errorf("%q sollte gültig sein: %v", test.args, err)
+		case test.conflict != "" && err == nil:
+			t.Errorf("%q sollte einen Fehler liefern", test.args)
+		case test.conflict != "" && !strings.Contains(err.Error(), test.conflict):
+			t.Errorf("%q: der Fehler sollte %q nennen: %v", test.args, test.conflict, err)
+		}
+	}
+}
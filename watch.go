@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// watchSummary fetches the summary every interval, bypassing the cache,
+// and prints it again whenever it changed. It returns when ctx is done.
+func watchSummary(ctx context.Context, lang, title string, interval time.Duration, fetch summaryFetcher, output OutputWriter, out io.Writer) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	previous := ""
+	first := true
+	for {
+		entry, _, err := fetch(ctx, lang, title)
+		now := time.Now().Format("2006-01-02 15:04:05")
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			activeTheme.Error.Fprintf(out, "[%s] Error fetching summary: %v\n", now, err)
+		case first || entry.Summary != previous:
+			if first {
+				fmt.Fprintf(out, "[%s] Watching %q every %v, press Ctrl-C to stop\n", now, title, interval)
+			} else {
+				fmt.Fprintf(out, "[%s] Summary changed\n", now)
+			}
+			if err := output.Write(newResult(lang, title, entry, false)); err != nil {
+				activeTheme.Error.Fprintf(out, "[%s] Error: %v\n", now, err)
+			}
+			previous = entry.Summary
+			first = false
+		default:
+			fmt.Fprintf(out, "[%s] No changes\n", now)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
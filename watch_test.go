@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
+
+func TestWatchSummaryReprintsOnChange(t *testing.T) {
+	setTestHome(t)
+
+	// Der Stub liefert zweimal dieselbe Zusammenfassung und danach eine geänderte
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		version := 1
+		if requests > 2 {
+			version = 2
+		}
+		mu.Unlock()
+		fmt.Fprintf(w, `{"title": "Berlin", "extract": "Fassung %d", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`, version)
+	}))
+	defer server.Close()
+
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var output, log bytes.Buffer
+	fetch := func(ctx context.Context, lang, title string) (wiki.CacheEntry, bool, error) {
+		entry, cached, err := fetchWikipediaSummary(ctx, lang, title)
+		mu.Lock()
+		if requests >= 4 {
+			cancel()
+		}
+		mu.Unlock()
+		return entry, cached, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		watchSummary(ctx, "de", "Berlin", 10*time.Millisecond, fetch, &jsonWriter{out: &output}, &log)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchSummary sollte nach dem Abbruch zurückkehren")
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Erwartete 2 Ausgaben (Start und Änderung), erhielt %d: %q", len(lines), output.String())
+	}
+	if !strings.Contains(lines[0], "Fassung 1") || !strings.Contains(lines[1], "Fassung 2") {
+		t.Errorf("Unerwartete Ausgaben: %q", lines)
+	}
+	if !strings.Contains(log.String(), "No changes") || !strings.Contains(log.String(), "Summary changed") {
+		t.Errorf("Jeder Durchlauf sollte mit Zeitstempel protokolliert werden: %q", log.String())
+	}
+}
@@ -1,338 +1,1066 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"strings"
 	"bufio"
-	"github.com/fatih/color"
-	"path/filepath"
 	"time"
 	"sync"
+	"unicode/utf8"
 	"flag"
+
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
 )
 
+const version = "0.1.0"
+
+// Exit codes, documented in the usage text so scripts can rely on them
 const (
-	wikipediaAPITemplate = "https://%s.wikipedia.org/api/rest_v1/page/summary/"
-	wikipediaSearchAPITemplate = "https://%s.wikipedia.org/w/api.php?action=query&list=search&srsearch=%s&format=json"
-	cacheFileName = ".wikr_cache.json"
-	cacheDuration = 24 * time.Hour
-	debug = false
-	version = "0.1.0"
+	exitOK        = 0
+	exitNoResults = 1
+	exitUsage     = 2
+	exitNetwork   = 3
 )
 
-type CacheEntry struct {
-	Summary   string    `json:"summary"`
-	URL       string    `json:"url"`
-	Timestamp time.Time `json:"timestamp"`
-}
+// stdinReader is shared by all prompts so that buffered input is not lost
+// between them.
+var stdinReader = bufio.NewReader(os.Stdin)
 
-type Cache map[string]CacheEntry
+// project is the Wikimedia project to look up articles in, set by -project.
+var project = wiki.DefaultProject
 
-func getCachePath() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return cacheFileName
-	}
-	return filepath.Join(homeDir, cacheFileName)
-}
+// wikiOptions are passed to every client, tests use them to point the
+// client at a stub server.
+var wikiOptions []wiki.Option
 
-func loadCache() Cache {
-	createEmptyCacheFileIfNotExists()
-	cache := make(Cache)
-	cachePath := getCachePath()
-	data, err := os.ReadFile(cachePath)
-	if err != nil {
-		if debug {
-			fmt.Printf("Error reading cache file %s: %v\n", cachePath, err)
-		}
-		return cache
+// newClient returns a client for the language that uses the cache file in
+// the home directory.
+func newClient(lang string) *wiki.Client {
+	opts := append([]wiki.Option{wiki.WithProject(project), wiki.WithCache(fileCache()), wiki.WithLogger(logger)}, wikiOptions...)
+	if timings != nil {
+		opts = append(opts, wiki.WithTimings(timings.record))
 	}
-	err = json.Unmarshal(data, &cache)
-	if err != nil && debug {
-		fmt.Printf("Error decoding cache: %v\n", err)
+	if pinTTL > 0 {
+		opts = append(opts, wiki.WithTTL(pinTTL))
 	}
-	return cache
-}
-
-func saveCache(cache Cache) {
-	data, err := json.Marshal(cache)
-	if err != nil && debug {
-		fmt.Printf("Error encoding cache: %v\n", err)
-		return
+	if adaptiveMaxTTL > 0 {
+		opts = append(opts, wiki.WithAdaptiveTTL(adaptiveMinTTL, adaptiveMaxTTL))
 	}
-	cachePath := getCachePath()
-	err = os.WriteFile(cachePath, data, 0644)
-	if err != nil && debug {
-		fmt.Printf("Error writing cache file %s: %v\n", cachePath, err)
+	if pinArticles {
+		opts = append(opts, wiki.WithPin())
 	}
-}
-
-func getCachedEntry(lang, title string) (string, string, bool) {
-	cache := loadCache()
-	key := lang + ":" + title
-	if debug {
-		fmt.Printf("\nSearch for cache entry for key: %s\n", key)
+	if rawDir != "" {
+		opts = append(opts, wiki.WithRawDir(rawDir))
 	}
-	entry, exists := cache[key]
-	if exists {
-		if debug {
-			fmt.Printf("Cache entry found, age: %v\n", time.Since(entry.Timestamp))
-		}
-		if time.Since(entry.Timestamp) < cacheDuration {
-			return entry.Summary, entry.URL, true
-		}
+	if searchLimit > 0 {
+		opts = append(opts, wiki.WithSearchLimit(searchLimit))
+	}
+	if maxBytes > 0 {
+		opts = append(opts, wiki.WithMaxBytes(maxBytes))
+	}
+	if baseURL != "" {
+		opts = append(opts, wiki.WithHost(baseURL))
 	}
-	return "", "", false
+	if authorization != "" {
+		opts = append(opts, wiki.WithAuthorization(authorization))
+	}
+	if tracer != nil {
+		opts = append(opts, wiki.WithHTTPClient(&http.Client{Transport: tracer}))
+	} else if netrcTransport != nil {
+		opts = append(opts, wiki.WithHTTPClient(&http.Client{Transport: netrcTransport}))
+	}
+	return wiki.NewClient(lang, opts...)
 }
 
-func setCachedEntry(lang, title, summary, url string) {
-	cache := loadCache()
-	key := lang + ":" + title
-	cache[key] = CacheEntry{
-		Summary:   summary,
-		URL:       url,
-		Timestamp: time.Now(),
-	}
-	if debug {
-		fmt.Printf("Save cache entry for key: %s\n", key)
+// pinTTL is how long fetched articles stay cached, set by -pin-ttl. 0
+// keeps the default.
+var pinTTL time.Duration
+
+// adaptiveMinTTL and adaptiveMaxTTL bound the TTL that depends on the last
+// edit of an article, from the config file. 0 keeps the fixed TTL.
+var adaptiveMinTTL, adaptiveMaxTTL time.Duration
+
+// rawDir receives the raw API responses, set by -save-raw.
+var rawDir string
+
+// cacheMode is the permission mode of the cache file from the config file.
+var cacheMode = wiki.DefaultCacheMode
+
+// searchLimit is how many search results are collected across the pages
+// of the search API, set by -search-limit. 0 takes the first page only.
+var searchLimit int
+
+// maxBytes caps the length of a response, set by -max-bytes. 0 reads the
+// whole response.
+var maxBytes int64
+
+// defaultMaxBytes keeps a pathological article from filling the memory.
+const defaultMaxBytes = 10 << 20
+
+// noStore reads the cache without writing to it, set by -no-store.
+var noStore = false
+
+// cacheCompression gzips the cache file, set by compress_cache in the
+// config file.
+var cacheCompression = false
+
+// fallbackWarning makes sure the missing home directory is only reported
+// once per run.
+var fallbackWarning sync.Once
+
+func fileCache() *wiki.FileCache {
+	path := wiki.DefaultCachePath()
+	if filepath.Dir(path) == wiki.FallbackDir() {
+		fallbackWarning.Do(func() {
+			logger.Warn("no home directory, keeping the cache in the temporary directory", "path", path)
+		})
 	}
-	saveCache(cache)
+	cache := wiki.NewFileCache(path)
+	cache.SetLogger(logger)
+	cache.SetMode(cacheMode)
+	cache.SetCompression(cacheCompression)
+	cache.SetReadOnly(noStore)
+	return cache
 }
 
-func showLoadingAnimation(done chan bool) {
-	animation := []string{"|", "/", "-", "\\"}
+const loadingMessage = "Loading data... "
+
+// showLoadingAnimation draws the frames of the spinner to out until done
+// is closed and reports whether it drew anything.
+func showLoadingAnimation(out io.Writer, animation []string, done chan bool) bool {
 	i := 0
+	drawn := false
 	for {
 		select {
 		case <-done:
-			return
+			return drawn
 		default:
-			fmt.Printf("\rLoading data... %s", animation[i])
+			fmt.Fprintf(out, "\r%s%s", loadingMessage, animation[i])
+			drawn = true
 			i = (i + 1) % len(animation)
 			time.Sleep(100 * time.Millisecond)
 		}
 	}
 }
 
-func getWikipediaSummary(lang, title string) (string, string, bool, error) {
+// spinnerEnabled is switched off by -no-spinner and while several lookups
+// run concurrently
+var spinnerEnabled = true
+
+// startLoadingAnimation shows the spinner until the returned function is
+// called, which also clears the animation.
+func startLoadingAnimation() func() {
+	if !spinnerEnabled || len(spinnerFrames) == 0 {
+		return func() {}
+	}
+
 	done := make(chan bool)
+	drawn := false
 	var wg sync.WaitGroup
 	wg.Add(1)
 
 	go func() {
 		defer wg.Done()
-		showLoadingAnimation(done)
+		drawn = showLoadingAnimation(os.Stdout, spinnerFrames, done)
 	}()
 
-	// Try to get the entry from the cache first
-	if summary, url, found := getCachedEntry(lang, title); found {
+	return func() {
 		close(done)
 		wg.Wait()
-		fmt.Print("\r") // Clears the loading animation
-		return summary, url, true, nil
+		if drawn {
+			clearLine(os.Stdout, utf8.RuneCountInString(loadingMessage)+1)
+		}
 	}
+}
 
-	encodedTitle := url.PathEscape(title)
-	response, err := http.Get(fmt.Sprintf(wikipediaAPITemplate, lang) + encodedTitle)
-	if err != nil {
-		close(done)
-		wg.Wait()
-		fmt.Print("\r")
-		return "", "", false, err
-	}
-	defer response.Body.Close()
+// clearLine erases the first width characters of the current line and
+// returns the cursor to its start. Spaces work on every terminal, unlike
+// the ANSI erase sequence.
+func clearLine(w io.Writer, width int) {
+	fmt.Fprint(w, "\r"+strings.Repeat(" ", width)+"\r")
+}
 
-	body, err := io.ReadAll(response.Body)
-	if err != nil {
-		close(done)
-		wg.Wait()
-		fmt.Print("\r")
-		return "", "", false, err
+func getWikipediaSummary(ctx context.Context, lang, title string) (wiki.CacheEntry, bool, error) {
+	// Try to get the entry from the cache first, the spinner is only
+	// shown for network requests
+	if entry, found := newClient(lang).Cached(title); found {
+		if entry.NotFound {
+			return wiki.CacheEntry{}, true, wiki.ErrArticleNotFound
+		}
+		return formatEntryURL(ctx, lang, entry), true, nil
 	}
 
-	var result map[string]interface{}
-	err = json.Unmarshal(body, &result)
-	if err != nil {
-		close(done)
-		wg.Wait()
-		fmt.Print("\r")
-		return "", "", false, err
+	entry, cached, err := fetchWikipediaSummary(ctx, lang, title)
+	if err == nil {
+		entry = formatEntryURL(ctx, lang, entry)
 	}
-
-	summary := result["extract"].(string)
-	url := result["content_urls"].(map[string]interface{})["desktop"].(map[string]interface{})["page"].(string)
-
-	// Shorten the summary to a maximum of 1000 characters
-	if len(summary) > 1000 {
-		summary = summary[:997] + "..."
+	if err != nil && fetchFailed(ctx, err) {
+		// An expired entry is better than nothing while offline
+		if stale, ok := newClient(lang).Stale(title); ok {
+			logger.Warn("fetching the summary failed, showing the expired cache entry", "title", title, "error", err)
+			return stale, true, nil
+		}
 	}
+	return entry, cached, err
+}
 
-	close(done)
-	wg.Wait()
-	fmt.Print("\r") // Clears the loading animation
+// fetchFailed reports whether err means the summary could not be fetched,
+// as opposed to the article not existing or the user cancelling.
+func fetchFailed(ctx context.Context, err error) bool {
+	return !errors.Is(err, wiki.ErrArticleNotFound) && !errors.Is(err, context.Canceled) && !errors.Is(ctx.Err(), context.Canceled)
+}
 
-	// Cache the new entry
-	setCachedEntry(lang, title, summary, url)
+// fetchWikipediaSummary always requests the summary from the API, without
+// looking at the cache first, and caches the result.
+func fetchWikipediaSummary(ctx context.Context, lang, title string) (wiki.CacheEntry, bool, error) {
+	stopLoading := startLoadingAnimation()
+	defer stopLoading()
+	return newClient(lang).FetchSummary(ctx, title)
+}
 
-	return summary, url, false, nil
+func getWikipediaSection(ctx context.Context, lang, title, sectionName string) (wiki.CacheEntry, bool, error) {
+	stopLoading := startLoadingAnimation()
+	defer stopLoading()
+	return newClient(lang).Section(ctx, title, sectionName)
 }
 
-func clearCache() error {
-	cachePath := getCachePath()
-	err := os.Remove(cachePath)
-	if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("error deleting cache file: %v", err)
-	}
-	if debug {
-		fmt.Println("Cache was deleted successfully.")
-	}
-	return nil
+func searchWikipedia(ctx context.Context, lang, term string) ([]string, error) {
+	return newClient(lang).Search(ctx, term)
+}
+
+func searchWithSuggestion(ctx context.Context, lang, term string) (wiki.SearchResult, error) {
+	return newClient(lang).SearchWithSuggestion(ctx, term)
 }
 
 func main() {
-	flag.Usage = func() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run executes the command line and returns the process exit code.
+func run(args []string) (code int) {
+	flags := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flags.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: %s [options] <search term>\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Options:\n")
-		flag.PrintDefaults()
+		flags.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  %s -lang en -max 10 Golang\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -describe Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -interactive-search -lang en\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -first-sentence Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -words 30 Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -count -json Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -search-limit 50 -max 50 Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -copy Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -pageid 2013\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -random -lang en\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -dry-run en Albert Einstein\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -project wiktionary -lang en serendipity\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -base-url https://wiki.example.com -auth $TOKEN Onboarding\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -section Geschichte Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -full Berlin | less -R\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -full -max-bytes 500000 Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -json -o berlin.json Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -format markdown Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -color-summary Albert Einstein\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -url-format permalink Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -bullets -format markdown Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -template '{{.Title}}: {{.URL}}' Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -diff Berlin,Paris\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -compact Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  open $(%s -url-only Berlin)\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -local-search berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -watch 5m Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -multi -first Berlin Paris Tokyo\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -multi en:Berlin de:München\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -sort alpha Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -batch topics.txt\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -batch topics.txt -concurrency 2\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -lang-fallback de,en Golang\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -lang-detect-query de,en,fr Brexit\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -lang-swap en Golang\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -show-langs Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -lang french Paris\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -lang-guess auto Москва\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -serve -addr localhost:8080\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -serve -metrics\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -pin-ttl 168h Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -modified-since 720h Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -profile Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -trace Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -save-raw /tmp/wikr-raw Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -cache-info de:Berlin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -pin de:Berlin\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -clear-cache\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -clear-expired\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -refresh -concurrency 2\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -history\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -lang-list -json\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -lang-stats\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -completion bash\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -version\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  %d  success\n", exitOK)
+		fmt.Fprintf(os.Stderr, "  %d  no results found or other error\n", exitNoResults)
+		fmt.Fprintf(os.Stderr, "  %d  usage error\n", exitUsage)
+		fmt.Fprintf(os.Stderr, "  %d  network error\n", exitNetwork)
 	}
 
-	lang := flag.String("lang", "de", "language of the Wikipedia")
-	maxResults := flag.Int("max", 5, "maximum amount of result entries")
-	isClearCache := flag.Bool("clear-cache", false, "clear cache and exit")
-	isVersion := flag.Bool("version", false, "show version")
+	lang := flags.String("lang", "de", "language of the Wikipedia")
+	maxResults := flags.Int("max", defaultMaxResults, "maximum amount of result entries (overrides the config file)")
+	flags.Int64Var(&maxBytes, "max-bytes", defaultMaxBytes, "stop reading a response after this many bytes, -full shows the article up to there, 0 for no limit")
+	flags.IntVar(&searchLimit, "search-limit", 0, "collect up to this many search results by following the pages of the search API, e.g. 50 together with -max 50")
+	isClearCache := flags.Bool("clear-cache", false, "clear cache and exit")
+	isClearExpired := flags.Bool("clear-expired", false, "remove only the expired entries from the cache and exit")
+	isRefresh := flags.Bool("refresh", false, "fetch all cached summaries again, -concurrency at a time, report how many changed and exit")
+	isVersion := flags.Bool("version", false, "show version")
+	completionShell := flags.String("completion", "", "print the completion script for this shell and exit: "+strings.Join(completionShells, ", "))
+	isHistory := flags.Bool("history", false, "show recently viewed articles and exit")
+	isInteractiveSearch := flags.Bool("interactive-search", false, "search as you type and pick a live result with the arrow keys and Enter")
+	isLangStats := flags.Bool("lang-stats", false, "show the number, characters and average age of the cached articles per language and exit")
+	isClearHistory := flags.Bool("history-clear", false, "clear history and exit")
+	isLangList := flags.Bool("lang-list", false, "list supported languages and exit")
+	isJSON := flags.Bool("json", false, "print machine-readable JSON output (same as -format json)")
+	flags.BoolVar(&prettyJSON, "json-pretty", false, "print indented JSON output for reading, -json stays compact")
+	format := flags.String("format", "plain", "output format: "+strings.Join(outputFormats, ", "))
+	templateText := flags.String("template", "", "format the result with a Go template, e.g. '{{.Title}}: {{.URL}}'")
+	isDescribe := flags.Bool("describe", false, "print only the one-line description of the article")
+	isFirstSentence := flags.Bool("first-sentence", false, "print only the first sentence of the summary")
+	outFile := flags.String("o", "", "write the result to this file instead of stdout, without colors")
+	flags.StringVar(&urlFormat, "url-format", "pretty", "article URL to show: "+strings.Join(urlFormats, ", ")+", e.g. permalink for citations")
+	isURLOnly := flags.Bool("url-only", false, "print only the article URL, prompts and errors go to stderr")
+	isCompact := flags.Bool("compact", false, "print title, description and URL on a single line without color or spinner")
+	isFull := flags.Bool("full", false, "print the whole article with its section headings instead of the summary")
+	section := flags.String("section", "", "print only the named section of the article")
+	isShowLangs := flags.Bool("show-langs", false, "list the versions of the article in other languages and pick one to read")
+	isCacheInfo := flags.Bool("cache-info", false, "print the cached metadata of one article, e.g. -cache-info de:Berlin, and exit")
+	isLocalSearch := flags.Bool("local-search", false, "search the titles in the cache without network access")
+	watch := flags.Duration("watch", 0, "refetch the summary at this interval (e.g. 5m) and print it when it changed")
+	batchFile := flags.String("batch", "", "look up every search term in the file, one per line")
+	concurrency := flags.Int("concurrency", defaultConcurrency, "number of lookups -batch and -refresh run in parallel (at least 1)")
+	isStrict := flags.Bool("strict", false, "in -batch mode, fail on ambiguous terms instead of using the first result")
+	swapLang := flags.String("lang-swap", "", "after a summary, offer to read the same article in this language, e.g. en")
+	langFallback := flags.String("lang-fallback", "", "languages to try in order when there is no article, e.g. de,en")
+	detectLangs := flags.String("lang-detect-query", "", "search these languages at once, e.g. de,en,fr, and pick the one to read")
+	langGuess := flags.String("lang-guess", "off", "without -lang, guess the language from the script of the search term, e.g. Cyrillic as ru: "+strings.Join(langGuessModes, ", "))
+	isCount := flags.Bool("count", false, "print how many articles match the search term and exit")
+	isMulti := flags.Bool("multi", false, "look up every argument as a separate term, e.g. -multi Berlin Paris Tokyo")
+	sortMode := flags.String("sort", "relevance", "order of the result menu: "+strings.Join(sortModes, ", "))
+	isFirst := flags.Bool("first", false, "use the most relevant result instead of asking when there are several")
+	isDiff := flags.Bool("diff", false, "compare two articles side by side, e.g. -diff Berlin,Paris")
+	width := flags.Int("width", 0, "wrap the summary at this many columns (default: terminal width, at most 100)")
+	isASCII := flags.Bool("ascii", false, "transliterate the output to ASCII (ä to ae) for terminals without UTF-8")
+	noColor := flags.Bool("no-color", false, "disable colored output (also honors NO_COLOR)")
+	flags.BoolVar(&colorSummary, "color-summary", false, "highlight the article title and the subject the summary starts with")
+	theme := flags.String("theme", "default", "color theme: "+strings.Join(themeNames(), ", "))
+	projectName := flags.String("project", wiki.DefaultProject, "Wikimedia project: "+strings.Join(wiki.Projects, ", "))
+	flags.StringVar(&baseURL, "base-url", "", "query this MediaWiki instead of Wikipedia, e.g. https://wiki.example.com, a %s is replaced with the language")
+	auth := flags.String("auth", "", "credentials for -base-url: a bearer token or user:password, $WIKR_AUTH if not given")
+	isDryRun := flags.Bool("dry-run", false, "print the URLs that would be requested and exit without requesting them")
+	isRandom := flags.Bool("random", false, "show the summary of a random article")
+	pageID := flags.Int("pageid", 0, "look up the article with this numeric page ID instead of searching")
+	isServe := flags.Bool("serve", false, "serve a JSON HTTP API with /summary and /search instead of looking up a term")
+	serveAddr := flags.String("addr", defaultServeAddr, "listen address for -serve")
+	isMetrics := flags.Bool("metrics", false, "with -serve, also serve Prometheus metrics on /metrics")
+	flags.BoolVar(&classicMenu, "classic-menu", false, "choose results by number instead of with the arrow keys")
+	flags.IntVar(&summarySentences, "sentences", 0, "shorten the summary to this many sentences instead of 1000 characters")
+	flags.IntVar(&summaryWords, "words", 0, "shorten the summary to this many words instead of 1000 characters")
+	flags.BoolVar(&summaryBullets, "bullets", false, "print every sentence of the summary as a \"- \" bullet point")
+	isCopy := flags.Bool("copy", false, "also copy the article URL to the clipboard")
+	flags.DurationVar(&modifiedSince, "modified-since", 0, "flag articles that were not edited within this duration, e.g. 720h")
+	flags.BoolVar(&noStore, "no-store", false, "read the cache but never write to it (the history is still kept)")
+	flags.DurationVar(&pinTTL, "pin-ttl", 0, "keep the fetched article cached for this long instead of 24h, e.g. 1h or 168h")
+	flags.BoolVar(&pinArticles, "pin", false, "keep the article cached forever, or pin a cached one given as lang:title, e.g. -pin de:Berlin")
+	isUnpin := flags.Bool("unpin", false, "let a pinned article expire again, e.g. -unpin de:Berlin")
+	flags.BoolVar(&hideCachedMarker, "hide-cached-marker", false, "do not mark results that come from the cache")
+	noSpinner := flags.Bool("no-spinner", false, "do not show the loading animation, colors are kept")
+	spinnerStyle := flags.String("spinner", defaultSpinnerStyle, "style of the loading animation: "+strings.Join(spinnerStyleNames(), ", ")+"; none is the same as -no-spinner")
+	flags.StringVar(&rawDir, "save-raw", "", "also write the raw JSON responses of the API to files in this directory")
+	isTrace := flags.Bool("trace", false, "print every HTTP request and response with headers and timing to stderr")
+	isBenchmark := flags.Bool("benchmark", false, "development: fetch a fixed list of articles from the network and then from the cache, and compare the times")
+	isProfile := flags.Bool("profile", false, "print how long the requests and cache accesses took to stderr")
+	var verbose verbosity
+	flags.Var(&verbose, "verbose", "log cache and network diagnostics to stderr, -verbose=debug for more detail")
+	flags.Var(&verbose, "v", "shorthand for -verbose")
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	configureLogger(verbose)
+	defer func() {
+		pinTTL, rawDir, modifiedSince, noStore, searchLimit = 0, "", 0, false, 0
+		maxBytes = 0
+		baseURL, authorization = "", ""
+		prettyJSON = false
+	}()
+	if err := validateFlags(flags); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitUsage
+	}
+	if pinTTL < 0 {
+		fmt.Fprintln(os.Stderr, "Error: -pin-ttl must not be negative")
+		return exitUsage
+	}
+	if searchLimit < 0 {
+		fmt.Fprintln(os.Stderr, "Error: -search-limit must not be negative")
+		return exitUsage
+	}
+	if maxBytes < 0 {
+		fmt.Fprintln(os.Stderr, "Error: -max-bytes must not be negative")
+		return exitUsage
+	}
+	if modifiedSince < 0 {
+		fmt.Fprintln(os.Stderr, "Error: -modified-since must not be negative")
+		return exitUsage
+	}
+	if err := checkSortMode(*sortMode); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitUsage
+	}
+	netrc, err := loadNetrc(netrcPath())
+	if err != nil {
+		logger.Warn("cannot read the netrc file", "error", err)
+	}
+	if len(netrc) > 0 {
+		netrcTransport, err = newNetrcTransport(netrc)
+		if err != nil {
+			logger.Warn("cannot use the proxy credentials of the netrc file", "error", err)
+		}
+		defer func() { netrcTransport = nil }()
+	}
+	if *isTrace {
+		var next http.RoundTripper = wiki.DefaultTransport
+		if netrcTransport != nil {
+			next = netrcTransport
+		}
+		tracer = newTraceTransport(next, os.Stderr)
+		defer func() { tracer = nil }()
+	}
+	if *isProfile {
+		timings = newProfiler()
+		defer func() {
+			timings.print(os.Stderr)
+			timings = nil
+		}()
+	}
+	config := loadConfig()
+	cacheMode = config.cacheFileMode()
+	cacheCompression = config.CompressCache
+	adaptiveMinTTL, adaptiveMaxTTL = config.adaptiveTTLBounds()
+	defer func() {
+		cacheCompression = false
+		adaptiveMinTTL, adaptiveMaxTTL = 0, 0
+	}()
+
+	if err := configureColor(*noColor || *isCompact || *outFile != "", *theme); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitUsage
+	}
+
+	if err := checkURLFormat(urlFormat); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitUsage
+	}
+	if err := checkLangGuess(*langGuess); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitUsage
+	}
+	if !containsString(wiki.Projects, *projectName) {
+		fmt.Fprintf(os.Stderr, "Error: unknown project %q, available projects: %s\n", *projectName, strings.Join(wiki.Projects, ", "))
+		return exitUsage
+	}
+	project = *projectName
+	// Names like "german" are accepted for the codes, a wiki behind
+	// -base-url may use codes wikr does not know
+	if code, err := resolveLanguage(*lang); err == nil {
+		*lang = code
+	} else if baseURL == "" {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitUsage
+	}
+	if *swapLang != "" {
+		code, err := resolveLanguage(*swapLang)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: -lang-swap:", err)
+			return exitUsage
+		}
+		*swapLang = code
+	}
+	if baseURL != "" {
+		if err := checkBaseURL(baseURL); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return exitUsage
+		}
+		project = baseURLProject(baseURL)
+		// The environment keeps the credentials out of the process list
+		if *auth == "" {
+			*auth = os.Getenv("WIKR_AUTH")
+		}
+		if *auth == "" {
+			authorization = netrcAuthorization(netrc, baseURLHost(baseURL, *lang))
+		}
+	}
+	if *auth != "" {
+		// Credentials for a private wiki must not be sent to Wikipedia
+		if baseURL == "" {
+			fmt.Fprintln(os.Stderr, "Error: -auth needs -base-url")
+			return exitUsage
+		}
+		authorization = authorizationHeader(*auth)
+	}
 
-	flag.Parse()
+	if *isJSON || prettyJSON {
+		*format = "json"
+	}
+	// resultOut receives the results, prompts and progress stay on the
+	// terminal
+	var resultOut io.Writer = os.Stdout
+	if *outFile != "" {
+		file, err := os.Create(*outFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return exitUsage
+		}
+		defer func() {
+			if err := file.Close(); err != nil && code == exitOK {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				code = exitNoResults
+			}
+		}()
+		resultOut = file
+	}
+	if *isASCII {
+		resultOut = asciiWriter{out: resultOut}
+	} else if *outFile == "" {
+		warnNonUTF8Terminal()
+	}
+	output, err := newOutputWriter(*format, resultOut, summaryWidth(*width))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitUsage
+	}
+	if *templateText != "" {
+		output, err = newTemplateWriter(resultOut, *templateText)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return exitUsage
+		}
+	}
+	if *isDescribe {
+		output = &descriptionWriter{out: resultOut}
+	}
+	if *isFirstSentence {
+		// Cut by sentence instead of the 1000 characters
+		summarySentences = 1
+		output = &sentenceWriter{out: resultOut}
+	}
+	if *isCompact {
+		compactWidth := *width
+		if compactWidth <= 0 {
+			compactWidth = terminalWidth()
+		}
+		output = &compactWriter{out: resultOut, width: compactWidth}
+	}
+	if *isURLOnly {
+		output = &urlWriter{out: resultOut}
+		promptOut = os.Stderr
+		defer func() { promptOut = os.Stdout }()
+	}
+	// In the single-line modes the spinner would end up in the output
+	if err := setSpinnerStyle(*spinnerStyle); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitUsage
+	}
+	defer func() { spinnerFrames = spinnerStyles[defaultSpinnerStyle] }()
+	if *isCompact || *isURLOnly || *noSpinner || *spinnerStyle == "none" {
+		spinnerEnabled = false
+		defer func() { spinnerEnabled = true }()
+	}
 
 	if *isClearCache {
-		err := clearCache()
+		err := fileCache().Clear()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			fmt.Fprintln(os.Stderr, err)
+			return exitNoResults
 		}
 		fmt.Println("Cache cleared.")
-		return
+		return exitOK
 	}
 
-	if len(os.Args) < 2 {
-        fmt.Fprintf(os.Stderr, "Error: search term is required\n")
-        flag.Usage()
-        os.Exit(1)
-    }
+	if *isClearExpired {
+		removed := fileCache().ClearExpired()
+		fmt.Println("Expired cache entries removed:", removed)
+		return exitOK
+	}
+
+	if *isClearHistory {
+		err := clearHistory()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitNoResults
+		}
+		fmt.Println("History cleared.")
+		return exitOK
+	}
+
+	if *isLangStats {
+		stats := collectLangStats(fileCache().Load(), time.Now())
+		if err := printLangStats(os.Stdout, stats, *format == "json"); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitNoResults
+		}
+		return exitOK
+	}
+
+	if *isHistory {
+		printHistory(loadHistory())
+		return exitOK
+	}
 
 	if *isVersion {
 		fmt.Println("Version:", version)
-		return
+		return exitOK
+	}
+
+	if *completionShell != "" {
+		if err := writeCompletion(os.Stdout, *completionShell, flags); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	if *isLangList {
+		err := printLanguages(*format == "json")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitNoResults
+		}
+		return exitOK
 	}
 
-	var searchTermParts []string
+	// Ctrl-C or SIGTERM aborts an in-flight request instead of waiting for
+	// it, and ends the program while it waits at a prompt
+	ctx, stop := signal.NotifyContext(context.Background(), shutdownSignals...)
+	defer stop()
+	go exitOnSignalWhileWaiting(ctx, os.Exit)
 
-	if os.Args[1] == "de" || os.Args[1] == "en" {
-		*lang = os.Args[1]
-		searchTermParts = os.Args[2:]
-	} else if os.Args[1] == "-lang" {
-		*lang = os.Args[2]
-		searchTermParts = os.Args[3:]
-	} else {
-		searchTermParts = os.Args[1:]
+	if *isServe {
+		return runServe(ctx, *serveAddr, *lang, *isMetrics)
+	}
+	if *isBenchmark {
+		return runBenchmark(ctx, *lang, benchmarkTitles, resultOut)
+	}
+	if *isRefresh {
+		return runRefresh(ctx, *concurrency, resultOut)
 	}
 
-	if len(searchTermParts) == 0 {
-		fmt.Println("Please provide a search term.")
-		os.Exit(1)
+	var searchTerm string
+	*lang, searchTerm = parseSearchArgs(flags.Args(), *lang)
+
+	if *batchFile != "" && *isDryRun {
+		terms, err := readBatchTerms(*batchFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+		return printDryRun(os.Stdout, dryRunURLs([]string{*lang}, terms, ""))
+	}
+	if *batchFile != "" {
+		return runBatch(ctx, *lang, *batchFile, *isStrict, *concurrency, output, resultOut, *format == "plain" && !*isDescribe && !*isFirstSentence)
 	}
 
-	searchTerm := strings.Join(searchTermParts, " ")
-	encodedSearchTerm := url.QueryEscape(searchTerm)
+	if *pageID < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -pageid must be a positive number\n")
+		return exitUsage
+	}
+	if *isRandom && *isDryRun {
+		return printDryRun(os.Stdout, []string{newClient(*lang).RandomURL()})
+	}
+	if *isRandom {
+		return runRandom(ctx, *lang, output, *isCopy)
+	}
 
-	// Search for possible results
-	searchResults, err := searchWikipedia(*lang, encodedSearchTerm)
+	if *pageID > 0 && *isDryRun {
+		return printDryRun(os.Stdout, []string{newClient(*lang).PageIDURL(*pageID)})
+	}
+	if *pageID > 0 {
+		return runPageID(ctx, *lang, *pageID, output, *isCopy)
+	}
+
+	// Whitespace-only terms would only produce an empty search, the
+	// interactive search asks for its own
+	if searchTerm == "" && !*isInteractiveSearch {
+		fmt.Fprintf(os.Stderr, "Error: search term is required\n")
+		flags.Usage()
+		return exitUsage
+	}
+
+	// An explicit -max wins over the config file, an explicit -lang over
+	// the guess from the script of the term
+	maxSet, langSet := false, false
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "max":
+			maxSet = true
+		case "lang":
+			langSet = true
+		}
+	})
+	// A leading "de" or "en" is as explicit as -lang
+	if _, args := splitLangShortcut(flags.Args(), *lang); len(args) < flags.NArg() {
+		langSet = true
+	}
+	if !langSet && *detectLangs == "" && baseURL == "" && !*isInteractiveSearch {
+		*lang = applyLangGuess(*langGuess, *lang, searchTerm, askLangGuess)
+	}
+	if !maxSet {
+		*maxResults = loadConfig().maxResultsFor(*lang)
+	}
+
+	if *isInteractiveSearch {
+		return runInteractiveSearch(ctx, *lang, maxResults, output, *isCopy)
+	}
+
+	if *isCount && *isDryRun {
+		return printDryRun(os.Stdout, []string{newClient(*lang).CountURL(searchTerm)})
+	}
+	if *isCount {
+		return runCount(ctx, *lang, searchTerm, *format == "json", resultOut)
+	}
+
+	if *isCacheInfo {
+		return runCacheInfo(project, *lang, searchTerm, resultOut)
+	}
+	if *isUnpin {
+		return runPin(*lang, searchTerm, false, resultOut)
+	}
+	if pinArticles && hasLangPrefix(searchTerm) {
+		return runPin(*lang, searchTerm, true, resultOut)
+	}
+	if *isLocalSearch {
+		return runLocalSearch(searchTerm, maxResults, output)
+	}
+
+	langs, err := parseLangFallback(*lang, *langFallback)
 	if err != nil {
-		fmt.Println("Error during search:", err)
-		os.Exit(1)
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitUsage
+	}
+	var detect []string
+	if *detectLangs != "" {
+		detect, err = parseDetectLangs(*detectLangs)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return exitUsage
+		}
+		langs = detect
+	}
+	if *isDryRun {
+		terms := []string{searchTerm}
+		if *isMulti && !*isDiff {
+			_, args := parseMultiArgs(flags.Args(), *lang)
+			multiTerms, err := parseMultiTerms(args, *lang)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				return exitUsage
+			}
+			// Prefixed terms are looked up in their language only
+			var urls []string
+			for _, term := range multiTerms {
+				termLangs := langs
+				if term.Lang != *lang {
+					termLangs = []string{term.Lang}
+				}
+				urls = append(urls, dryRunURLs(termLangs, []string{term.Term}, *section)...)
+			}
+			return printDryRun(os.Stdout, urls)
+		}
+		if *isDiff {
+			terms, err = parseDiffTerms(searchTerm)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				return exitUsage
+			}
+		}
+		return printDryRun(os.Stdout, dryRunURLs(langs, terms, *section))
 	}
 
-	if len(searchResults) == 0 {
-		fmt.Println("No results found.")
-		os.Exit(1)
+	if *isDiff {
+		return runDiff(ctx, *lang, searchTerm)
 	}
 
-	var selectedTitle string
-	if len(searchResults) == 1 {
-		selectedTitle = searchResults[0]
-	} else {
-		selectedTitle = chooseResult(searchResults, maxResults)
+	if *isMulti {
+		_, args := parseMultiArgs(flags.Args(), *lang)
+		terms, err := parseMultiTerms(args, *lang)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return exitUsage
+		}
+		return runMulti(ctx, terms, maxResults, *isFirst, output, resultOut, *format == "plain" && !*isDescribe && !*isFirstSentence)
 	}
 
-	// Get the summary for the selected title
-	summary, url, cached, err := getWikipediaSummary(*lang, selectedTitle)
+	// Search for possible results, in the fallback languages if necessary
+	// Offering a corrected spelling needs someone to answer
+	confirm := askDidYouMean
+	if *isFirst || *isCompact || *isURLOnly {
+		confirm = nil
+	}
+	search := func(ctx context.Context, langs []string, term string) ([]string, string, error) {
+		return searchWithFallback(ctx, langs, term, confirm)
+	}
+	if detect != nil {
+		search = func(ctx context.Context, langs []string, term string) ([]string, string, error) {
+			return detectLanguage(ctx, langs, term, clampConcurrency(*concurrency))
+		}
+	}
+	searchResults, searchLang, err := search(ctx, langs, searchTerm)
+	*lang = searchLang
 	if err != nil {
-		color.Red("Error fetching summary: %v", err)
-		os.Exit(1)
+		fmt.Fprintln(os.Stderr, "Error during search:", err)
+		return exitNetwork
 	}
 
-	color.Blue("\n\nSummary:")
-	if cached {
-		color.Yellow("(cached)")
+	if len(searchResults) == 0 {
+		fmt.Fprintln(os.Stderr, "No results found.")
+		return exitNoResults
+	}
+
+	fetch := getWikipediaSummary
+	if *section != "" {
+		fetch = func(ctx context.Context, lang, title string) (wiki.CacheEntry, bool, error) {
+			return getWikipediaSection(ctx, lang, title, *section)
+		}
 	}
-	fmt.Println(summary)
-	color.Green("\nURL:")
-	fmt.Println(url)
+	if len(langs) > 1 && detect == nil {
+		fetch = fallbackFetcher(langs, fetch)
+	}
+
+	// A single line leaves no room for the result menu
+	if *isCompact || *isFirst {
+		searchResults = searchResults[:1]
+	}
+	searchResults = sortResults(searchResults, *sortMode, *maxResults)
+
+	if *isFull {
+		title := searchResults[0]
+		if len(searchResults) > 1 {
+			title = chooseResult(searchResults, maxResults)
+		}
+		return runArticle(ctx, *lang, title, resultOut, summaryWidth(*width))
+	}
+
+	if *isShowLangs {
+		title := searchResults[0]
+		if len(searchResults) > 1 {
+			title = chooseResult(searchResults, maxResults)
+		}
+		return runShowLangs(ctx, *lang, title, output, resultOut, *format == "json", *isCopy)
+	}
+
+	if *watch > 0 {
+		title := searchResults[0]
+		if len(searchResults) > 1 {
+			title = chooseResult(searchResults, maxResults)
+		}
+		watchSummary(ctx, *lang, title, *watch, fetchWikipediaSummary, output, os.Stdout)
+		return exitOK
+	}
+
+	state := &searchState{Lang: *lang, Results: searchResults, Output: output, CopyURL: *isCopy, Once: *isURLOnly, SwapLang: *swapLang, FullText: *section != ""}
+	if err := state.run(ctx, maxResults, fetch); err != nil {
+		activeTheme.Error.Fprintf(os.Stderr, "Error fetching summary: %v\n", err)
+		return exitCodeFor(err)
+	}
+	return exitOK
 }
 
-func searchWikipedia(lang, term string) ([]string, error) {
-	response, err := http.Get(fmt.Sprintf(wikipediaSearchAPITemplate, lang, term))
-	if err != nil {
-		return nil, err
+// exitCodeFor maps a lookup error to the documented exit code.
+func exitCodeFor(err error) int {
+	if errors.Is(err, wiki.ErrArticleNotFound) || errors.Is(err, wiki.ErrNoResults) {
+		return exitNoResults
 	}
-	defer response.Body.Close()
+	return exitNetwork
+}
 
-	body, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, err
+type summaryFetcher func(ctx context.Context, lang, title string) (wiki.CacheEntry, bool, error)
+
+// searchState keeps the search results and the chosen title around so the
+// user can pick another result without searching again.
+type searchState struct {
+	Lang     string
+	Results  []string
+	Selected string
+	// Output displays the fetched entry, plain text is used when nil
+	Output OutputWriter
+	// CopyURL copies the URL of each displayed article to the clipboard
+	CopyURL bool
+	// Once skips the offer to go back to the results
+	Once bool
+	// SwapLang is offered to read the shown article in, set by -lang-swap
+	SwapLang string
+	// FullText skips the default cut to 1000 characters, which is meant
+	// for summaries, set by -section
+	FullText bool
+}
+
+// run lets the user choose a result and displays its summary. A failed
+// fetch re-prompts from the same results, and after a summary has been
+// shown the user may go back and pick another one, or read the article
+// in SwapLang.
+func (s *searchState) run(ctx context.Context, maxResults *int, fetch summaryFetcher) error {
+	for {
+		if len(s.Results) == 1 {
+			s.Selected = s.Results[0]
+		} else {
+			s.Selected = chooseResult(s.Results, maxResults)
+		}
+
+		// Get the summary for the selected title
+		entry, cached, err := fetch(ctx, s.Lang, s.Selected)
+		if err != nil {
+			// An interrupted request must not lead back to the prompt
+			if len(s.Results) == 1 || ctx.Err() != nil {
+				return err
+			}
+			activeTheme.Error.Fprintf(os.Stderr, "Error fetching summary: %v\n", err)
+			continue
+		}
+		if err := s.show(s.Lang, entry, cached); err != nil {
+			return err
+		}
+
+		lang := s.Lang
+	prompt:
+		for {
+			offerSwap := s.SwapLang != "" && s.SwapLang != lang
+			switch askNext(len(s.Results) > 1 && !s.Once, offerSwap, s.SwapLang) {
+			case nextBack:
+				break prompt
+			case nextSwap:
+				entry, cached, err := fetch(ctx, s.SwapLang, s.Selected)
+				if err != nil {
+					if ctx.Err() != nil {
+						return err
+					}
+					activeTheme.Error.Fprintf(os.Stderr, "Error fetching summary: %v\n", err)
+					continue
+				}
+				lang = s.SwapLang
+				if err := s.show(lang, entry, cached); err != nil {
+					return err
+				}
+			default:
+				return nil
+			}
+		}
 	}
+}
 
-	var result map[string]interface{}
-	err = json.Unmarshal(body, &result)
-	if err != nil {
-		return nil, err
+// show displays the entry fetched for the selected title in lang.
+func (s *searchState) show(lang string, entry wiki.CacheEntry, cached bool) error {
+	result := newResult(lang, s.Selected, entry, cached)
+	if s.FullText {
+		result.Summary = shortenText(entry.Summary)
+	}
+	addHistoryEntry(result.Lang, s.Selected)
+	output := s.Output
+	if output == nil {
+		output = &plainWriter{out: os.Stdout}
+	}
+	if err := output.Write(result); err != nil {
+		return err
+	}
+	if s.CopyURL {
+		copyURL(result.URL)
 	}
+	return nil
+}
+
+// parseSearchArgs splits the positional arguments into an optional
+// language shortcut and the trimmed search term.
+func parseSearchArgs(args []string, lang string) (string, string) {
+	lang, args = splitLangShortcut(args, lang)
+	return lang, strings.TrimSpace(strings.Join(args, " "))
+}
 
-	searchResults := result["query"].(map[string]interface{})["search"].([]interface{})
-	titles := make([]string, len(searchResults))
-	for i, item := range searchResults {
-		titles[i] = item.(map[string]interface{})["title"].(string)
+// splitLangShortcut removes a leading "de" or "en" from the arguments and
+// returns it as the language.
+func splitLangShortcut(args []string, lang string) (string, []string) {
+	if len(args) > 0 && (args[0] == "de" || args[0] == "en") {
+		return args[0], args[1:]
 	}
+	return lang, args
+}
 
-	return titles, nil
+// lookupTerm searches for the term and fetches the summary of the most
+// relevant result without prompting. In strict mode a term with several
+// results and no exact match is an error instead.
+func lookupTerm(ctx context.Context, lang, term string, strict bool) (Result, error) {
+	titles, err := searchWikipedia(ctx, lang, term)
+	if err != nil {
+		return Result{}, err
+	}
+	if len(titles) == 0 {
+		return Result{}, fmt.Errorf("%w for %q", wiki.ErrNoResults, term)
+	}
+	if strict && len(titles) > 1 && !strings.EqualFold(titles[0], term) {
+		return Result{}, fmt.Errorf("%q is ambiguous, candidates: %s", term, strings.Join(titles, ", "))
+	}
+	entry, cached, err := getWikipediaSummary(ctx, lang, titles[0])
+	if err != nil {
+		return Result{}, err
+	}
+	return newResult(lang, titles[0], entry, cached), nil
+}
+
+func lookupFirstResult(ctx context.Context, lang, term string) (Result, error) {
+	return lookupTerm(ctx, lang, term, false)
 }
 
 func chooseResult(results []string, maxResults *int) string {
 	if len(results) > *maxResults {
 		results = results[:*maxResults]
 	}
-	fmt.Println("\nMultiple results found. Please choose one:")
+
+	// The numeric prompt below is the fallback if raw mode is unavailable
+	if arrowMenuAvailable() {
+		choice, ok, err := selectWithArrows(results)
+		if err == nil {
+			if !ok {
+				fmt.Fprintln(promptOut, "\nProgram was exited.")
+				os.Exit(0)
+			}
+			return choice
+		}
+	}
+
+	fmt.Fprintln(promptOut, "\nMultiple results found. Please choose one:")
 	for i, result := range results {
-		fmt.Printf("%d. %s\n", i+1, result)
+		fmt.Fprintf(promptOut, "%d. %s\n", i+1, result)
 	}
-	fmt.Println("q. Quit")
+	fmt.Fprintln(promptOut, "q. Quit")
 
-	reader := bufio.NewReader(os.Stdin)
 	for {
-		fmt.Println("\nEnter the number of the desired result (or 'q' to quit): ")
-		input, _ := reader.ReadString('\n')
+		fmt.Fprintln(promptOut, "\nEnter the number of the desired result (or 'q' to quit): ")
+		done := waitForInput()
+		input, _ := stdinReader.ReadString('\n')
+		done()
 		input = strings.TrimSpace(input)
 
 		if input == "q" {
-			fmt.Println("\nProgram was exited.")
+			fmt.Fprintln(promptOut, "\nProgram was exited.")
 			os.Exit(0)
 		}
 
@@ -341,24 +1069,6 @@ func chooseResult(results []string, maxResults *int) string {
 		if err == nil && index > 0 && index <= len(results) {
 			return results[index-1]
 		}
-		fmt.Println("\nInvalid input. Please try again.")
-	}
-}
-
-func createEmptyCacheFileIfNotExists() {
-	cachePath := getCachePath()
-	if _, err := os.Stat(cachePath); os.IsNotExist(err) {
-		emptyCache := make(Cache)
-		data, err := json.Marshal(emptyCache)
-		if err != nil && debug {
-			fmt.Printf("Error creating empty cache file: %v\n", err)
-			return
-		}
-		err = os.WriteFile(cachePath, data, 0644)
-		if err != nil && debug {
-			fmt.Printf("Error writing empty cache file %s: %v\n", cachePath, err)
-		} else if debug {
-			fmt.Printf("Empty cache file was created: %s\n", cachePath)
-		}
+		fmt.Fprintln(promptOut, "\nInvalid input. Please try again.")
 	}
 }
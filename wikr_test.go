@@ -4,131 +4,350 @@ import (
 	"testing"
 	"os"
 	"time"
-)
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"net/http"
+	"net/http/httptest"
 
-func TestMain(m *testing.M) {
-	createEmptyCacheFileIfNotExists()
+	"github.com/SvenSchneiderDVAG/wikr/pkg/wiki"
+)
 
-	// Run tests
-	code := m.Run()
+func TestParseSearchArgs(t *testing.T) {
+	lang, term := parseSearchArgs([]string{"en", "Albert", "Einstein"}, "de")
+	if lang != "en" {
+		t.Errorf("Erwartete Sprache 'en', erhielt '%s'", lang)
+	}
+	if term != "Albert Einstein" {
+		t.Errorf("Erwarteter Suchbegriff 'Albert Einstein', erhielt '%s'", term)
+	}
 
-	// Teardown
-	os.Remove(getCachePath())
-	os.Exit(code)
+	lang, term = parseSearchArgs([]string{"Eiffelturm"}, "de")
+	if lang != "de" || term != "Eiffelturm" {
+		t.Errorf("Erwartete 'de' und 'Eiffelturm', erhielt '%s' und '%s'", lang, term)
+	}
 }
 
-func TestGetCachePath(t *testing.T) {
-	path := getCachePath()
-	if path == "" {
-		t.Error("getCachePath sollte einen nicht-leeren Pfad zurückgeben")
+func TestParseSearchArgsWhitespaceOnly(t *testing.T) {
+	_, term := parseSearchArgs([]string{"   ", "\t"}, "de")
+	if term != "" {
+		t.Errorf("Ein Suchbegriff nur aus Leerzeichen sollte leer sein, erhielt '%s'", term)
 	}
-}
 
-func TestLoadAndSaveCache(t *testing.T) {
-	// Erstelle einen Test-Cache
-	testCache := Cache{
-		"de:Test": CacheEntry{
-			Summary:   "Dies ist ein Test",
-			URL:       "https://de.wikipedia.org/wiki/Test",
-			Timestamp: time.Now(),
-		},
+	_, term = parseSearchArgs([]string{"en", "  "}, "de")
+	if term != "" {
+		t.Errorf("Ein Suchbegriff nur aus Leerzeichen sollte leer sein, erhielt '%s'", term)
 	}
+}
+
+func TestSearchStateRepromptsAfterFetchError(t *testing.T) {
+	setTestHome(t)
 
-	// Speichere den Test-Cache
-	saveCache(testCache)
+	// Erst wird "Berlin" gewählt, dessen Abruf fehlschlägt, dann "Paris"
+	stdinReader = bufio.NewReader(strings.NewReader("1\n2\n\n"))
+	defer func() { stdinReader = bufio.NewReader(os.Stdin) }()
 
-	// Lade den Cache
-	loadedCache := loadCache()
+	var fetched []string
+	fetch := func(ctx context.Context, lang, title string) (wiki.CacheEntry, bool, error) {
+		fetched = append(fetched, title)
+		if title == "Berlin" {
+			return wiki.CacheEntry{}, false, errors.New("Netzwerkfehler")
+		}
+		return wiki.CacheEntry{Summary: "Paris ist die Hauptstadt Frankreichs.", URL: "https://de.wikipedia.org/wiki/Paris"}, false, nil
+	}
 
-	// Überprüfe, ob der geladene Cache den Test-Eintrag enthält
-	entry, exists := loadedCache["de:Test"]
-	if !exists {
-		t.Error("Der geladene Cache sollte den Test-Eintrag enthalten")
+	maxResults := 5
+	state := &searchState{Lang: "de", Results: []string{"Berlin", "Paris"}}
+	if err := state.run(context.Background(), &maxResults, fetch); err != nil {
+		t.Fatalf("run sollte keinen Fehler zurückgeben: %v", err)
 	}
 
-	if entry.Summary != "Dies ist ein Test" {
-		t.Errorf("Erwartete Zusammenfassung 'Dies ist ein Test', erhielt '%s'", entry.Summary)
+	if len(fetched) != 2 || fetched[0] != "Berlin" || fetched[1] != "Paris" {
+		t.Errorf("Erwartete Abrufe [Berlin Paris], erhielt %v", fetched)
 	}
 
-	// Lösche den Test-Eintrag aus dem Cache
-	delete(loadedCache, "de:Test")
-	saveCache(loadedCache)
+	if state.Selected != "Paris" {
+		t.Errorf("Erwartete Auswahl 'Paris', erhielt '%s'", state.Selected)
+	}
 }
 
-func TestGetAndSetCachedEntry(t *testing.T) {
-	// Setze einen Test-Eintrag
-	setCachedEntry("de", "TestArtikel", "Dies ist ein Test-Artikel", "https://de.wikipedia.org/wiki/TestArtikel")
+func TestSearchStateSingleResultError(t *testing.T) {
+	fetch := func(ctx context.Context, lang, title string) (wiki.CacheEntry, bool, error) {
+		return wiki.CacheEntry{}, false, errors.New("Netzwerkfehler")
+	}
+
+	maxResults := 5
+	state := &searchState{Lang: "de", Results: []string{"Berlin"}}
+	if err := state.run(context.Background(), &maxResults, fetch); err == nil {
+		t.Error("Bei nur einem Ergebnis sollte der Fehler zurückgegeben werden")
+	}
+}
 
-	// Hole den Test-Eintrag
-	summary, url, found := getCachedEntry("de", "TestArtikel")
+func TestRunExitCodes(t *testing.T) {
+	setTestHome(t)
 
-	if !found {
-		t.Error("Der Test-Eintrag sollte im Cache gefunden werden")
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"Version", []string{"-version"}, exitOK},
+		{"Hilfe", []string{"-h"}, exitOK},
+		{"Sprachliste", []string{"-lang-list"}, exitOK},
+		{"Ohne Suchbegriff", []string{}, exitUsage},
+		{"Nur Leerzeichen", []string{"   "}, exitUsage},
+		{"Nur Sprache", []string{"en"}, exitUsage},
+		{"Unbekannte Option", []string{"-unbekannt"}, exitUsage},
+		{"Negative Seiten-ID", []string{"-pageid", "-5"}, exitUsage},
+		{"Unbekannte Sprache", []string{"-lang", "klingonisch", "Berlin"}, exitUsage},
+		{"Unbekanntes URL-Format", []string{"-url-format", "kurz", "Berlin"}, exitUsage},
+		{"Unbekannte Tauschsprache", []string{"-lang-swap", "xx", "Berlin"}, exitUsage},
+		{"Unbekanntes Projekt", []string{"-project", "wikifoo", "Berlin"}, exitUsage},
+		{"Negative TTL", []string{"-pin-ttl", "-1h", "Berlin"}, exitUsage},
+		{"Negatives Suchlimit", []string{"-search-limit", "-5", "Berlin"}, exitUsage},
+		{"Sätze und Wörter", []string{"-sentences", "2", "-words", "30", "Berlin"}, exitUsage},
+		{"Ungültige Vorlage", []string{"-template", "{{.Titel}}", "Berlin"}, exitUsage},
+		{"Unbekannte Sortierung", []string{"-sort", "random", "Berlin"}, exitUsage},
+		{"Erster Satz und Sätze", []string{"-first-sentence", "-sentences", "2", "Berlin"}, exitUsage},
+		{"Unbekannter Spinner", []string{"-spinner", "wirbel", "Berlin"}, exitUsage},
+		{"JSON und Vorlage", []string{"-json", "-template", "{{.Title}}", "Berlin"}, exitUsage},
+		{"URL und Beschreibung", []string{"-url-only", "-describe", "Berlin"}, exitUsage},
 	}
 
-	if summary != "Dies ist ein Test-Artikel" {
-		t.Errorf("Erwartete Zusammenfassung 'Dies ist ein Test-Artikel', erhielt '%s'", summary)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := run(test.args); got != test.want {
+				t.Errorf("run(%q): erwarteter Exit-Code %d, erhielt %d", test.args, test.want, got)
+			}
+		})
 	}
+}
 
-	if url != "https://de.wikipedia.org/wiki/TestArtikel" {
-		t.Errorf("Erwartete URL 'https://de.wikipedia.org/wiki/TestArtikel', erhielt '%s'", url)
+func TestDescribe(t *testing.T) {
+	result := Result{
+		Summary:     "Berlin ist die Hauptstadt der Bundesrepublik Deutschland.",
+		Description: "Hauptstadt der Bundesrepublik Deutschland",
 	}
 
-	// Lösche die Test-Cache-Datei
-	os.Remove(getCachePath())
+	if got := describe(result); got != result.Description {
+		t.Errorf("describe sollte die Beschreibung zurückgeben, erhielt '%s'", got)
+	}
 }
 
-func TestSearchWikipedia(t *testing.T) {
-	results, err := searchWikipedia("de", "Berlin")
+func TestDescribeFallsBackToFirstSentence(t *testing.T) {
+	result := Result{Summary: "Go ist eine Programmiersprache. Sie wurde bei Google entwickelt."}
 
-	if err != nil {
-		t.Errorf("searchWikipedia sollte keinen Fehler zurückgeben: %v", err)
+	if got := describe(result); got != "Go ist eine Programmiersprache." {
+		t.Errorf("Erwartete den ersten Satz, erhielt '%s'", got)
 	}
+}
+
+func TestNegativeCacheHit(t *testing.T) {
+	setTestHome(t)
+
+	fileCache().Set("de", "Gibtesnicht", wiki.CacheEntry{NotFound: true})
 
-	if len(results) == 0 {
-		t.Error("searchWikipedia sollte Ergebnisse für 'Berlin' zurückgeben")
+	// Der negative Eintrag sollte ohne Netzwerkzugriff zu "nicht gefunden" führen
+	_, cached, err := getWikipediaSummary(context.Background(), "de", "Gibtesnicht")
+	if !errors.Is(err, wiki.ErrArticleNotFound) {
+		t.Errorf("Erwartete wiki.ErrArticleNotFound, erhielt %v", err)
 	}
+	if !cached {
+		t.Error("Der negative Eintrag sollte aus dem Cache kommen")
+	}
+}
+
+func TestGetWikipediaSummaryCanceled(t *testing.T) {
+	setTestHome(t)
 
-	foundBerlin := false
-	for _, result := range results {
-		if result == "Berlin" {
-			foundBerlin = true
-			break
+	// Der Stub antwortet erst, wenn der Test beendet ist
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
 		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, _, err := getWikipediaSummary(ctx, "de", "Berlin")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Erwartete context.Canceled, erhielt %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Error("Die Anfrage sollte nach dem Abbruch sofort zurückkehren")
 	}
+}
+
+func TestNoSpinner(t *testing.T) {
+	setTestHome(t)
+
+	// Der Stub antwortet langsam genug, dass der Spinner sichtbar würde
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("list") == "search" {
+			fmt.Fprint(w, `{"query": {"search": [{"title": "Berlin"}]}}`)
+			return
+		}
+		time.Sleep(250 * time.Millisecond)
+		fmt.Fprint(w, `{"title": "Berlin", "titles": {"canonical": "Berlin"}, "extract": "Berlin ist die Hauptstadt Deutschlands.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+	}))
+	defer server.Close()
 
-	if !foundBerlin {
-		t.Error("'Berlin' sollte in den Suchergebnissen enthalten sein")
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	// -spinner none verhält sich wie -no-spinner
+	for _, flag := range [][]string{{"-no-spinner"}, {"-spinner", "none"}} {
+		setTestHome(t)
+		var code int
+		stdout, _ := captureOutput(t, func() {
+			code = run(append(flag, "Berlin"))
+		})
+		if code != exitOK {
+			t.Fatalf("%v: Exit-Code = %d, erwartet %d", flag, code, exitOK)
+		}
+		if strings.Contains(stdout, "Loading data") || strings.Contains(stdout, "\r") {
+			t.Errorf("Mit %v sollten keine Spinner-Frames erscheinen: %q", flag, stdout)
+		}
+		if !strings.Contains(stdout, "Berlin ist die Hauptstadt") {
+			t.Errorf("Die Zusammenfassung sollte trotzdem erscheinen: %q", stdout)
+		}
+		if !spinnerEnabled || len(spinnerFrames) == 0 {
+			t.Error("Der Spinner sollte nach run wieder aktiviert sein")
+		}
 	}
 }
 
-func TestGetWikipediaSummary(t *testing.T) {
-	summary, url, cached, err := getWikipediaSummary("de", "Berlin")
+func TestClearLine(t *testing.T) {
+	for _, width := range []int{0, 5, len(loadingMessage) + 1, 120} {
+		var output strings.Builder
+		clearLine(&output, width)
 
-	if err != nil {
-		t.Errorf("getWikipediaSummary sollte keinen Fehler zurückgeben: %v", err)
+		want := "\r" + strings.Repeat(" ", width) + "\r"
+		if output.String() != want {
+			t.Errorf("clearLine(%d) = %q, erwartet %q", width, output.String(), want)
+		}
 	}
+}
+
+func TestRunNoStore(t *testing.T) {
+	setTestHome(t)
+	fileCache().Set("de", "Paris", wiki.CacheEntry{Title: "Paris", Summary: "Paris aus dem Cache.", URL: "https://de.wikipedia.org/wiki/Paris"})
 
-	if summary == "" {
-		t.Error("Die Zusammenfassung sollte nicht leer sein")
+	var summaryRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/w/api.php") {
+			fmt.Fprintf(w, `{"query": {"search": [{"title": %q}]}}`, r.URL.Query().Get("srsearch"))
+			return
+		}
+		summaryRequests++
+		fmt.Fprint(w, `{"title": "Berlin", "extract": "Berlin ist die Hauptstadt.", "content_urls": {"desktop": {"page": "https://de.wikipedia.org/wiki/Berlin"}}}`)
+	}))
+	defer server.Close()
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	captureOutput(t, func() {
+		if code := run([]string{"-no-store", "Berlin"}); code != exitOK {
+			t.Errorf("Exit-Code = %d, erwartet %d", code, exitOK)
+		}
+		if code := run([]string{"-no-store", "Paris"}); code != exitOK {
+			t.Errorf("Exit-Code = %d, erwartet %d", code, exitOK)
+		}
+	})
+
+	if summaryRequests != 1 {
+		t.Errorf("Nur Berlin sollte abgerufen werden, Paris kommt aus dem Cache; %d Anfragen", summaryRequests)
+	}
+	if _, found := fileCache().Get("de", "Berlin"); found {
+		t.Error("Mit -no-store sollte Berlin nicht im Cache landen")
 	}
+	if noStore {
+		t.Error("noStore sollte nach run zurückgesetzt sein")
+	}
+}
 
-	if url == "" {
-		t.Error("Die URL sollte nicht leer sein")
+func TestRunClearExpired(t *testing.T) {
+	setTestHome(t)
+	fileCache().Save(wiki.Cache{
+		"de:Alt": wiki.CacheEntry{Summary: "Veraltet", ETag: `"1"`, Timestamp: time.Now().Add(-wiki.CacheDuration - time.Hour)},
+		"de:Neu": wiki.CacheEntry{Summary: "Aktuell", Timestamp: time.Now()},
+	})
+
+	var code int
+	stdout, _ := captureOutput(t, func() {
+		code = run([]string{"-clear-expired"})
+	})
+	if code != exitOK {
+		t.Fatalf("Exit-Code = %d, erwartet %d", code, exitOK)
+	}
+	if !strings.Contains(stdout, "Expired cache entries removed: 1") {
+		t.Errorf("Die Anzahl der entfernten Einträge sollte gemeldet werden: %q", stdout)
+	}
+	if _, found := fileCache().GetStale("de:Alt"); found {
+		t.Error("Der abgelaufene Eintrag sollte entfernt sein")
+	}
+	if _, found := fileCache().Get("de", "Neu"); !found {
+		t.Error("Der aktuelle Eintrag sollte erhalten bleiben")
 	}
+}
+
+func TestGetWikipediaSummaryFallsBackToStale(t *testing.T) {
+	setTestHome(t)
+	expired := time.Now().Add(-wiki.CacheDuration - time.Hour)
+	fileCache().Save(wiki.Cache{
+		"de:Berlin":     wiki.CacheEntry{Title: "Berlin", Summary: "Berlin ist die Hauptstadt.", ETag: `"1"`, Timestamp: expired},
+		"de:Hauptstadt": wiki.CacheEntry{RedirectTo: "Berlin", ETag: `"1"`, Timestamp: expired},
+	})
 
-	if cached {
-		t.Error("Der erste Aufruf sollte nicht aus dem Cache kommen")
+	// Der Server ist nicht erreichbar
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	for _, title := range []string{"Berlin", "Hauptstadt"} {
+		entry, cached, err := getWikipediaSummary(context.Background(), "de", title)
+		if err != nil {
+			t.Fatalf("%s: der abgelaufene Eintrag sollte gezeigt werden: %v", title, err)
+		}
+		if !cached || !entry.Stale || entry.Summary != "Berlin ist die Hauptstadt." {
+			t.Errorf("%s: erwartete den veralteten Eintrag, erhielt %+v (cached=%v)", title, entry, cached)
+		}
+	}
+	result := newResult("de", "Berlin", wiki.CacheEntry{Title: "Berlin", Stale: true, Timestamp: expired}, true)
+	if label := cachedLabel(result, time.Now()); !strings.HasPrefix(label, "(stale, offline") {
+		t.Errorf("Das Ergebnis sollte als veraltet markiert sein: %q", label)
 	}
 
-	// Zweiter Aufruf sollte aus dem Cache kommen
-	_, _, cached, _ = getWikipediaSummary("de", "Berlin")
-	if !cached {
-		t.Error("Der zweite Aufruf sollte aus dem Cache kommen")
+	if _, _, err := getWikipediaSummary(context.Background(), "de", "Paris"); err == nil {
+		t.Error("Ohne Eintrag im Cache sollte der Netzwerkfehler bleiben")
 	}
+}
 
-	// Lösche den Test-Eintrag aus dem Cache
-	cache := loadCache()
-	delete(cache, "de:Berlin")
-	saveCache(cache)
+func TestGetWikipediaSummaryNotFoundIsNotStale(t *testing.T) {
+	setTestHome(t)
+	expired := time.Now().Add(-wiki.CacheDuration - time.Hour)
+	fileCache().Save(wiki.Cache{
+		"de:Berlin": wiki.CacheEntry{Title: "Berlin", Summary: "Gelöscht.", ETag: `"1"`, Timestamp: expired},
+	})
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	wikiOptions = []wiki.Option{wiki.WithHost(server.URL + "/%s")}
+	defer func() { wikiOptions = nil }()
+
+	if _, _, err := getWikipediaSummary(context.Background(), "de", "Berlin"); !errors.Is(err, wiki.ErrArticleNotFound) {
+		t.Errorf("Ein gelöschter Artikel sollte nicht aus dem Cache kommen: %v", err)
+	}
 }
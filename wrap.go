@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+const (
+	defaultTerminalWidth = 80
+	// Longer lines are hard to read, even on wide terminals
+	maxSummaryWidth = 100
+)
+
+// terminalWidth returns the width of the terminal on stdout, or
+// defaultTerminalWidth if stdout is not a terminal.
+func terminalWidth() int {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 {
+		return defaultTerminalWidth
+	}
+	return width
+}
+
+// summaryWidth returns the width the summary is wrapped to. A positive
+// override wins, otherwise the terminal width capped at maxSummaryWidth.
+func summaryWidth(override int) int {
+	if override > 0 {
+		return override
+	}
+	width := terminalWidth()
+	if width > maxSummaryWidth {
+		return maxSummaryWidth
+	}
+	return width
+}
+
+// wrapText breaks text into lines of at most width characters. Line
+// breaks in the text are kept and words longer than width are split.
+func wrapText(text string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for utf8.RuneCountInString(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				runes := []rune(word)
+				lines = append(lines, string(runes[:width]))
+				word = string(runes[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWrapText(t *testing.T) {
+	text := "Berlin ist die Hauptstadt und ein Land der Bundesrepublik Deutschland."
+
+	tests := []struct {
+		width int
+		want  []string
+	}{
+		{80, []string{"Berlin ist die Hauptstadt und ein Land der Bundesrepublik Deutschland."}},
+		{30, []string{"Berlin ist die Hauptstadt und", "ein Land der Bundesrepublik", "Deutschland."}},
+		{15, []string{"Berlin ist die", "Hauptstadt und", "ein Land der", "Bundesrepublik", "Deutschland."}},
+		{10, []string{"Berlin ist", "die", "Hauptstadt", "und ein", "Land der", "Bundesrepu", "blik", "Deutschlan", "d."}},
+	}
+
+	for _, test := range tests {
+		got := wrapText(text, test.width)
+		if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("wrapText mit Breite %d: erwartete %q, erhielt %q", test.width, test.want, got)
+		}
+		for _, line := range got {
+			if utf8.RuneCountInString(line) > test.width {
+				t.Errorf("Die Zeile %q ist breiter als %d Zeichen", line, test.width)
+			}
+		}
+	}
+}
+
+func TestWrapTextKeepsParagraphs(t *testing.T) {
+	got := wrapText("Erster Absatz.\n\nZweiter Absatz.", 80)
+	want := []string{"Erster Absatz.", "", "Zweiter Absatz."}
+
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Erwartete %q, erhielt %q", want, got)
+	}
+}
+
+func TestWrapTextCountsRunes(t *testing.T) {
+	got := wrapText("Größenänderung über Flüsse", 15)
+	want := []string{"Größenänderung", "über Flüsse"}
+
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Umlaute sollten als ein Zeichen zählen: erwartete %q, erhielt %q", want, got)
+	}
+}
+
+func TestSummaryWidth(t *testing.T) {
+	if got := summaryWidth(42); got != 42 {
+		t.Errorf("Die Angabe von -width sollte gewinnen: erwartete 42, erhielt %d", got)
+	}
+
+	// Im Test ist stdout kein Terminal
+	if got := summaryWidth(0); got != defaultTerminalWidth {
+		t.Errorf("Ohne Terminal erwartete %d, erhielt %d", defaultTerminalWidth, got)
+	}
+}